@@ -2,47 +2,79 @@ package main
 
 import (
 	"context"
-	"flag"
+	"fmt"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/go-redis/redis/v7"
 	_ "github.com/joho/godotenv/autoload"
 
+	"github.com/Zucke/social_prove/internal/config"
 	"github.com/Zucke/social_prove/internal/db/mongo"
 	"github.com/Zucke/social_prove/internal/server"
+	accounteraser "github.com/Zucke/social_prove/pkg/account/eraser"
+	accountexporter "github.com/Zucke/social_prove/pkg/account/exporter"
+	accountrepository "github.com/Zucke/social_prove/pkg/account/repository"
+	auditrepository "github.com/Zucke/social_prove/pkg/audit/repository"
+	auditservice "github.com/Zucke/social_prove/pkg/audit/service"
 	"github.com/Zucke/social_prove/pkg/auth"
+	badgerepository "github.com/Zucke/social_prove/pkg/badge/repository"
+	badgeservice "github.com/Zucke/social_prove/pkg/badge/service"
+	bookmarkrepository "github.com/Zucke/social_prove/pkg/bookmark/repository"
+	commentrepository "github.com/Zucke/social_prove/pkg/comment/repository"
+	leaserepository "github.com/Zucke/social_prove/pkg/lease/repository"
+	"github.com/Zucke/social_prove/pkg/lifecycle"
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/picture/storage"
+	"github.com/Zucke/social_prove/pkg/picture/worker"
+	postrepository "github.com/Zucke/social_prove/pkg/post/repository"
+	"github.com/Zucke/social_prove/pkg/post/scheduler"
+	"github.com/Zucke/social_prove/pkg/ratelimit"
+	ratelimitrepository "github.com/Zucke/social_prove/pkg/ratelimit/repository"
+	reactionrepository "github.com/Zucke/social_prove/pkg/reaction/repository"
+	"github.com/Zucke/social_prove/pkg/retention"
+	"github.com/Zucke/social_prove/pkg/tracing"
+	userrepository "github.com/Zucke/social_prove/pkg/user/repository"
 )
 
 func main() {
-	var port string
-	if port = os.Getenv("PORT"); port == "" {
-		port = "8000"
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
 
-	var dbURL string
-	if dbURL = os.Getenv("DATABASE_URI"); dbURL == "" {
-		dbURL = "mongodb://127.0.0.1:27017"
+	log := logger.New("draid", !cfg.Debug)
+	if err := log.Levels().Apply(cfg.Log.Level, cfg.Log.Components); err != nil {
+		log.Error(err)
+		os.Exit(1)
 	}
 
-	debug := flag.Bool("debug", false, "Debug mode")
-	flag.Parse()
-
-	log := logger.New("draid", !*debug)
-
-	ctx := context.Background()
-	dbClient, err := mongo.NewClient(ctx, log, dbURL)
+	tracer, err := tracing.New(tracing.Options{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
 
-	err = dbClient.Start(ctx)
+	// The components start in the order they are added and close in the
+	// reverse one, the server first and the database last.
+	app := lifecycle.New(log.Named("lifecycle"))
+	app.Add("tracing", lifecycle.Hook{OnClose: tracer.Close})
+
+	ctx := context.Background()
+	dbClient, err := mongo.NewClient(ctx, log.Named("mongo"), cfg.Database.URI, cfg.Database.Name)
 	if err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
+	app.Add("mongo", dbClient)
 
 	var fa auth.Repository
 	// firebaseCredentialsPath := os.Getenv("FIREBASE_CREDENTIALS_PATH")
@@ -52,23 +84,152 @@ func main() {
 	// 	os.Exit(1)
 	// }
 
-	srv, err := server.New(port, *debug, dbClient, log, fa)
+	store := storage.Local(cfg.Media.Dir, cfg.Media.URL)
+	pictureWorker := worker.New(log.Named("picture"), store, cfg.Media.Limits, postrepository.Mongo(dbClient.Collection(mongo.PostCollection), log.Named("picture")), 2)
+	app.Add("pictures", pictureWorker)
+
+	postScheduler := scheduler.New(
+		log.Named("scheduler"),
+		postrepository.Mongo(dbClient.Collection(mongo.PostCollection), log.Named("scheduler")),
+		badgeservice.New(
+			dbClient.Collection(mongo.BadgeCollection),
+			dbClient.Collection(mongo.AwardCollection),
+			dbClient.Collection(mongo.PostCollection),
+			dbClient.Collection(mongo.TripCollection),
+			dbClient.Collection(mongo.UserCollection),
+			log.Named("badge"),
+			cfg.Services.Timeout,
+			auditservice.New(dbClient.Collection(mongo.AuditCollection), log.Named("audit"), cfg.Services.Timeout),
+		),
+		leaserepository.Mongo(dbClient.Collection(mongo.LeaseCollection), log.Named("scheduler")),
+		time.Minute,
+	)
+	app.Add("scheduler", postScheduler)
+
+	// The exports are downloaded through the API, they don't get a public URL.
+	archives := storage.Local(cfg.Exports.Dir, "")
+	repositoryLog := log.Named("repository")
+	users := userrepository.Mongo(dbClient.Collection(mongo.UserCollection), repositoryLog)
+	posts := postrepository.Mongo(dbClient.Collection(mongo.PostCollection), repositoryLog)
+	bookmarks := bookmarkrepository.Mongo(dbClient.Collection(mongo.BookmarkCollection), repositoryLog)
+	reactions := reactionrepository.Mongo(dbClient.Collection(mongo.ReactionCollection), repositoryLog)
+	comments := commentrepository.Mongo(dbClient.Collection(mongo.CommentCollection), repositoryLog)
+	badges := badgerepository.Mongo(dbClient.Collection(mongo.BadgeCollection), dbClient.Collection(mongo.AwardCollection), repositoryLog)
+	exports := accountrepository.Mongo(dbClient.Collection(mongo.ExportCollection), repositoryLog)
+
+	exporter := accountexporter.New(log.Named("exporter"), exports, users, posts, comments, reactions, bookmarks, badges, store, archives)
+	app.Add("exporter", exporter)
+
+	eraser := accounteraser.New(
+		log.Named("eraser"),
+		users,
+		userrepository.Suspensions(dbClient.Collection(mongo.SuspensionCollection), repositoryLog),
+		posts,
+		postrepository.Revisions(dbClient.Collection(mongo.RevisionCollection), repositoryLog),
+		bookmarks,
+		reactions,
+		comments,
+		badges,
+		exports,
+		auditrepository.Mongo(dbClient.Collection(mongo.AuditCollection), repositoryLog),
+		store,
+		archives,
+	)
+
+	erasures := accounteraser.NewWorker(log.Named("erasure"), eraser, cfg.Retention.EraseTimeout)
+	app.Add("erasures", erasures)
+
+	purger := retention.New(
+		log.Named("retention"),
+		users,
+		posts,
+		eraser,
+		leaserepository.Mongo(dbClient.Collection(mongo.LeaseCollection), repositoryLog),
+		time.Duration(cfg.Retention.Days)*24*time.Hour,
+		time.Hour,
+	)
+	app.Add("retention", purger)
+
+	// The rate limits are shared by the replicas unless they are in memory.
+	var limits ratelimit.Repository
+	if cfg.RateLimit.Enabled {
+		switch cfg.RateLimit.Store {
+		case ratelimit.Mongo:
+			limits = ratelimitrepository.Mongo(dbClient.Collection(mongo.RateLimitCollection), repositoryLog)
+		case ratelimit.Redis:
+			opts, err := redis.ParseURL(cfg.RateLimit.RedisURL)
+			if err != nil {
+				log.Error(err)
+				os.Exit(1)
+			}
+			redisClient := redis.NewClient(opts)
+			app.Add("redis", lifecycle.Hook{OnClose: func(ctx context.Context) error { return redisClient.Close() }})
+			limits = ratelimitrepository.Redis(redisClient, repositoryLog)
+		default:
+			limits = ratelimitrepository.Memory()
+		}
+	}
+
+	srv, err := server.New(cfg, dbClient, log, fa, store, pictureWorker, archives, exporter, erasures, limits)
 	if err != nil {
 		log.Error(err)
 		os.Exit(1)
 	}
 
-	// Start the server.
-	go srv.Start()
+	app.Add("server", srv)
 
-	// Wait for an interrupt.
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
-	<-c
+	if err := app.Start(ctx); err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+
+	// Reload the log levels on a hangup.
+	go reloadLogLevels(log)
+
+	// Wait for an interrupt, a termination or a failure of the server.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	code := 0
+	select {
+	case sig := <-stop:
+		log.Infof("received %s, shutting down", sig)
+	case err := <-srv.Err():
+		log.Error(err)
+		code = 1
+	}
+
+	// A second signal stops without waiting.
+	signal.Stop(stop)
 
-	// Attempt a graceful shutdown.
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-	dbClient.Close(ctx)
-	srv.Close(ctx)
+	ctx, cancel := context.WithTimeout(ctx, cfg.Server.ShutdownTimeout)
+	if err := app.Close(ctx); err != nil {
+		log.Error(err)
+		code = 1
+	}
+	cancel()
+
+	os.Exit(code)
+}
+
+// reloadLogLevels loads again the configuration on each SIGHUP and applies
+// its log levels, the levels changed through the API are replaced.
+func reloadLogLevels(log logger.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	for range hup {
+		cfg, err := config.Load(os.Args[1:])
+		if err != nil {
+			log.Errorf("cannot reload the log levels: %v", err)
+			continue
+		}
+
+		if err := log.Levels().Apply(cfg.Log.Level, cfg.Log.Components); err != nil {
+			log.Errorf("cannot reload the log levels: %v", err)
+			continue
+		}
+
+		log.With("default", cfg.Log.Level, "components", cfg.Log.Components).Info("log levels reloaded")
+	}
 }
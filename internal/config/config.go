@@ -0,0 +1,575 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/ratelimit"
+	"github.com/Zucke/social_prove/pkg/security"
+	"github.com/Zucke/social_prove/pkg/tracing"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+// Config is the configuration of the API. It's loaded from the defaults,
+// then a YAML file, then the environment and last the flags, each one
+// overriding the previous.
+type Config struct {
+	Debug     bool      `yaml:"debug"`
+	Server    Server    `yaml:"server"`
+	Database  Database  `yaml:"database"`
+	Auth      Auth      `yaml:"auth"`
+	Services  Services  `yaml:"services"`
+	Media     Media     `yaml:"media"`
+	Exports   Exports   `yaml:"exports"`
+	Retention Retention `yaml:"retention"`
+	Tracing   Tracing   `yaml:"tracing"`
+	Log       Log       `yaml:"log"`
+	RateLimit RateLimit `yaml:"rate_limit"`
+}
+
+// Server is the configuration of the HTTP server, ShutdownTimeout limits
+// the wait for the requests in progress and the background jobs on a
+// shutdown. HTTP2 is served over TLS, or in clear text without it.
+// MaxBodyBytes limits the bodies of the requests but the uploads of pictures.
+type Server struct {
+	Port            string           `yaml:"port"`
+	ReadTimeout     time.Duration    `yaml:"read_timeout"`
+	WriteTimeout    time.Duration    `yaml:"write_timeout"`
+	IdleTimeout     time.Duration    `yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration    `yaml:"shutdown_timeout"`
+	HTTP2           bool             `yaml:"http2"`
+	TLS             TLS              `yaml:"tls"`
+	CORS            CORS             `yaml:"cors"`
+	Headers         security.Headers `yaml:"headers"`
+	MaxBodyBytes    int64            `yaml:"max_body_bytes"`
+}
+
+// CORS are the cross-origin requests allowed, "*" allows any origin and
+// then can't be combined with AllowCredentials. The preflight requests are
+// cached for MaxAge.
+type CORS struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedMethods   []string      `yaml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+// TLS is served when CertFile and KeyFile are set, the certificate is
+// loaded again when its files change, checked each ReloadInterval. With
+// ClientCAFile the admins require a client certificate signed by one of its
+// CAs on the routes checking the roles.
+type TLS struct {
+	CertFile       string        `yaml:"cert_file"`
+	KeyFile        string        `yaml:"key_file"`
+	ClientCAFile   string        `yaml:"client_ca_file"`
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+// Enabled reports whether the server uses TLS.
+func (t TLS) Enabled() bool {
+	return t.CertFile != "" || t.KeyFile != ""
+}
+
+// Database is the configuration of MongoDB.
+type Database struct {
+	URI  string `yaml:"uri"`
+	Name string `yaml:"name"`
+}
+
+// Auth is the configuration of the tokens, SigningString signs them.
+type Auth struct {
+	SigningString string `yaml:"signing_string"`
+}
+
+// Services is the configuration of the services, Timeout limits each of
+// their operations.
+type Services struct {
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Media is where the pictures are stored and the URL they are served from,
+// by default the /media of the server. The pictures over Limits aren't
+// processed. The WebP variants require the cwebp binary of libwebp in the
+// PATH, without it only the JPEG variants are generated, which drop the
+// transparency.
+type Media struct {
+	Dir    string         `yaml:"dir"`
+	URL    string         `yaml:"url"`
+	Limits picture.Limits `yaml:"limits"`
+}
+
+// Exports is where the exports of the accounts are stored.
+type Exports struct {
+	Dir string `yaml:"dir"`
+}
+
+// Retention is the days the deleted users and posts are kept. The users
+// removing their account are erased at once in background, EraseTimeout
+// limits each attempt.
+type Retention struct {
+	Days         int           `yaml:"days"`
+	EraseTimeout time.Duration `yaml:"erase_timeout"`
+}
+
+// Tracing is the configuration of the traces, Exporter is none, stdout or
+// otlp, which sends them to the OTLP/HTTP Endpoint of a collector.
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Log is the level of the logs, empty is info in production and debug
+// otherwise, Components overrides it for the named loggers like http or
+// mongo.
+type Log struct {
+	Level      string            `yaml:"level"`
+	Components map[string]string `yaml:"components"`
+}
+
+// RateLimit is the token bucket of the requests of each user, or of each IP
+// without token. Store is memory, mongo or redis, the buckets in memory
+// aren't shared by the replicas. Routes override Default for a method and
+// route pattern, like "POST /api/v1/post/".
+type RateLimit struct {
+	Enabled  bool                       `yaml:"enabled"`
+	Store    string                     `yaml:"store"`
+	RedisURL string                     `yaml:"redis_url"`
+	Default  ratelimit.Limit            `yaml:"default"`
+	Routes   map[string]ratelimit.Limit `yaml:"routes"`
+}
+
+// Default returns the configuration without file, environment nor flags.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:            "8000",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+			HTTP2:           true,
+			TLS: TLS{
+				ReloadInterval: time.Minute,
+			},
+			CORS: CORS{
+				AllowedOrigins: []string{"*"},
+				AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
+				AllowedHeaders: []string{
+					"Accept",
+					"Authorization",
+					"Content-Type",
+					"X-CSRF-Token",
+					"X-Google-Token",
+					"X-Google-client",
+					"c-Control",
+				},
+				MaxAge: 5 * time.Minute,
+			},
+			Headers: security.Headers{
+				HSTSMaxAge:            180 * 24 * time.Hour,
+				FrameOptions:          security.Deny,
+				ContentSecurityPolicy: security.APIPolicy,
+			},
+			MaxBodyBytes: 1 << 20,
+		},
+		Database: Database{
+			URI:  "mongodb://127.0.0.1:27017",
+			Name: "draid",
+		},
+		Services: Services{
+			Timeout: 10 * time.Second,
+		},
+		Media: Media{
+			Dir: "media",
+			Limits: picture.Limits{
+				MaxBytes:  10 << 20,
+				MaxWidth:  8192,
+				MaxHeight: 8192,
+				MaxPixels: 40000000,
+			},
+		},
+		Exports: Exports{
+			Dir: "exports",
+		},
+		Retention: Retention{
+			Days:         30,
+			EraseTimeout: time.Minute,
+		},
+		Tracing: Tracing{
+			Exporter:    tracing.None,
+			Endpoint:    "http://localhost:4318/v1/traces",
+			ServiceName: "social",
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimit{
+			Enabled: true,
+			Store:   ratelimit.Memory,
+			Default: ratelimit.Limit{Requests: 300, Period: time.Minute},
+			Routes: map[string]ratelimit.Limit{
+				"POST /api/v1/user/":        {Requests: 20, Period: time.Hour, Burst: 5},
+				"POST /api/v1/login/":       {Requests: 10, Period: time.Minute},
+				"POST /api/v1/auth/google/": {Requests: 10, Period: time.Minute},
+				"POST /api/v1/post/":        {Requests: 60, Period: time.Hour, Burst: 10},
+			},
+		},
+	}
+}
+
+// Load returns the configuration for the command line args, the file is
+// the -config flag or CONFIG_FILE. A configuration that isn't valid, like
+// one without signing string, is an error.
+func Load(args []string) (Config, error) {
+	return load(args, os.LookupEnv)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool)) (Config, error) {
+	c := Default()
+
+	fs := flag.NewFlagSet("social", flag.ContinueOnError)
+	file := fs.String("config", "", "YAML configuration file")
+	debug := fs.Bool("debug", false, "Debug mode")
+	port := fs.String("port", "", "Port of the server")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	if *file == "" {
+		*file, _ = lookupEnv("CONFIG_FILE")
+	}
+
+	if *file != "" {
+		if err := c.readFile(*file); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := c.readEnv(lookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "debug":
+			c.Debug = *debug
+		case "port":
+			c.Server.Port = *port
+		}
+	})
+
+	if c.Media.URL == "" {
+		c.Media.URL = "http://localhost:" + c.Server.Port + "/media"
+	}
+
+	if err := c.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return c, nil
+}
+
+func (c *Config) readFile(name string) error {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("cannot read the configuration: %w", err)
+	}
+
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return fmt.Errorf("cannot parse the configuration %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (c *Config) readEnv(lookupEnv func(string) (string, bool)) error {
+	str := func(name string, dst *string) {
+		if v, ok := lookupEnv(name); ok && v != "" {
+			*dst = v
+		}
+	}
+
+	var errs []string
+	parse := func(name string, set func(v string) error) {
+		v, ok := lookupEnv(name)
+		if !ok || v == "" {
+			return
+		}
+		if err := set(v); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid %s %q", name, v))
+		}
+	}
+
+	str("PORT", &c.Server.Port)
+	str("TLS_CERT_FILE", &c.Server.TLS.CertFile)
+	str("TLS_KEY_FILE", &c.Server.TLS.KeyFile)
+	str("TLS_CLIENT_CA_FILE", &c.Server.TLS.ClientCAFile)
+	str("DATABASE_URI", &c.Database.URI)
+	str("DATABASE_NAME", &c.Database.Name)
+	str("SIGNING_STRING", &c.Auth.SigningString)
+	str("MEDIA_DIR", &c.Media.Dir)
+	str("MEDIA_URL", &c.Media.URL)
+	str("EXPORT_DIR", &c.Exports.Dir)
+	str("TRACING_EXPORTER", &c.Tracing.Exporter)
+	str("TRACING_ENDPOINT", &c.Tracing.Endpoint)
+	str("TRACING_SERVICE_NAME", &c.Tracing.ServiceName)
+	str("LOG_LEVEL", &c.Log.Level)
+	str("RATE_LIMIT_STORE", &c.RateLimit.Store)
+	str("RATE_LIMIT_REDIS_URL", &c.RateLimit.RedisURL)
+
+	parse("DEBUG", func(v string) (err error) {
+		c.Debug, err = strconv.ParseBool(v)
+		return err
+	})
+	parse("SERVICE_TIMEOUT", func(v string) (err error) {
+		c.Services.Timeout, err = time.ParseDuration(v)
+		return err
+	})
+	parse("HTTP2", func(v string) (err error) {
+		c.Server.HTTP2, err = strconv.ParseBool(v)
+		return err
+	})
+	parse("SHUTDOWN_TIMEOUT", func(v string) (err error) {
+		c.Server.ShutdownTimeout, err = time.ParseDuration(v)
+		return err
+	})
+	parse("RETENTION_DAYS", func(v string) (err error) {
+		c.Retention.Days, err = strconv.Atoi(v)
+		return err
+	})
+	parse("ERASE_TIMEOUT", func(v string) (err error) {
+		c.Retention.EraseTimeout, err = time.ParseDuration(v)
+		return err
+	})
+	parse("TRACING_SAMPLE_RATIO", func(v string) (err error) {
+		c.Tracing.SampleRatio, err = strconv.ParseFloat(v, 64)
+		return err
+	})
+	parse("RATE_LIMIT_ENABLED", func(v string) (err error) {
+		c.RateLimit.Enabled, err = strconv.ParseBool(v)
+		return err
+	})
+	parse("LOG_LEVELS", func(v string) error {
+		components := make(map[string]string)
+		for _, pair := range strings.Split(v, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+				return errors.New("expected component=level")
+			}
+			components[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+		c.Log.Components = components
+		return nil
+	})
+	parse("CORS_ALLOW_CREDENTIALS", func(v string) (err error) {
+		c.Server.CORS.AllowCredentials, err = strconv.ParseBool(v)
+		return err
+	})
+	parse("MAX_BODY_BYTES", func(v string) (err error) {
+		c.Server.MaxBodyBytes, err = strconv.ParseInt(v, 10, 64)
+		return err
+	})
+	parse("MEDIA_MAX_BYTES", func(v string) (err error) {
+		c.Media.Limits.MaxBytes, err = strconv.ParseInt(v, 10, 64)
+		return err
+	})
+	parse("MEDIA_MAX_PIXELS", func(v string) (err error) {
+		c.Media.Limits.MaxPixels, err = strconv.ParseInt(v, 10, 64)
+		return err
+	})
+	parse("HSTS_MAX_AGE", func(v string) (err error) {
+		c.Server.Headers.HSTSMaxAge, err = time.ParseDuration(v)
+		return err
+	})
+	list := func(name string, dst *[]string) {
+		parse(name, func(v string) error {
+			items := strings.Split(v, ",")
+			for i := range items {
+				items[i] = strings.TrimSpace(items[i])
+			}
+			*dst = items
+			return nil
+		})
+	}
+	list("CORS_ALLOWED_ORIGINS", &c.Server.CORS.AllowedOrigins)
+	list("CORS_ALLOWED_METHODS", &c.Server.CORS.AllowedMethods)
+	list("CORS_ALLOWED_HEADERS", &c.Server.CORS.AllowedHeaders)
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Validate check the configuration can start the API.
+func (c Config) Validate() error {
+	var v validation.Validator
+
+	if v.Required("server.port", c.Server.Port) {
+		if n, err := strconv.Atoi(c.Server.Port); err != nil || n < 1 || n > 65535 {
+			v.Add("server.port", validation.InvalidFormat, "must be a port number")
+		}
+	}
+
+	positive := func(field string, d time.Duration) {
+		if d <= 0 {
+			v.Add(field, validation.InvalidFormat, "must be a positive duration")
+		}
+	}
+	positive("server.read_timeout", c.Server.ReadTimeout)
+	positive("server.write_timeout", c.Server.WriteTimeout)
+	positive("server.idle_timeout", c.Server.IdleTimeout)
+	positive("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	positive("services.timeout", c.Services.Timeout)
+
+	if c.Server.TLS.Enabled() {
+		v.Required("server.tls.cert_file", c.Server.TLS.CertFile)
+		v.Required("server.tls.key_file", c.Server.TLS.KeyFile)
+		positive("server.tls.reload_interval", c.Server.TLS.ReloadInterval)
+	} else if c.Server.TLS.ClientCAFile != "" {
+		v.Add("server.tls.client_ca_file", validation.NotAllowed, "requires a certificate")
+	}
+
+	c.validateCORS(&v)
+
+	if c.Server.Headers.HSTSMaxAge < 0 {
+		v.Add("server.headers.hsts_max_age", validation.InvalidFormat, "cannot be negative")
+	}
+	if c.Server.Headers.FrameOptions != "" {
+		v.OneOf("server.headers.frame_options", c.Server.Headers.FrameOptions, security.Deny, security.SameOrigin)
+	}
+	if c.Server.MaxBodyBytes <= 0 {
+		v.Add("server.max_body_bytes", validation.InvalidFormat, "must be positive")
+	}
+
+	v.Required("database.uri", c.Database.URI)
+	v.Required("database.name", c.Database.Name)
+	v.Required("auth.signing_string", c.Auth.SigningString)
+	v.Required("media.dir", c.Media.Dir)
+	if c.Media.Limits.MaxBytes <= 0 {
+		v.Add("media.limits.max_bytes", validation.InvalidFormat, "must be positive")
+	}
+	if c.Media.Limits.MaxWidth <= 0 {
+		v.Add("media.limits.max_width", validation.InvalidFormat, "must be positive")
+	}
+	if c.Media.Limits.MaxHeight <= 0 {
+		v.Add("media.limits.max_height", validation.InvalidFormat, "must be positive")
+	}
+	if c.Media.Limits.MaxPixels <= 0 {
+		v.Add("media.limits.max_pixels", validation.InvalidFormat, "must be positive")
+	}
+	v.Required("exports.dir", c.Exports.Dir)
+
+	if c.Retention.Days < 0 {
+		v.Add("retention.days", validation.InvalidFormat, "cannot be negative")
+	}
+	positive("retention.erase_timeout", c.Retention.EraseTimeout)
+
+	if v.OneOf("tracing.exporter", c.Tracing.Exporter, tracing.None, tracing.Stdout, tracing.OTLP) && c.Tracing.Exporter == tracing.OTLP {
+		if v.Required("tracing.endpoint", c.Tracing.Endpoint) {
+			v.URL("tracing.endpoint", c.Tracing.Endpoint)
+		}
+	}
+	v.Required("tracing.service_name", c.Tracing.ServiceName)
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.Add("tracing.sample_ratio", validation.InvalidFormat, "must be between 0 and 1")
+	}
+
+	if c.Log.Level != "" {
+		v.OneOf("log.level", c.Log.Level, logger.LevelNames...)
+	}
+	names := make([]string, 0, len(c.Log.Components))
+	for name := range c.Log.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v.OneOf("log.components."+name, c.Log.Components[name], logger.LevelNames...)
+	}
+
+	if c.RateLimit.Enabled {
+		c.validateRateLimit(&v)
+	}
+
+	if err := v.Err(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return nil
+}
+
+func (c Config) validateCORS(v *validation.Validator) {
+	cors := c.Server.CORS
+	if len(cors.AllowedOrigins) == 0 {
+		v.Add("server.cors.allowed_origins", validation.Required, "is required")
+	}
+	for i, origin := range cors.AllowedOrigins {
+		field := fmt.Sprintf("server.cors.allowed_origins[%d]", i)
+		if origin == "*" {
+			if cors.AllowCredentials {
+				v.Add(field, validation.NotAllowed, "cannot be * with credentials")
+			}
+			continue
+		}
+		// The origins can have a wildcard, like https://*.example.com.
+		v.URL(field, strings.Replace(origin, "*", "x", 1))
+	}
+
+	if len(cors.AllowedMethods) == 0 {
+		v.Add("server.cors.allowed_methods", validation.Required, "is required")
+	}
+	for i, method := range cors.AllowedMethods {
+		v.OneOf(fmt.Sprintf("server.cors.allowed_methods[%d]", i), method,
+			http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions)
+	}
+
+	if cors.MaxAge < 0 {
+		v.Add("server.cors.max_age", validation.InvalidFormat, "cannot be negative")
+	}
+}
+
+func (c Config) validateRateLimit(v *validation.Validator) {
+	if v.OneOf("rate_limit.store", c.RateLimit.Store, ratelimit.Memory, ratelimit.Mongo, ratelimit.Redis) && c.RateLimit.Store == ratelimit.Redis {
+		v.Required("rate_limit.redis_url", c.RateLimit.RedisURL)
+	}
+
+	limit := func(field string, l ratelimit.Limit) {
+		if l.Requests <= 0 {
+			v.Add(field+".requests", validation.InvalidFormat, "must be positive")
+		}
+		if l.Period < ratelimit.MinPeriod {
+			v.Add(field+".period", validation.InvalidFormat, "must be at least "+ratelimit.MinPeriod.String())
+		}
+		if l.Burst < 0 {
+			v.Add(field+".burst", validation.InvalidFormat, "cannot be negative")
+		}
+	}
+	limit("rate_limit.default", c.RateLimit.Default)
+
+	routes := make([]string, 0, len(c.RateLimit.Routes))
+	for route := range c.RateLimit.Routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		field := "rate_limit.routes." + route
+		if parts := strings.Fields(route); len(parts) != 2 || !strings.HasPrefix(parts[1], "/") {
+			v.Add(field, validation.InvalidFormat, "must be a method and a route pattern")
+		}
+		limit(field, c.RateLimit.Routes[route])
+	}
+}
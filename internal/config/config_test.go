@@ -0,0 +1,310 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zucke/social_prove/pkg/ratelimit"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "config.yml")
+	err = ioutil.WriteFile(file, []byte("server:\n  port: \"9000\"\n  read_timeout: 5s\nauth:\n  signing_string: file\nservices:\n  timeout: 3s\nrate_limit:\n  routes:\n    POST /api/v1/post/:\n      requests: 5\n      period: 1m\n"), 0600)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		args  []string
+		env   map[string]string
+		check func(t *testing.T, c Config)
+		err   bool
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"SIGNING_STRING": "secret"},
+			check: func(t *testing.T, c Config) {
+				want := Default()
+				want.Auth.SigningString = "secret"
+				want.Media.URL = "http://localhost:8000/media"
+				assert.Equal(t, want, c)
+			},
+		},
+		{
+			name: "file",
+			args: []string{"-config", file},
+			check: func(t *testing.T, c Config) {
+				assert.Equal(t, "9000", c.Server.Port)
+				assert.Equal(t, 5*time.Second, c.Server.ReadTimeout)
+				assert.Equal(t, 10*time.Second, c.Server.WriteTimeout)
+				assert.Equal(t, 3*time.Second, c.Services.Timeout)
+				assert.Equal(t, "file", c.Auth.SigningString)
+				assert.Equal(t, "http://localhost:9000/media", c.Media.URL)
+				assert.Equal(t, ratelimit.Limit{Requests: 5, Period: time.Minute}, c.RateLimit.Routes["POST /api/v1/post/"])
+				assert.Equal(t, Default().RateLimit.Routes["POST /api/v1/login/"], c.RateLimit.Routes["POST /api/v1/login/"])
+			},
+		},
+		{
+			name: "env overrides the file",
+			env: map[string]string{
+				"CONFIG_FILE":          file,
+				"PORT":                 "9001",
+				"SIGNING_STRING":       "env",
+				"SERVICE_TIMEOUT":      "1s",
+				"SHUTDOWN_TIMEOUT":     "30s",
+				"CORS_ALLOWED_ORIGINS": "https://a.example.com, https://b.example.com",
+			},
+			check: func(t *testing.T, c Config) {
+				assert.Equal(t, "9001", c.Server.Port)
+				assert.Equal(t, "env", c.Auth.SigningString)
+				assert.Equal(t, time.Second, c.Services.Timeout)
+				assert.Equal(t, 30*time.Second, c.Server.ShutdownTimeout)
+				assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, c.Server.CORS.AllowedOrigins)
+			},
+		},
+		{
+			name: "flags override the env",
+			args: []string{"-port", "9002", "-debug"},
+			env:  map[string]string{"PORT": "9001", "DEBUG": "false", "SIGNING_STRING": "env"},
+			check: func(t *testing.T, c Config) {
+				assert.Equal(t, "9002", c.Server.Port)
+				assert.True(t, c.Debug)
+			},
+		},
+		{
+			name: "log levels",
+			env:  map[string]string{"SIGNING_STRING": "secret", "LOG_LEVEL": "warn", "LOG_LEVELS": "http=info, mongo=debug"},
+			check: func(t *testing.T, c Config) {
+				assert.Equal(t, "warn", c.Log.Level)
+				assert.Equal(t, map[string]string{"http": "info", "mongo": "debug"}, c.Log.Components)
+			},
+		},
+		{
+			name: "tls",
+			env:  map[string]string{"SIGNING_STRING": "secret", "TLS_CERT_FILE": "cert.pem", "TLS_KEY_FILE": "key.pem", "TLS_CLIENT_CA_FILE": "ca.pem", "HTTP2": "false"},
+			check: func(t *testing.T, c Config) {
+				assert.True(t, c.Server.TLS.Enabled())
+				assert.Equal(t, TLS{CertFile: "cert.pem", KeyFile: "key.pem", ClientCAFile: "ca.pem", ReloadInterval: time.Minute}, c.Server.TLS)
+				assert.False(t, c.Server.HTTP2)
+			},
+		},
+		{
+			name: "cors and headers",
+			env: map[string]string{
+				"SIGNING_STRING":         "secret",
+				"CORS_ALLOWED_ORIGINS":   "https://*.example.com",
+				"CORS_ALLOWED_METHODS":   "GET, POST",
+				"CORS_ALLOWED_HEADERS":   "Authorization,Content-Type",
+				"CORS_ALLOW_CREDENTIALS": "true",
+				"HSTS_MAX_AGE":           "0s",
+				"MAX_BODY_BYTES":         "4096",
+				"MEDIA_MAX_BYTES":        "2048",
+				"MEDIA_MAX_PIXELS":       "1000000",
+			},
+			check: func(t *testing.T, c Config) {
+				assert.Equal(t, CORS{
+					AllowedOrigins:   []string{"https://*.example.com"},
+					AllowedMethods:   []string{"GET", "POST"},
+					AllowedHeaders:   []string{"Authorization", "Content-Type"},
+					AllowCredentials: true,
+					MaxAge:           5 * time.Minute,
+				}, c.Server.CORS)
+				assert.Zero(t, c.Server.Headers.HSTSMaxAge)
+				assert.Equal(t, int64(4096), c.Server.MaxBodyBytes)
+				assert.Equal(t, int64(2048), c.Media.Limits.MaxBytes)
+				assert.Equal(t, int64(1000000), c.Media.Limits.MaxPixels)
+			},
+		},
+		{
+			name: "rate limit",
+			env:  map[string]string{"SIGNING_STRING": "secret", "RATE_LIMIT_STORE": "redis", "RATE_LIMIT_REDIS_URL": "redis://localhost:6379/0"},
+			check: func(t *testing.T, c Config) {
+				assert.True(t, c.RateLimit.Enabled)
+				assert.Equal(t, ratelimit.Redis, c.RateLimit.Store)
+				assert.Equal(t, "redis://localhost:6379/0", c.RateLimit.RedisURL)
+			},
+		},
+		{
+			name: "bad log levels",
+			env:  map[string]string{"SIGNING_STRING": "secret", "LOG_LEVELS": "http"},
+			err:  true,
+		},
+		{
+			name: "missing file",
+			args: []string{"-config", filepath.Join(dir, "missing.yml")},
+			env:  map[string]string{"SIGNING_STRING": "secret"},
+			err:  true,
+		},
+		{
+			name: "bad env",
+			env:  map[string]string{"SIGNING_STRING": "secret", "SERVICE_TIMEOUT": "soon", "RETENTION_DAYS": "a month"},
+			err:  true,
+		},
+		{
+			name: "unknown flag",
+			args: []string{"-verbose"},
+			env:  map[string]string{"SIGNING_STRING": "secret"},
+			err:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := load(test.args, env(test.env))
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			test.check(t, c)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(c *Config)
+		fields []string
+	}{
+		{
+			name:   "missing signing string",
+			config: func(c *Config) { c.Auth.SigningString = "" },
+			fields: []string{"auth.signing_string"},
+		},
+		{
+			name: "bad server",
+			config: func(c *Config) {
+				c.Server.Port = "http"
+				c.Server.IdleTimeout = 0
+				c.Server.CORS.AllowedOrigins = nil
+				c.Server.MaxBodyBytes = 0
+			},
+			fields: []string{"server.port", "server.idle_timeout", "server.cors.allowed_origins", "server.max_body_bytes"},
+		},
+		{
+			name: "bad cors",
+			config: func(c *Config) {
+				c.Server.CORS.AllowedOrigins = []string{"*", "example.com", "https://*.example.com"}
+				c.Server.CORS.AllowedMethods = []string{"GET", "get"}
+				c.Server.CORS.AllowCredentials = true
+				c.Server.Headers.FrameOptions = "ALLOW"
+			},
+			fields: []string{"server.cors.allowed_origins[0]", "server.cors.allowed_origins[1]", "server.cors.allowed_methods[1]", "server.headers.frame_options"},
+		},
+		{
+			name: "bad retention",
+			config: func(c *Config) {
+				c.Retention.Days = -1
+				c.Database.Name = ""
+			},
+			fields: []string{"database.name", "retention.days"},
+		},
+		{
+			name: "bad tracing",
+			config: func(c *Config) {
+				c.Tracing.Exporter = "otlp"
+				c.Tracing.Endpoint = "localhost:4318"
+				c.Tracing.SampleRatio = 2
+			},
+			fields: []string{"tracing.endpoint", "tracing.sample_ratio"},
+		},
+		{
+			name:   "unknown exporter",
+			config: func(c *Config) { c.Tracing.Exporter = "jaeger" },
+			fields: []string{"tracing.exporter"},
+		},
+		{
+			name: "bad log levels",
+			config: func(c *Config) {
+				c.Log.Level = "trace"
+				c.Log.Components = map[string]string{"mongo": "verbose", "http": "info"}
+			},
+			fields: []string{"log.level", "log.components.mongo"},
+		},
+		{
+			name: "bad media limits",
+			config: func(c *Config) {
+				c.Media.Limits.MaxBytes = 0
+				c.Media.Limits.MaxWidth = 0
+				c.Media.Limits.MaxPixels = -1
+			},
+			fields: []string{"media.limits.max_bytes", "media.limits.max_width", "media.limits.max_pixels"},
+		},
+		{
+			name: "bad tls",
+			config: func(c *Config) {
+				c.Server.TLS.CertFile = "cert.pem"
+				c.Server.TLS.ReloadInterval = 0
+			},
+			fields: []string{"server.tls.key_file", "server.tls.reload_interval"},
+		},
+		{
+			name:   "client ca without tls",
+			config: func(c *Config) { c.Server.TLS.ClientCAFile = "ca.pem" },
+			fields: []string{"server.tls.client_ca_file"},
+		},
+		{
+			name: "bad rate limit",
+			config: func(c *Config) {
+				c.RateLimit.Store = ratelimit.Redis
+				c.RateLimit.Default.Period = 0
+				c.RateLimit.Routes = map[string]ratelimit.Limit{"/api/v1/post/": {Requests: 1, Period: time.Second, Burst: -1}}
+			},
+			fields: []string{"rate_limit.redis_url", "rate_limit.default.period", "rate_limit.routes./api/v1/post/", "rate_limit.routes./api/v1/post/.burst"},
+		},
+		{
+			name: "rate limit period under a millisecond",
+			config: func(c *Config) {
+				c.RateLimit.Default.Period = time.Microsecond
+			},
+			fields: []string{"rate_limit.default.period"},
+		},
+		{
+			name: "rate limit disabled",
+			config: func(c *Config) {
+				c.RateLimit.Enabled = false
+				c.RateLimit.Store = "memcached"
+				c.Auth.SigningString = ""
+			},
+			fields: []string{"auth.signing_string"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := Default()
+			c.Auth.SigningString = "secret"
+			test.config(&c)
+
+			err := c.Validate()
+
+			var errs validation.Errors
+			assert.True(t, errors.As(err, &errs))
+
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			assert.Equal(t, test.fields, fields)
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// migrate update the stored documents to the current models.
+func (c *Client) migrate(ctx context.Context) error {
+	if err := c.migrateEmbeddedLikes(ctx); err != nil {
+		return err
+	}
+
+	if err := c.migrateLikeCollection(ctx); err != nil {
+		return err
+	}
+
+	if err := c.migrateBadgeNames(ctx); err != nil {
+		return err
+	}
+
+	if err := c.migratePictureURLs(ctx); err != nil {
+		return err
+	}
+
+	return c.migrateActive(ctx)
+}
+
+// migratePictureURLs turns the pictures stored as URLs into pictures ready
+// without variants.
+func (c *Client) migratePictureURLs(ctx context.Context) error {
+	posts := c.Client.Database(c.name).Collection(PostCollection)
+
+	_, err := posts.UpdateMany(
+		ctx,
+		bson.M{"pictures": bson.M{"$type": "string"}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{"pictures": bson.M{"$map": bson.M{
+				"input": "$pictures",
+				"as":    "picture",
+				"in": bson.M{"$cond": bson.A{
+					bson.M{"$eq": bson.A{bson.M{"$type": "$$picture"}, "string"}},
+					bson.M{"url": "$$picture", "status": "ready"},
+					"$$picture",
+				}},
+			}}}}},
+		},
+	)
+
+	return err
+}
+
+// migrateActive activates the users stored without the active flag, the
+// inactive users are rejected since they can be deactivated.
+func (c *Client) migrateActive(ctx context.Context) error {
+	users := c.Client.Database(c.name).Collection(UserCollection)
+
+	_, err := users.UpdateMany(
+		ctx,
+		bson.M{"active": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"active": true}},
+	)
+
+	return err
+}
+
+// migrateEmbeddedLikes moves the likes embedded on the posts to the
+// reactions collection, a post with a like that isn't a user ID fails the
+// migration and keeps its likes.
+func (c *Client) migrateEmbeddedLikes(ctx context.Context) error {
+	database := c.Client.Database(c.name)
+	posts := database.Collection(PostCollection)
+
+	cursor, err := posts.Find(ctx, bson.M{"likes": bson.M{"$exists": true}})
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		p := struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Likes []interface{}      `bson:"likes"`
+		}{}
+		if err := cursor.Decode(&p); err != nil {
+			return fmt.Errorf("cannot decode post likes: %w", err)
+		}
+
+		userIDs, err := likeUserIDs(p.Likes)
+		if err != nil {
+			return fmt.Errorf("post %s: %w", p.ID.Hex(), err)
+		}
+
+		if err := c.migratePostLikes(ctx, p.ID, userIDs, time.Now()); err != nil {
+			return err
+		}
+
+		if _, err := posts.UpdateOne(ctx, bson.M{"_id": p.ID}, bson.M{"$unset": bson.M{"likes": ""}}); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// likeUserIDs returns the users of the embedded likes, stored as ObjectIDs
+// or as their hex strings.
+func likeUserIDs(likes []interface{}) ([]primitive.ObjectID, error) {
+	userIDs := make([]primitive.ObjectID, 0, len(likes))
+	for _, like := range likes {
+		switch v := like.(type) {
+		case primitive.ObjectID:
+			userIDs = append(userIDs, v)
+		case string:
+			id, err := primitive.ObjectIDFromHex(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid like %q: %w", v, err)
+			}
+			userIDs = append(userIDs, id)
+		default:
+			return nil, fmt.Errorf("invalid like of type %T", like)
+		}
+	}
+
+	return userIDs, nil
+}
+
+// migrateLikeCollection moves the documents of the likes collection to the reactions collection.
+func (c *Client) migrateLikeCollection(ctx context.Context) error {
+	database := c.Client.Database(c.name)
+	likes := database.Collection(likeCollection)
+	posts := database.Collection(PostCollection)
+
+	cursor, err := likes.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+
+	defer cursor.Close(ctx)
+
+	postIDs := make(map[primitive.ObjectID]bool)
+	for cursor.Next(ctx) {
+		l := struct {
+			PostID    primitive.ObjectID `bson:"post_id"`
+			UserID    primitive.ObjectID `bson:"user_id"`
+			CreatedAt time.Time          `bson:"created_at"`
+		}{}
+		if err := cursor.Decode(&l); err != nil {
+			return fmt.Errorf("cannot decode like: %w", err)
+		}
+
+		if err := c.migratePostLikes(ctx, l.PostID, []primitive.ObjectID{l.UserID}, l.CreatedAt); err != nil {
+			return err
+		}
+		postIDs[l.PostID] = true
+	}
+
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	for id := range postIDs {
+		if _, err := posts.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$unset": bson.M{"likes_count": ""}}); err != nil {
+			return err
+		}
+	}
+
+	if len(postIDs) == 0 {
+		return nil
+	}
+
+	return likes.Drop(ctx)
+}
+
+// migratePostLikes stores the likes of the users as reactions and
+// recount the likes of the post.
+func (c *Client) migratePostLikes(ctx context.Context, postID primitive.ObjectID, userIDs []primitive.ObjectID, createdAt time.Time) error {
+	database := c.Client.Database(c.name)
+	posts := database.Collection(PostCollection)
+	reactions := database.Collection(ReactionCollection)
+
+	models := make([]mongo.WriteModel, 0, len(userIDs))
+	for _, userID := range userIDs {
+		filter := bson.M{
+			"target":    "post",
+			"target_id": postID,
+			"user_id":   userID,
+		}
+		update := bson.M{
+			"$setOnInsert": bson.M{
+				"type":       "like",
+				"created_at": createdAt,
+				"updated_at": createdAt,
+			},
+		}
+		models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	if len(models) > 0 {
+		_, err := reactions.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return err
+		}
+	}
+
+	count, err := reactions.CountDocuments(ctx, bson.M{"target": "post", "target_id": postID, "type": "like"})
+	if err != nil {
+		return err
+	}
+
+	_, err = posts.UpdateOne(ctx, bson.M{"_id": postID}, bson.M{"$set": bson.M{"reactions.like": count}})
+
+	return err
+}
+
+// migrateBadgeNames moves the free text badges of the posts to the badge
+// catalog and references them by ID.
+func (c *Client) migrateBadgeNames(ctx context.Context) error {
+	database := c.Client.Database(c.name)
+	posts := database.Collection(PostCollection)
+	badges := database.Collection(BadgeCollection)
+
+	filter := bson.M{"badge": bson.M{"$type": "string"}}
+	names, err := posts.Distinct(ctx, "badge", filter)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		name, ok := name.(string)
+		if !ok {
+			continue
+		}
+
+		if name == "" {
+			if _, err := posts.UpdateMany(ctx, bson.M{"badge": name}, bson.M{"$unset": bson.M{"badge": ""}}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		now := time.Now()
+		update := bson.M{
+			"$setOnInsert": bson.M{
+				"kind":       "post",
+				"created_at": now,
+				"updated_at": now,
+			},
+		}
+		opts := options.FindOneAndUpdate().
+			SetUpsert(true).
+			SetReturnDocument(options.After)
+
+		b := struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}{}
+		if err := badges.FindOneAndUpdate(ctx, bson.M{"name": name}, update, opts).Decode(&b); err != nil {
+			return err
+		}
+
+		_, err := posts.UpdateMany(ctx,
+			bson.M{"badge": name},
+			bson.M{
+				"$set":   bson.M{"badge_id": b.ID},
+				"$unset": bson.M{"badge": ""},
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
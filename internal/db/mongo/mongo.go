@@ -3,27 +3,44 @@ package mongo
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"github.com/Zucke/social_prove/pkg/logger"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.mongodb.org/mongo-driver/x/bsonx"
 )
 
 type Client struct {
 	*mongo.Client
-	log logger.Logger
-}
+	name string
+	log  logger.Logger
 
-// DBName Database name.
-const DBName = "draid"
+	// started is 1 once the indexes and the migrations are done.
+	started int32
+}
 
 // Collections.
 const (
-	UserCollection = "users"
-	PostCollection = "posts"
-	TripCollection = "trips"
+	UserCollection       = "users"
+	PostCollection       = "posts"
+	TripCollection       = "trips"
+	ReactionCollection   = "reactions"
+	CommentCollection    = "comments"
+	BookmarkCollection   = "bookmarks"
+	BadgeCollection      = "badges"
+	AwardCollection      = "awards"
+	LeaseCollection      = "leases"
+	RevisionCollection   = "revisions"
+	SuspensionCollection = "suspensions"
+	ExportCollection     = "exports"
+	AuditCollection      = "audit"
+	RateLimitCollection  = "ratelimits"
+
+	// likeCollection stored the likes before the reactions.
+	likeCollection = "likes"
 )
 
 // Errors.
@@ -34,6 +51,7 @@ var (
 	ErrCouldNotDelete  = errors.New("could not delete")
 	ErrCouldNotFound   = errors.New("could not found")
 	ErrCouldNotParseID = errors.New("could not parse id")
+	ErrNotStarted      = errors.New("indexes and migrations not done")
 )
 
 // Close disconnect the database.
@@ -43,7 +61,7 @@ func (c *Client) Close(ctx context.Context) error {
 
 // Collection returns a MongoDB's collection from a name.
 func (c *Client) Collection(name string) *mongo.Collection {
-	return c.Client.Database(DBName).Collection(name)
+	return c.Client.Database(c.name).Collection(name)
 }
 
 // Start initialize the mongo client and set de index.
@@ -53,6 +71,27 @@ func (c *Client) Start(ctx context.Context) error {
 		return err
 	}
 
+	if err := c.migrate(ctx); err != nil {
+		c.log.Errorf("cannot migrate: %v", err)
+		return err
+	}
+
+	atomic.StoreInt32(&c.started, 1)
+
+	return nil
+}
+
+// Ping check the primary of the database answers.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.Client.Ping(ctx, readpref.Primary())
+}
+
+// Started check Start created the indexes and migrated the documents.
+func (c *Client) Started(ctx context.Context) error {
+	if atomic.LoadInt32(&c.started) == 0 {
+		return ErrNotStarted
+	}
+
 	return nil
 }
 
@@ -61,7 +100,7 @@ func (c *Client) indexes(ctx context.Context) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	database := c.Client.Database(DBName)
+	database := c.Client.Database(c.name)
 	indexOpts := options.CreateIndexes().SetMaxTime(time.Second * 10)
 
 	// User Indexes.
@@ -85,6 +124,12 @@ func (c *Client) indexes(ctx context.Context) error {
 		Keys:    bsonx.MDoc{"location": bsonx.String("2dsphere")},
 	}
 
+	// Only the deleted documents have deleted_at, for the purges.
+	deletedAtIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true).SetSparse(true),
+		Keys:    bsonx.MDoc{"deleted_at": bsonx.Int32(1)},
+	}
+
 	userIndexes := database.Collection(UserCollection).Indexes()
 	_, err := userIndexes.CreateMany(
 		ctx,
@@ -92,6 +137,7 @@ func (c *Client) indexes(ctx context.Context) error {
 			userEmailIndexModel,
 			userRoleIndexModel,
 			userUIDIndexModel,
+			deletedAtIndexModel,
 		},
 		indexOpts,
 	)
@@ -112,9 +158,188 @@ func (c *Client) indexes(ctx context.Context) error {
 	}
 
 	// Post indexes.
+	repostOfIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true).SetSparse(true),
+		Keys:    bsonx.MDoc{"repost_of": bsonx.Int32(1)},
+	}
+
+	scheduledIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true).SetSparse(true),
+		Keys: bsonx.Doc{
+			{Key: "status", Value: bsonx.Int32(1)},
+			{Key: "publish_at", Value: bsonx.Int32(1)},
+		},
+	}
 
 	postIndexes := database.Collection(PostCollection).Indexes()
-	_, err = postIndexes.CreateMany(ctx, []mongo.IndexModel{userIDIndexModel, geolocationIndexModel}, indexOpts)
+	_, err = postIndexes.CreateMany(ctx, []mongo.IndexModel{userIDIndexModel, geolocationIndexModel, repostOfIndexModel, scheduledIndexModel, deletedAtIndexModel}, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	// Reaction indexes.
+	reactionTargetUserIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true).SetUnique(true),
+		Keys: bsonx.Doc{
+			{Key: "target", Value: bsonx.Int32(1)},
+			{Key: "target_id", Value: bsonx.Int32(1)},
+			{Key: "user_id", Value: bsonx.Int32(1)},
+		},
+	}
+
+	reactionIndexes := database.Collection(ReactionCollection).Indexes()
+	_, err = reactionIndexes.CreateMany(ctx, []mongo.IndexModel{reactionTargetUserIndexModel, userIDIndexModel}, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	// Comment indexes.
+	commentPostIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true),
+		Keys: bsonx.Doc{
+			{Key: "post_id", Value: bsonx.Int32(1)},
+			{Key: "created_at", Value: bsonx.Int32(1)},
+		},
+	}
+
+	commentIndexes := database.Collection(CommentCollection).Indexes()
+	_, err = commentIndexes.CreateMany(ctx, []mongo.IndexModel{commentPostIndexModel, userIDIndexModel}, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	// Revision indexes.
+	revisionPostIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true),
+		Keys: bsonx.Doc{
+			{Key: "post_id", Value: bsonx.Int32(1)},
+			{Key: "created_at", Value: bsonx.Int32(-1)},
+		},
+	}
+
+	revisionIndexes := database.Collection(RevisionCollection).Indexes()
+	_, err = revisionIndexes.CreateOne(ctx, revisionPostIndexModel, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	// Suspension indexes.
+	suspensionUserIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true),
+		Keys: bsonx.Doc{
+			{Key: "user_id", Value: bsonx.Int32(1)},
+			{Key: "created_at", Value: bsonx.Int32(-1)},
+		},
+	}
+
+	suspensionIndexes := database.Collection(SuspensionCollection).Indexes()
+	_, err = suspensionIndexes.CreateOne(ctx, suspensionUserIndexModel, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	// Export indexes.
+	exportUserIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true),
+		Keys: bsonx.Doc{
+			{Key: "user_id", Value: bsonx.Int32(1)},
+			{Key: "created_at", Value: bsonx.Int32(-1)},
+		},
+	}
+
+	exportStatusIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true),
+		Keys:    bsonx.MDoc{"status": bsonx.Int32(1)},
+	}
+
+	exportIndexes := database.Collection(ExportCollection).Indexes()
+	_, err = exportIndexes.CreateMany(ctx, []mongo.IndexModel{exportUserIndexModel, exportStatusIndexModel}, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	// Audit indexes.
+	auditCreatedAtIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true),
+		Keys:    bsonx.MDoc{"created_at": bsonx.Int32(-1)},
+	}
+
+	auditActorIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true),
+		Keys: bsonx.Doc{
+			{Key: "actor_id", Value: bsonx.Int32(1)},
+			{Key: "created_at", Value: bsonx.Int32(-1)},
+		},
+	}
+
+	auditTargetIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true),
+		Keys: bsonx.Doc{
+			{Key: "target_id", Value: bsonx.Int32(1)},
+			{Key: "created_at", Value: bsonx.Int32(-1)},
+		},
+	}
+
+	auditIndexes := database.Collection(AuditCollection).Indexes()
+	_, err = auditIndexes.CreateMany(ctx, []mongo.IndexModel{auditCreatedAtIndexModel, auditActorIndexModel, auditTargetIndexModel}, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	// Bookmark indexes.
+	bookmarkUserNameIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true).SetUnique(true),
+		Keys: bsonx.Doc{
+			{Key: "user_id", Value: bsonx.Int32(1)},
+			{Key: "name", Value: bsonx.Int32(1)},
+		},
+	}
+
+	bookmarkPostIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true),
+		Keys:    bsonx.MDoc{"items.post_id": bsonx.Int32(1)},
+	}
+
+	bookmarkIndexes := database.Collection(BookmarkCollection).Indexes()
+	_, err = bookmarkIndexes.CreateMany(ctx, []mongo.IndexModel{bookmarkUserNameIndexModel, bookmarkPostIndexModel}, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	// Badge indexes.
+	badgeNameIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true).SetUnique(true),
+		Keys:    bsonx.MDoc{"name": bsonx.Int32(1)},
+	}
+
+	badgeIndexes := database.Collection(BadgeCollection).Indexes()
+	_, err = badgeIndexes.CreateOne(ctx, badgeNameIndexModel, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	awardUserBadgeIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true).SetUnique(true),
+		Keys: bsonx.Doc{
+			{Key: "user_id", Value: bsonx.Int32(1)},
+			{Key: "badge_id", Value: bsonx.Int32(1)},
+		},
+	}
+
+	awardIndexes := database.Collection(AwardCollection).Indexes()
+	_, err = awardIndexes.CreateOne(ctx, awardUserBadgeIndexModel, indexOpts)
+	if err != nil {
+		return err
+	}
+
+	// Rate limit indexes, the buckets are dropped once full.
+	rateLimitExpiresAtIndexModel := mongo.IndexModel{
+		Options: options.Index().SetBackground(true).SetExpireAfterSeconds(0),
+		Keys:    bsonx.MDoc{"expires_at": bsonx.Int32(1)},
+	}
+
+	rateLimitIndexes := database.Collection(RateLimitCollection).Indexes()
+	_, err = rateLimitIndexes.CreateOne(ctx, rateLimitExpiresAtIndexModel, indexOpts)
 	if err != nil {
 		return err
 	}
@@ -122,14 +347,14 @@ func (c *Client) indexes(ctx context.Context) error {
 	return nil
 }
 
-// NewClient returns a new client for mongo.
-func NewClient(ctx context.Context, log logger.Logger, source string) (*Client, error) {
+// NewClient returns a new client for mongo, name is the database.
+func NewClient(ctx context.Context, log logger.Logger, source, name string) (*Client, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(source))
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(source).SetMonitor(monitor()))
 	if err != nil {
 		log.Errorf("cannot create mongodb connection: %v", err)
 		return nil, err
 	}
-	return &Client{Client: client, log: log}, nil
+	return &Client{Client: client, name: name, log: log}, nil
 }
@@ -0,0 +1,66 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Zucke/social_prove/pkg/metrics"
+	"github.com/Zucke/social_prove/pkg/tracing"
+)
+
+// monitor measures the latency of the commands and traces each one as a
+// child of the span of its context.
+func monitor() *event.CommandMonitor {
+	var (
+		mu    sync.Mutex
+		spans = map[int64]trace.Span{}
+	)
+
+	end := func(requestID int64, name, status string, nanos int64, failure string) {
+		metrics.MongoDuration.WithLabelValues(name, status).Observe(time.Duration(nanos).Seconds())
+
+		mu.Lock()
+		span, ok := spans[requestID]
+		delete(spans, requestID)
+		mu.Unlock()
+
+		if !ok {
+			return
+		}
+		if failure != "" {
+			span.SetStatus(codes.Error, failure)
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			_, span := tracing.Start(
+				ctx,
+				"mongo."+e.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemMongoDB,
+					semconv.DBNameKey.String(e.DatabaseName),
+					semconv.DBOperationKey.String(e.CommandName),
+				),
+			)
+
+			mu.Lock()
+			spans[e.RequestID] = span
+			mu.Unlock()
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			end(e.RequestID, e.CommandName, "succeeded", e.DurationNanos, "")
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			end(e.RequestID, e.CommandName, "failed", e.DurationNanos, e.Failure)
+		},
+	}
+}
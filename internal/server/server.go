@@ -2,105 +2,235 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"net"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/cors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
+	"github.com/Zucke/social_prove/internal/config"
 	"github.com/Zucke/social_prove/internal/db/mongo"
 	v1 "github.com/Zucke/social_prove/internal/server/v1"
+	"github.com/Zucke/social_prove/pkg/account"
+	"github.com/Zucke/social_prove/pkg/audit"
 	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/health"
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/metrics"
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/ratelimit"
+	"github.com/Zucke/social_prove/pkg/security"
+	"github.com/Zucke/social_prove/pkg/tracing"
+	userrepository "github.com/Zucke/social_prove/pkg/user/repository"
 )
 
+var errFirebaseNotConfigured = errors.New("firebase client not configured")
+
+// docsPolicy is the content security policy of the Swagger UI of /docs,
+// its page has inline scripts and styles.
+const docsPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+
 // Server is a base server configuration.
 type Server struct {
 	server *http.Server
 	log    logger.Logger
-	port   string
-	debug  bool
+	cfg    config.Config
+
+	listener net.Listener
+	errs     chan error
 }
 
-func (serv *Server) getRoutes(client *mongo.Client, fa auth.Repository) (http.Handler, error) {
+func (serv *Server) getRoutes(client *mongo.Client, fa auth.Repository, storage picture.Storage, queue picture.Queue, archives picture.Storage, exports account.Queue, erasures account.ErasureQueue, limits ratelimit.Repository) (http.Handler, error) {
 	cors := cors.New(cors.Options{
-		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{
-			"GET",
-			"POST",
-			"PUT",
-			"DELETE",
-			"OPTIONS",
-			"PATCH",
+		AllowedOrigins: serv.cfg.Server.CORS.AllowedOrigins,
+		AllowedMethods: serv.cfg.Server.CORS.AllowedMethods,
+		AllowedHeaders: serv.cfg.Server.CORS.AllowedHeaders,
+		ExposedHeaders: []string{
+			"X-RateLimit-Limit",
+			"X-RateLimit-Remaining",
+			"X-RateLimit-Reset",
+			"Retry-After",
 		},
-		AllowedHeaders: []string{
-			"Accept",
-			"Authorization",
-			"Content-Type",
-			"X-CSRF-Token",
-			"X-Google-Token",
-			"X-Google-client",
-			"c-Control",
-		},
-		AllowCredentials: true,
-		MaxAge:           300,
+		AllowCredentials: serv.cfg.Server.CORS.AllowCredentials,
+		MaxAge:           int(serv.cfg.Server.CORS.MaxAge / time.Second),
 	})
 
 	r := chi.NewRouter()
+	r.Use(tracing.Middleware)
+	r.Use(metrics.Middleware)
+	r.Use(serv.cfg.Server.Headers.Middleware)
 	r.Use(cors.Handler)
+	r.Use(security.LimitBody(serv.cfg.Server.MaxBodyBytes))
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	r.Use(audit.WithIP)
+	r.Use(logger.Middleware(serv.log.Named("http")))
 	r.Use(middleware.Recoverer)
 
-	v1Routes, err := v1.New(serv.log, client, fa)
+	// The tokens of the deleted, deactivated or suspended users are rejected,
+	// with a client CA the admins require a client certificate.
+	authenticator := auth.New(
+		serv.cfg.Auth.SigningString,
+		userrepository.Mongo(client.Collection(mongo.UserCollection), serv.log.Named("auth")),
+		serv.cfg.Server.TLS.ClientCAFile != "",
+	)
+
+	v1Routes, err := v1.New(serv.cfg, serv.log, client, authenticator, fa, storage, queue, archives, exports, erasures, limits)
 	if err != nil {
 		return nil, err
 	}
 
 	r.Mount("/api/v1", v1Routes)
-	r.Handle(
+
+	checker := health.New()
+	checker.Add("mongo", client.Ping)
+	checker.Add("indexes", client.Started)
+	checker.Add("auth", authenticator.Configured)
+	if fa != nil {
+		checker.Add("firebase", func(ctx context.Context) error {
+			if fa.GetFirebaseClient() == nil {
+				return errFirebaseNotConfigured
+			}
+			return nil
+		})
+	}
+	r.Get("/healthz", checker.Liveness)
+	r.Get("/readyz", checker.Readiness)
+	r.Handle("/metrics", metrics.Handler())
+	r.With(security.ContentSecurityPolicy(docsPolicy)).Handle(
 		"/docs/*",
 		http.StripPrefix("/docs/", http.FileServer(http.Dir("docs"))),
 	)
 
+	if media, ok := storage.(http.Handler); ok {
+		r.Handle("/media/*", http.StripPrefix("/media/", media))
+	}
+
 	return r, nil
 }
 
-// New initialize a new server with configuration.
-func New(port string, debug bool, client *mongo.Client, log logger.Logger, fa auth.Repository) (*Server, error) {
+// New initialize a new server with configuration. The exports are kept in
+// archives, which is never served as is, and the rate limits in limits,
+// nil when they are disabled.
+func New(
+	cfg config.Config,
+	client *mongo.Client,
+	log logger.Logger,
+	fa auth.Repository,
+	storage picture.Storage,
+	queue picture.Queue,
+	archives picture.Storage,
+	exports account.Queue,
+	erasures account.ErasureQueue,
+	limits ratelimit.Repository,
+) (*Server, error) {
 	serv := &Server{
-		port:  port,
-		debug: debug,
-		log:   log,
+		cfg:  cfg,
+		log:  log,
+		errs: make(chan error, 1),
 	}
 
-	r, err := serv.getRoutes(client, fa)
+	r, err := serv.getRoutes(client, fa, storage, queue, archives, exports, erasures, limits)
 	if err != nil {
 		return nil, err
 	}
 
-	serv.server = &http.Server{
-		Addr:         ":" + port,
-		Handler:      r,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		IdleTimeout:  120 * time.Second,
+	if err := serv.configure(r); err != nil {
+		return nil, err
 	}
 
 	return serv, nil
 }
 
-// Close server resources.
-func (serv *Server) Close(ctx context.Context) error {
+// configure the HTTP server of h, over TLS when it has a certificate.
+func (serv *Server) configure(h http.Handler) error {
+	if serv.cfg.Server.HTTP2 && !serv.cfg.Server.TLS.Enabled() {
+		// HTTP/2 in clear text, for the proxies that speak it to the API.
+		h = h2c.NewHandler(h, &http2.Server{IdleTimeout: serv.cfg.Server.IdleTimeout})
+	}
+
+	serv.server = &http.Server{
+		Addr:         ":" + serv.cfg.Server.Port,
+		Handler:      h,
+		ReadTimeout:  serv.cfg.Server.ReadTimeout,
+		WriteTimeout: serv.cfg.Server.WriteTimeout,
+		IdleTimeout:  serv.cfg.Server.IdleTimeout,
+	}
+
+	if serv.cfg.Server.TLS.Enabled() {
+		var err error
+		serv.server.TLSConfig, err = tlsConfig(serv.cfg.Server.TLS, serv.log.Named("tls"))
+		if err != nil {
+			return err
+		}
+
+		if !serv.cfg.Server.HTTP2 {
+			// An empty TLSNextProto disables HTTP/2.
+			serv.server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		}
+	}
+
 	return nil
 }
 
-// Start the server.
-func (serv *Server) Start() {
-	serv.log.Infof("Server running on http://localhost:%s", serv.port)
-	serv.log.Error(serv.server.ListenAndServe())
-	os.Exit(1)
+// Start listen on the port and serve in the background, Err reports the
+// failures of the server once it is serving.
+func (serv *Server) Start(ctx context.Context) error {
+	l, err := net.Listen("tcp", serv.server.Addr)
+	if err != nil {
+		return err
+	}
+	serv.listener = l
+
+	scheme := "http"
+	if serv.server.TLSConfig != nil {
+		scheme = "https"
+	}
+
+	go func() {
+		defer close(serv.errs)
+
+		var err error
+		if serv.server.TLSConfig != nil {
+			// The certificate is in TLSConfig.
+			err = serv.server.ServeTLS(l, "", "")
+		} else {
+			err = serv.server.Serve(l)
+		}
+
+		if !errors.Is(err, http.ErrServerClosed) {
+			serv.errs <- err
+		}
+	}()
+
+	serv.log.Infof("Server running on %s://%s", scheme, l.Addr())
+	return nil
+}
+
+// Err receives the error that stopped the server, it is closed without
+// error once the server is closed.
+func (serv *Server) Err() <-chan error {
+	return serv.errs
+}
+
+// Addr returns the address the server listens on, nil until Start.
+func (serv *Server) Addr() net.Addr {
+	if serv.listener == nil {
+		return nil
+	}
+
+	return serv.listener.Addr()
+}
+
+// Close stop accepting connections and wait for the requests in progress
+// until ctx is done.
+func (serv *Server) Close(ctx context.Context) error {
+	serv.log.Info("Server shutting down")
+	return serv.server.Shutdown(ctx)
 }
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+)
+
+func TestServer_Lifecycle(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	})
+
+	serv := &Server{
+		server: &http.Server{Addr: "127.0.0.1:0", Handler: handler},
+		log:    logger.NewMock(),
+		errs:   make(chan error, 1),
+	}
+	assert.Nil(t, serv.Addr())
+
+	ctx := context.Background()
+	assert.NoError(t, serv.Start(ctx))
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		res, err := http.Get("http://" + serv.Addr().String() + "/")
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer res.Body.Close()
+
+		b, err := ioutil.ReadAll(res.Body)
+		responses <- result{string(b), err}
+	}()
+	<-started
+
+	// The request in progress is waited for.
+	closed := make(chan error, 1)
+	go func() { closed <- serv.Close(ctx) }()
+
+	select {
+	case <-closed:
+		t.Fatal("closed before the request finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-closed)
+
+	res := <-responses
+	assert.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+
+	// The server is closed without error and no longer accepts requests.
+	err, ok := <-serv.Err()
+	assert.False(t, ok)
+	assert.NoError(t, err)
+
+	_, err = http.Get("http://" + serv.Addr().String() + "/")
+	assert.Error(t, err)
+}
+
+func TestServer_CloseTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	started := make(chan struct{})
+	serv := &Server{
+		server: &http.Server{Addr: "127.0.0.1:0", Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		})},
+		log:  logger.NewMock(),
+		errs: make(chan error, 1),
+	}
+	assert.NoError(t, serv.Start(context.Background()))
+
+	go func() {
+		res, err := http.Get("http://" + serv.Addr().String() + "/")
+		if err == nil {
+			res.Body.Close()
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, serv.Close(ctx))
+}
+
+func TestServer_StartError(t *testing.T) {
+	serv := &Server{
+		server: &http.Server{Addr: "127.0.0.1:-1"},
+		log:    logger.NewMock(),
+		errs:   make(chan error, 1),
+	}
+
+	assert.Error(t, serv.Start(context.Background()))
+}
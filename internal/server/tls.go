@@ -0,0 +1,142 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Zucke/social_prove/internal/config"
+	"github.com/Zucke/social_prove/pkg/logger"
+)
+
+var errNoClientCA = errors.New("no CA certificate in the client CA file")
+
+// certificate is the key pair of the server, loaded again when one of its
+// files changes so the rotated certificates are served without restart.
+type certificate struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+	log      logger.Logger
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+	checked time.Time
+}
+
+func loadCertificate(certFile, keyFile string, interval time.Duration, log logger.Logger) (*certificate, error) {
+	c := &certificate{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+		log:      log,
+	}
+
+	modTime, err := c.lastModified()
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the certificate: %w", err)
+	}
+
+	c.cert = &cert
+	c.modTime = modTime
+	c.checked = time.Now()
+	return c, nil
+}
+
+// GetCertificate returns the certificate for the handshakes, reloaded if
+// its files changed since the last check.
+func (c *certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reload(time.Now())
+	return c.cert, nil
+}
+
+// reload the key pair when the files are newer, the current one is kept
+// if the new one cannot be loaded, like while the files are being written.
+func (c *certificate) reload(now time.Time) {
+	if now.Sub(c.checked) < c.interval {
+		return
+	}
+	c.checked = now
+
+	modTime, err := c.lastModified()
+	if err != nil {
+		c.log.Errorf("cannot check the certificate: %v", err)
+		return
+	}
+
+	if !modTime.After(c.modTime) {
+		return
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		c.log.Errorf("cannot reload the certificate: %v", err)
+		return
+	}
+
+	c.cert = &cert
+	c.modTime = modTime
+	c.log.Infof("certificate %s reloaded", c.certFile)
+}
+
+// lastModified returns the modification time of the newest file.
+func (c *certificate) lastModified() (time.Time, error) {
+	var last time.Time
+	for _, name := range []string{c.certFile, c.keyFile} {
+		fi, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if fi.ModTime().After(last) {
+			last = fi.ModTime()
+		}
+	}
+
+	return last, nil
+}
+
+// tlsConfig returns the TLS configuration of the server. The client
+// certificates are verified when given, the routes that need one check it.
+func tlsConfig(cfg config.TLS, log logger.Logger) (*tls.Config, error) {
+	cert, err := loadCertificate(cfg.CertFile, cfg.KeyFile, cfg.ReloadInterval, log)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: cert.GetCertificate,
+	}
+
+	if cfg.ClientCAFile != "" {
+		b, err := ioutil.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the client CA: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, errNoClientCA
+		}
+
+		c.ClientCAs = pool
+		c.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return c, nil
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
+
+	"github.com/Zucke/social_prove/internal/config"
+	"github.com/Zucke/social_prove/pkg/logger"
+)
+
+// issuer signs the certificates of the tests, itself when it has no
+// certificate.
+type issuer struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// issue returns a certificate of name signed by i, a CA when ca is true.
+func (i *issuer) issue(t *testing.T, name string, ca bool) issuer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  ca,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	parent, signer := tmpl, key
+	if i != nil {
+		parent, signer = i.cert, i.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return issuer{cert, key}
+}
+
+// write the certificate and the key of i as PEM files in dir.
+func (i issuer) write(t *testing.T, dir, name string) (certFile, keyFile string) {
+	der, err := x509.MarshalECPrivateKey(i.key)
+	assert.NoError(t, err)
+
+	certFile = filepath.Join(dir, name+".pem")
+	keyFile = filepath.Join(dir, name+"-key.pem")
+	assert.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: i.cert.Raw}), 0600))
+	assert.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600))
+
+	return certFile, keyFile
+}
+
+func (i issuer) keyPair() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{i.cert.Raw}, PrivateKey: i.key}
+}
+
+func TestCertificate_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := (*issuer)(nil).issue(t, "ca", true)
+	first := ca.issue(t, "first", false)
+	certFile, keyFile := first.write(t, dir, "server")
+
+	c, err := loadCertificate(certFile, keyFile, time.Minute, logger.NewMock())
+	assert.NoError(t, err)
+
+	got, err := c.GetCertificate(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, first.cert.Raw, got.Certificate[0])
+
+	// The rotated certificate is loaded once the interval passed.
+	second := ca.issue(t, "second", false)
+	second.write(t, dir, "server")
+	later := time.Now().Add(time.Hour)
+	assert.NoError(t, os.Chtimes(certFile, later, later))
+
+	got, _ = c.GetCertificate(nil)
+	assert.Equal(t, first.cert.Raw, got.Certificate[0])
+
+	c.checked = time.Now().Add(-time.Minute)
+	got, _ = c.GetCertificate(nil)
+	assert.Equal(t, second.cert.Raw, got.Certificate[0])
+
+	// A broken rotation keeps the current certificate.
+	assert.NoError(t, ioutil.WriteFile(keyFile, []byte("not a key"), 0600))
+	later = later.Add(time.Hour)
+	assert.NoError(t, os.Chtimes(keyFile, later, later))
+
+	c.checked = time.Now().Add(-time.Minute)
+	got, _ = c.GetCertificate(nil)
+	assert.Equal(t, second.cert.Raw, got.Certificate[0])
+}
+
+func TestServer_TLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ca := (*issuer)(nil).issue(t, "ca", true)
+	certFile, keyFile := ca.issue(t, "server", false).write(t, dir, "server")
+	caFile, _ := ca.write(t, dir, "ca")
+	client := ca.issue(t, "admin", false)
+
+	cfg := config.Default()
+	cfg.Server.Port = "0"
+	cfg.Server.TLS = config.TLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ReloadInterval: time.Minute}
+
+	serv := &Server{cfg: cfg, log: logger.NewMock(), errs: make(chan error, 1)}
+	assert.NoError(t, serv.configure(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %d", r.Proto, len(r.TLS.VerifiedChains))
+	})))
+
+	ctx := context.Background()
+	assert.NoError(t, serv.Start(ctx))
+	defer serv.Close(ctx)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	tests := []struct {
+		name  string
+		certs []tls.Certificate
+		want  string
+	}{
+		{
+			name:  "With client certificate",
+			certs: []tls.Certificate{client.keyPair()},
+			want:  "HTTP/2.0 1",
+		},
+		{
+			name: "Without client certificate",
+			want: "HTTP/2.0 0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &http.Client{Transport: &http.Transport{
+				TLSClientConfig:   &tls.Config{RootCAs: roots, Certificates: test.certs},
+				ForceAttemptHTTP2: true,
+			}}
+
+			res, err := c.Get(fmt.Sprintf("https://127.0.0.1:%d/", serv.Addr().(*net.TCPAddr).Port))
+			assert.NoError(t, err)
+			defer res.Body.Close()
+
+			b, err := ioutil.ReadAll(res.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, string(b))
+		})
+	}
+}
+
+func TestServer_H2C(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.Port = "0"
+
+	serv := &Server{cfg: cfg, log: logger.NewMock(), errs: make(chan error, 1)}
+	assert.NoError(t, serv.configure(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Proto)
+	})))
+
+	ctx := context.Background()
+	assert.NoError(t, serv.Start(ctx))
+	defer serv.Close(ctx)
+
+	c := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+
+	res, err := c.Get(fmt.Sprintf("http://127.0.0.1:%d/", serv.Addr().(*net.TCPAddr).Port))
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", string(b))
+}
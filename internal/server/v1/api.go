@@ -5,25 +5,115 @@ import (
 
 	"github.com/go-chi/chi"
 
+	"github.com/Zucke/social_prove/internal/config"
 	"github.com/Zucke/social_prove/internal/db/mongo"
+	"github.com/Zucke/social_prove/pkg/account"
+	accounthandler "github.com/Zucke/social_prove/pkg/account/handler"
+	audithandler "github.com/Zucke/social_prove/pkg/audit/handler"
+	auditservice "github.com/Zucke/social_prove/pkg/audit/service"
 	"github.com/Zucke/social_prove/pkg/auth"
+	badgehandler "github.com/Zucke/social_prove/pkg/badge/handler"
+	badgeservice "github.com/Zucke/social_prove/pkg/badge/service"
+	bookmarkhandler "github.com/Zucke/social_prove/pkg/bookmark/handler"
+	commenthandler "github.com/Zucke/social_prove/pkg/comment/handler"
 	"github.com/Zucke/social_prove/pkg/logger"
+	loggerhandler "github.com/Zucke/social_prove/pkg/logger/handler"
+	"github.com/Zucke/social_prove/pkg/picture"
 	posthandler "github.com/Zucke/social_prove/pkg/post/handler"
+	"github.com/Zucke/social_prove/pkg/ratelimit"
 	userhandler "github.com/Zucke/social_prove/pkg/user/handler"
 )
 
 // New create and configure routes.
-func New(log logger.Logger, dbClient *mongo.Client, fa auth.Repository) (http.Handler, error) {
+func New(cfg config.Config, log logger.Logger, dbClient *mongo.Client, authenticator *auth.Authenticator, fa auth.Repository, storage picture.Storage, queue picture.Queue, archives picture.Storage, exports account.Queue, erasures account.ErasureQueue, limits ratelimit.Repository) (http.Handler, error) {
 	r := chi.NewRouter()
 
+	// The requests are limited by user, or by IP without token.
+	if limits != nil {
+		limiter := ratelimit.New(limits, cfg.RateLimit.Default, cfg.RateLimit.Routes, log.Named("ratelimit"))
+		r.Use(authenticator.Identify)
+		r.Use(limiter.Middleware)
+	}
+
+	timeout := cfg.Services.Timeout
+
+	audits := auditservice.New(dbClient.Collection(mongo.AuditCollection), log.Named("audit"), timeout)
+	r.Mount("/audit/", audithandler.New(audits, log.Named("audit")).Routes(authenticator))
+	r.Mount("/log/levels", loggerhandler.New(log.Levels(), audits, log.Named("log")).Routes(authenticator))
+
 	//For User.
-	ur := userhandler.New(dbClient.Collection(mongo.UserCollection), log, fa)
+	ur := userhandler.New(
+		dbClient.Collection(mongo.UserCollection),
+		dbClient.Collection(mongo.PostCollection),
+		dbClient.Collection(mongo.CommentCollection),
+		dbClient.Collection(mongo.ReactionCollection),
+		dbClient.Collection(mongo.SuspensionCollection),
+		log.Named("user"),
+		timeout,
+		cfg.Auth.SigningString,
+		fa,
+		audits,
+	)
 	r.Post("/login/", ur.LoginHandler)
 	r.Post("/auth/google/", ur.FirebaseAuthHandler)
-	r.Mount("/user/", ur.Routes())
+	r.Mount("/user/", ur.Routes(authenticator))
+
+	badges := badgeservice.New(
+		dbClient.Collection(mongo.BadgeCollection),
+		dbClient.Collection(mongo.AwardCollection),
+		dbClient.Collection(mongo.PostCollection),
+		dbClient.Collection(mongo.TripCollection),
+		dbClient.Collection(mongo.UserCollection),
+		log.Named("badge"),
+		timeout,
+		audits,
+	)
+	bg := badgehandler.New(badges, log.Named("badge"))
+	r.Mount("/badge/", bg.Routes(authenticator))
+	r.Mount("/user/{id}/badges", bg.UserRoutes(authenticator))
+
+	ps := posthandler.New(
+		dbClient.Collection(mongo.PostCollection),
+		dbClient.Collection(mongo.ReactionCollection),
+		dbClient.Collection(mongo.RevisionCollection),
+		log.Named("post"),
+		timeout,
+		storage,
+		queue,
+		cfg.Media.Limits,
+		badges,
+		audits,
+	)
+	r.Mount("/post/", ps.Routes(authenticator))
+
+	ch := commenthandler.New(
+		dbClient.Collection(mongo.CommentCollection),
+		dbClient.Collection(mongo.PostCollection),
+		dbClient.Collection(mongo.ReactionCollection),
+		log.Named("comment"),
+		timeout,
+	)
+	r.Mount("/post/{id}/comments", ch.Routes(authenticator))
+
+	bh := bookmarkhandler.New(
+		dbClient.Collection(mongo.BookmarkCollection),
+		dbClient.Collection(mongo.PostCollection),
+		log.Named("bookmark"),
+		timeout,
+	)
+	r.Mount("/post/{id}/save", bh.PostRoutes(authenticator))
+	r.Mount("/me/collections", bh.Routes(authenticator))
 
-	ps := posthandler.New(dbClient.Collection(mongo.PostCollection), log)
-	r.Mount("/post/", ps.Routes())
+	ah := accounthandler.New(
+		dbClient.Collection(mongo.ExportCollection),
+		dbClient.Collection(mongo.UserCollection),
+		log.Named("account"),
+		timeout,
+		archives,
+		exports,
+		erasures,
+	)
+	r.Mount("/me", ah.Routes(authenticator))
 
 	return r, nil
 
@@ -0,0 +1,54 @@
+package account
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// ExportStatus of the building of an export.
+type ExportStatus string
+
+// Export statuses.
+const (
+	Pending ExportStatus = "pending"
+	Ready   ExportStatus = "ready"
+	Failed  ExportStatus = "failed"
+)
+
+// ExportTTL is how long a ready export can be downloaded.
+const ExportTTL = 7 * 24 * time.Hour
+
+// Errors.
+var (
+	ErrExportNotReady = response.NewError(response.Conflict, "export not ready")
+	ErrExportExpired  = response.NewError(response.Gone, "export expired")
+)
+
+// Export is an archive with the data of a user, built in background.
+type Export struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID      primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Status      ExportStatus       `json:"status,omitempty" bson:"status,omitempty"`
+	Key         string             `json:"-" bson:"key,omitempty"`
+	URL         string             `json:"url,omitempty" bson:"-"`
+	CreatedAt   time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	CompletedAt *time.Time         `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	ExpiresAt   *time.Time         `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+}
+
+// Downloadable returns the reason why the export cannot be downloaded at
+// now, or nil.
+func (e Export) Downloadable(now time.Time) error {
+	if e.Status != Ready {
+		return ErrExportNotReady
+	}
+
+	if e.ExpiresAt != nil && !now.Before(*e.ExpiresAt) {
+		return ErrExportExpired
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package eraser
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	"github.com/Zucke/social_prove/pkg/audit"
+	"github.com/Zucke/social_prove/pkg/badge"
+	"github.com/Zucke/social_prove/pkg/bookmark"
+	"github.com/Zucke/social_prove/pkg/comment"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/post"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+// Eraser remove for good the deleted users and posts. What belongs to them
+// is removed, the references that must stay, like the revisions, the
+// suspensions or the audit entries of a user, are anonymized.
+type Eraser struct {
+	users       user.Repository
+	suspensions user.SuspensionRepository
+	posts       post.Repository
+	revisions   post.RevisionRepository
+	bookmarks   bookmark.Repository
+	reactions   reaction.Repository
+	comments    comment.Repository
+	badges      badge.Repository
+	exports     account.ExportRepository
+	audits      audit.Repository
+	media       picture.Storage
+	archives    picture.Storage
+	log         logger.Logger
+}
+
+// EraseUser remove a deleted user with its posts, comments, reactions,
+// collections, awards and exports, and the references to it. A failed
+// erasure can be retried, the user goes last.
+func (e *Eraser) EraseUser(ctx context.Context, id primitive.ObjectID) error {
+	// The reposts still live leave the counters of their originals before
+	// they're purged.
+	originals, err := e.posts.DeleteAllForUser(ctx, id, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, originalID := range originals {
+		err := e.posts.IncReposts(ctx, originalID, -1)
+		if err != nil && !errors.Is(err, response.ErrorNotFound) {
+			return err
+		}
+	}
+
+	postIDs, err := e.posts.GetIDsForUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, postID := range postIDs {
+		if err := e.ErasePost(ctx, postID); err != nil {
+			return err
+		}
+	}
+
+	comments, err := e.comments.GetAllForUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range comments {
+		if err := e.reactions.DeleteAllForTarget(ctx, reaction.Comment, c.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := e.comments.DeleteAllForUser(ctx, id); err != nil {
+		return err
+	}
+
+	reactions, err := e.reactions.GetAllForUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, re := range reactions {
+		if err := e.eraseReaction(ctx, re); err != nil {
+			return err
+		}
+	}
+
+	if err := e.bookmarks.DeleteAllForUser(ctx, id); err != nil {
+		return err
+	}
+
+	if err := e.badges.DeleteAwards(ctx, id); err != nil {
+		return err
+	}
+
+	if err := e.eraseExports(ctx, id); err != nil {
+		return err
+	}
+
+	if err := e.revisions.AnonymizeUser(ctx, id); err != nil {
+		return err
+	}
+
+	if err := e.suspensions.Erase(ctx, id); err != nil {
+		return err
+	}
+
+	// The audit log is append only but for the erasure of the users.
+	if err := e.audits.Anonymize(ctx, id); err != nil {
+		return err
+	}
+
+	if err := e.users.RemoveReferences(ctx, id); err != nil {
+		return err
+	}
+
+	return e.users.Purge(ctx, id)
+}
+
+// ErasePost remove a deleted post with its pictures, its comments, the
+// reactions to them, its revisions and its bookmarks. Its reposts are
+// deleted to be purged later.
+func (e *Eraser) ErasePost(ctx context.Context, id primitive.ObjectID) error {
+	if err := e.reactions.DeleteAllForTarget(ctx, reaction.Post, id); err != nil {
+		return err
+	}
+
+	commentIDs, err := e.comments.GetIDsForPost(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, commentID := range commentIDs {
+		if err := e.reactions.DeleteAllForTarget(ctx, reaction.Comment, commentID); err != nil {
+			return err
+		}
+	}
+
+	if err := e.comments.DeleteAllForPost(ctx, id); err != nil {
+		return err
+	}
+
+	if err := e.revisions.DeleteAll(ctx, id); err != nil {
+		return err
+	}
+
+	if err := e.bookmarks.RemovePost(ctx, id); err != nil {
+		return err
+	}
+
+	if err := e.posts.DeleteReposts(ctx, id, time.Now()); err != nil {
+		return err
+	}
+
+	p, err := e.posts.Purge(ctx, id)
+	if errors.Is(err, response.ErrorNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, pic := range p.Pictures {
+		e.deleteMedia(ctx, pic.Key)
+		for _, v := range pic.Variants {
+			e.deleteMedia(ctx, v.Key)
+		}
+	}
+
+	return nil
+}
+
+// eraseReaction remove a reaction after taking it out of the counters of
+// its target, so a failure leaves it to the retry. The hidden reactions are
+// already out of the counters.
+func (e *Eraser) eraseReaction(ctx context.Context, re reaction.Reaction) error {
+	if re.DeletedAt == nil {
+		counts := map[reaction.Type]int{re.Type: -1}
+
+		var err error
+		switch re.Target {
+		case reaction.Post:
+			err = e.posts.IncReactions(ctx, re.TargetID, counts)
+		case reaction.Comment:
+			err = e.comments.IncReactions(ctx, re.TargetID, counts)
+		}
+		if err != nil && !errors.Is(err, response.ErrorNotFound) {
+			return err
+		}
+	}
+
+	_, err := e.reactions.Delete(ctx, re.Target, re.TargetID, re.UserID)
+	if err != nil && !errors.Is(err, reaction.ErrNoReaction) {
+		return err
+	}
+
+	return nil
+}
+
+// eraseExports remove the archives of a user and their records.
+func (e *Eraser) eraseExports(ctx context.Context, userID primitive.ObjectID) error {
+	exports, err := e.exports.GetAllForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, ex := range exports {
+		if ex.Key != "" {
+			if err := e.archives.Delete(ctx, ex.Key); err != nil {
+				return err
+			}
+		}
+
+		if err := e.exports.Delete(ctx, ex.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteMedia remove a stored picture, the post is already gone so a
+// failure is only logged.
+func (e *Eraser) deleteMedia(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+
+	if err := e.media.Delete(ctx, key); err != nil {
+		e.log.Warnf("cannot delete the picture %s: %v", key, err)
+	}
+}
+
+// New create a new Eraser, media stores the pictures of the posts and
+// archives the exports.
+func New(
+	log logger.Logger,
+	users user.Repository,
+	suspensions user.SuspensionRepository,
+	posts post.Repository,
+	revisions post.RevisionRepository,
+	bookmarks bookmark.Repository,
+	reactions reaction.Repository,
+	comments comment.Repository,
+	badges badge.Repository,
+	exports account.ExportRepository,
+	audits audit.Repository,
+	media picture.Storage,
+	archives picture.Storage,
+) *Eraser {
+	return &Eraser{
+		users:       users,
+		suspensions: suspensions,
+		posts:       posts,
+		revisions:   revisions,
+		bookmarks:   bookmarks,
+		reactions:   reactions,
+		comments:    comments,
+		badges:      badges,
+		exports:     exports,
+		audits:      audits,
+		media:       media,
+		archives:    archives,
+		log:         log,
+	}
+}
@@ -0,0 +1,341 @@
+package eraser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	amock "github.com/Zucke/social_prove/pkg/account/mock"
+	aumock "github.com/Zucke/social_prove/pkg/audit/mock"
+	gmock "github.com/Zucke/social_prove/pkg/badge/mock"
+	bmock "github.com/Zucke/social_prove/pkg/bookmark/mock"
+	"github.com/Zucke/social_prove/pkg/comment"
+	cmock "github.com/Zucke/social_prove/pkg/comment/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/picture"
+	smock "github.com/Zucke/social_prove/pkg/picture/mock"
+	"github.com/Zucke/social_prove/pkg/post"
+	pmock "github.com/Zucke/social_prove/pkg/post/mock"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	rmock "github.com/Zucke/social_prove/pkg/reaction/mock"
+	"github.com/Zucke/social_prove/pkg/response"
+	umock "github.com/Zucke/social_prove/pkg/user/mock"
+)
+
+type mocks struct {
+	users       *umock.MockRepository
+	suspensions *umock.MockSuspensionRepository
+	posts       *pmock.MockRepository
+	revisions   *pmock.MockRevisionRepository
+	bookmarks   *bmock.MockRepository
+	reactions   *rmock.MockRepository
+	comments    *cmock.MockRepository
+	badges      *gmock.MockRepository
+	exports     *amock.MockExportRepository
+	audits      *aumock.MockRepository
+	media       *smock.MockStorage
+	archives    *smock.MockStorage
+}
+
+func newEraser(ctrl *gomock.Controller) (*Eraser, mocks) {
+	m := mocks{
+		users:       umock.NewMockRepository(ctrl),
+		suspensions: umock.NewMockSuspensionRepository(ctrl),
+		posts:       pmock.NewMockRepository(ctrl),
+		revisions:   pmock.NewMockRevisionRepository(ctrl),
+		bookmarks:   bmock.NewMockRepository(ctrl),
+		reactions:   rmock.NewMockRepository(ctrl),
+		comments:    cmock.NewMockRepository(ctrl),
+		badges:      gmock.NewMockRepository(ctrl),
+		exports:     amock.NewMockExportRepository(ctrl),
+		audits:      aumock.NewMockRepository(ctrl),
+		media:       smock.NewMockStorage(ctrl),
+		archives:    smock.NewMockStorage(ctrl),
+	}
+
+	e := New(
+		logger.NewMock(),
+		m.users,
+		m.suspensions,
+		m.posts,
+		m.revisions,
+		m.bookmarks,
+		m.reactions,
+		m.comments,
+		m.badges,
+		m.exports,
+		m.audits,
+		m.media,
+		m.archives,
+	)
+
+	return e, m
+}
+
+func TestEraser_ErasePost(t *testing.T) {
+	postID := primitive.NewObjectID()
+	commentID := primitive.NewObjectID()
+
+	tests := []struct {
+		name       string
+		purgeErr   error
+		timesMedia int
+		wantErr    error
+	}{
+		{
+			name:       "post and pictures erased",
+			timesMedia: 1,
+		},
+		{
+			name:     "post already purged",
+			purgeErr: response.ErrorNotFound,
+		},
+		{
+			name:     "purge failed",
+			purgeErr: response.ErrorInternalServerError,
+			wantErr:  response.ErrorInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			e, m := newEraser(ctrl)
+
+			m.reactions.
+				EXPECT().
+				DeleteAllForTarget(gomock.Any(), reaction.Post, postID).
+				Return(nil).
+				Times(1)
+			m.comments.
+				EXPECT().
+				GetIDsForPost(gomock.Any(), postID).
+				Return([]primitive.ObjectID{commentID}, nil).
+				Times(1)
+			m.reactions.
+				EXPECT().
+				DeleteAllForTarget(gomock.Any(), reaction.Comment, commentID).
+				Return(nil).
+				Times(1)
+			m.comments.
+				EXPECT().
+				DeleteAllForPost(gomock.Any(), postID).
+				Return(nil).
+				Times(1)
+			m.revisions.
+				EXPECT().
+				DeleteAll(gomock.Any(), postID).
+				Return(nil).
+				Times(1)
+			m.bookmarks.
+				EXPECT().
+				RemovePost(gomock.Any(), postID).
+				Return(nil).
+				Times(1)
+			m.posts.
+				EXPECT().
+				DeleteReposts(gomock.Any(), postID, gomock.Any()).
+				Return(nil).
+				Times(1)
+			m.posts.
+				EXPECT().
+				Purge(gomock.Any(), postID).
+				Return(post.Post{
+					ID: postID,
+					Pictures: []picture.Picture{{
+						Key:      "posts/1/2.png",
+						Variants: []picture.Variant{{Key: "posts/1/2_feed.jpeg"}},
+					}},
+				}, test.purgeErr).
+				Times(1)
+			m.media.
+				EXPECT().
+				Delete(gomock.Any(), "posts/1/2.png").
+				Return(nil).
+				Times(test.timesMedia)
+			m.media.
+				EXPECT().
+				Delete(gomock.Any(), "posts/1/2_feed.jpeg").
+				Return(nil).
+				Times(test.timesMedia)
+
+			err := e.ErasePost(context.Background(), postID)
+			assert.Equal(t, test.wantErr, err)
+		})
+	}
+}
+
+func TestEraser_EraseUser(t *testing.T) {
+	userID := primitive.NewObjectID()
+	likedID := primitive.NewObjectID()
+	commentID := primitive.NewObjectID()
+	likedCommentID := primitive.NewObjectID()
+	hiddenID := primitive.NewObjectID()
+	originalID := primitive.NewObjectID()
+	purgedID := primitive.NewObjectID()
+	exportID := primitive.NewObjectID()
+	deletedAt := time.Now()
+
+	tests := []struct {
+		name       string
+		reactErr   error
+		timesRest  int
+		timesPurge int
+		wantErr    error
+	}{
+		{
+			name:       "user erased",
+			timesRest:  1,
+			timesPurge: 1,
+		},
+		{
+			name:     "user kept when its cascade fails",
+			reactErr: response.ErrorInternalServerError,
+			wantErr:  response.ErrorInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			e, m := newEraser(ctrl)
+
+			// The live reposts leave the counters before the purge.
+			m.posts.
+				EXPECT().
+				DeleteAllForUser(gomock.Any(), userID, gomock.Any()).
+				Return([]primitive.ObjectID{originalID, purgedID}, nil).
+				Times(1)
+			m.posts.
+				EXPECT().
+				IncReposts(gomock.Any(), originalID, -1).
+				Return(nil).
+				Times(1)
+			m.posts.
+				EXPECT().
+				IncReposts(gomock.Any(), purgedID, -1).
+				Return(response.ErrorNotFound).
+				Times(1)
+			m.posts.
+				EXPECT().
+				GetIDsForUser(gomock.Any(), userID).
+				Return([]primitive.ObjectID{}, nil).
+				Times(1)
+			m.comments.
+				EXPECT().
+				GetAllForUser(gomock.Any(), userID).
+				Return([]comment.Comment{{ID: commentID, UserID: userID}}, nil).
+				Times(1)
+			m.reactions.
+				EXPECT().
+				DeleteAllForTarget(gomock.Any(), reaction.Comment, commentID).
+				Return(nil).
+				Times(1)
+			m.comments.
+				EXPECT().
+				DeleteAllForUser(gomock.Any(), userID).
+				Return(nil).
+				Times(1)
+			m.reactions.
+				EXPECT().
+				GetAllForUser(gomock.Any(), userID).
+				Return([]reaction.Reaction{
+					{Target: reaction.Comment, TargetID: likedCommentID, UserID: userID, Type: reaction.Like},
+					{Target: reaction.Post, TargetID: likedID, UserID: userID, Type: reaction.Love},
+					// Hidden at the delete of the user, its counter is already updated.
+					{Target: reaction.Post, TargetID: hiddenID, UserID: userID, Type: reaction.Wow, DeletedAt: &deletedAt},
+				}, nil).
+				Times(1)
+			// Each reaction leaves the counters before it's deleted.
+			gomock.InOrder(
+				m.comments.
+					EXPECT().
+					IncReactions(gomock.Any(), likedCommentID, map[reaction.Type]int{reaction.Like: -1}).
+					Return(response.ErrorNotFound).
+					Times(1),
+				m.reactions.
+					EXPECT().
+					Delete(gomock.Any(), reaction.Comment, likedCommentID, userID).
+					Return(reaction.Like, nil).
+					Times(1),
+				m.posts.
+					EXPECT().
+					IncReactions(gomock.Any(), likedID, map[reaction.Type]int{reaction.Love: -1}).
+					Return(test.reactErr).
+					Times(1),
+			)
+			m.reactions.
+				EXPECT().
+				Delete(gomock.Any(), reaction.Post, likedID, userID).
+				Return(reaction.Love, nil).
+				Times(test.timesRest)
+			m.reactions.
+				EXPECT().
+				Delete(gomock.Any(), reaction.Post, hiddenID, userID).
+				Return(reaction.Type(""), reaction.ErrNoReaction).
+				Times(test.timesRest)
+			m.bookmarks.
+				EXPECT().
+				DeleteAllForUser(gomock.Any(), userID).
+				Return(nil).
+				Times(test.timesRest)
+			m.badges.
+				EXPECT().
+				DeleteAwards(gomock.Any(), userID).
+				Return(nil).
+				Times(test.timesRest)
+			m.exports.
+				EXPECT().
+				GetAllForUser(gomock.Any(), userID).
+				Return([]account.Export{{ID: exportID, Key: "1/2.zip"}}, nil).
+				Times(test.timesRest)
+			m.archives.
+				EXPECT().
+				Delete(gomock.Any(), "1/2.zip").
+				Return(nil).
+				Times(test.timesRest)
+			m.exports.
+				EXPECT().
+				Delete(gomock.Any(), exportID).
+				Return(nil).
+				Times(test.timesRest)
+			m.revisions.
+				EXPECT().
+				AnonymizeUser(gomock.Any(), userID).
+				Return(nil).
+				Times(test.timesRest)
+			m.suspensions.
+				EXPECT().
+				Erase(gomock.Any(), userID).
+				Return(nil).
+				Times(test.timesRest)
+			m.audits.
+				EXPECT().
+				Anonymize(gomock.Any(), userID).
+				Return(nil).
+				Times(test.timesRest)
+			m.users.
+				EXPECT().
+				RemoveReferences(gomock.Any(), userID).
+				Return(nil).
+				Times(test.timesRest)
+			m.users.
+				EXPECT().
+				Purge(gomock.Any(), userID).
+				Return(nil).
+				Times(test.timesPurge)
+
+			err := e.EraseUser(context.Background(), userID)
+			assert.Equal(t, test.wantErr, err)
+		})
+	}
+}
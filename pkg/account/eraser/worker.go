@@ -0,0 +1,86 @@
+package eraser
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/queue"
+)
+
+const (
+	queueSize = 100
+	// attempts is how many times an erasure is tried before it's left to
+	// the retention purge.
+	attempts  = 3
+	retryWait = 5
+)
+
+// Worker erase in background the users who removed their account. A failed
+// erasure is retried a few times, then it's finished by the retention purge.
+type Worker struct {
+	queue     *queue.Queue
+	eraser    account.Eraser
+	timeout   time.Duration
+	retryWait time.Duration
+	log       logger.Logger
+}
+
+// Enqueue add a deleted user to be erased.
+func (w *Worker) Enqueue(userID primitive.ObjectID) error {
+	return w.queue.Enqueue(userID)
+}
+
+// Start launch the worker.
+func (w *Worker) Start(ctx context.Context) error {
+	return w.queue.Start(ctx)
+}
+
+// Close stop receiving users and wait for the worker to finish, the
+// erasures waiting for a retry are left to the retention purge.
+func (w *Worker) Close(ctx context.Context) error {
+	return w.queue.Close(ctx)
+}
+
+func (w *Worker) process(id primitive.ObjectID) {
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			select {
+			case <-time.After(w.retryWait):
+			case <-w.queue.Closing():
+				w.log.Warnf("erasure of the user %s left to the retention purge: %v", id.Hex(), err)
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+		err = w.eraser.EraseUser(ctx, id)
+		cancel()
+		if err == nil {
+			return
+		}
+		w.log.Warnf("cannot erase the user %s: %v", id.Hex(), err)
+	}
+
+	w.log.Errorf("erasure of the user %s left to the retention purge: %v", id.Hex(), err)
+}
+
+// NewWorker create a new Worker, timeout limits each attempt to erase a
+// user.
+func NewWorker(log logger.Logger, eraser account.Eraser, timeout time.Duration) *Worker {
+	w := &Worker{
+		eraser:    eraser,
+		timeout:   timeout,
+		retryWait: retryWait * time.Second,
+		log:       log,
+	}
+	w.queue = queue.New(queueSize, 1, func(job interface{}) {
+		w.process(job.(primitive.ObjectID))
+	})
+
+	return w
+}
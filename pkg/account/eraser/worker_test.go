@@ -0,0 +1,96 @@
+package eraser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	amock "github.com/Zucke/social_prove/pkg/account/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/queue"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+func TestWorker_Process(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	tests := []struct {
+		name  string
+		errs  []error
+		times int
+	}{
+		{
+			name:  "erased",
+			errs:  []error{nil},
+			times: 1,
+		},
+		{
+			name:  "erased on a retry",
+			errs:  []error{response.ErrorInternalServerError, nil},
+			times: 2,
+		},
+		{
+			// The retention purge finishes it.
+			name:  "failure every attempt",
+			errs:  []error{response.ErrorInternalServerError, response.ErrorInternalServerError, response.ErrorInternalServerError},
+			times: attempts,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			em := amock.NewMockEraser(ctrl)
+
+			calls := 0
+			em.
+				EXPECT().
+				EraseUser(gomock.Any(), userID).
+				DoAndReturn(func(ctx context.Context, _ primitive.ObjectID) error {
+					_, ok := ctx.Deadline()
+					assert.True(t, ok)
+					err := test.errs[calls]
+					calls++
+					return err
+				}).
+				Times(test.times)
+
+			w := NewWorker(logger.NewMock(), em, time.Minute)
+			w.retryWait = time.Millisecond
+			w.process(userID)
+		})
+	}
+}
+
+func TestWorker_Close(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	em := amock.NewMockEraser(ctrl)
+	userID := primitive.NewObjectID()
+
+	erased := make(chan struct{})
+	em.
+		EXPECT().
+		EraseUser(gomock.Any(), userID).
+		DoAndReturn(func(context.Context, primitive.ObjectID) error {
+			close(erased)
+			return response.ErrorInternalServerError
+		}).
+		Times(1)
+
+	// The retry waiting on the close is left to the retention purge.
+	w := NewWorker(logger.NewMock(), em, time.Minute)
+	w.retryWait = time.Hour
+	assert.NoError(t, w.Start(context.Background()))
+	assert.NoError(t, w.Enqueue(userID))
+	<-erased
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, w.Close(ctx))
+	assert.Equal(t, queue.ErrClosed, w.Enqueue(userID))
+}
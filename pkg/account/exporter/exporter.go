@@ -0,0 +1,318 @@
+package exporter
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	"github.com/Zucke/social_prove/pkg/badge"
+	"github.com/Zucke/social_prove/pkg/bookmark"
+	"github.com/Zucke/social_prove/pkg/comment"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/post"
+	"github.com/Zucke/social_prove/pkg/queue"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+const (
+	queueSize = 100
+	waitTime  = 300
+)
+
+// follows are the follow edges of a user in the archive.
+type follows struct {
+	Following []primitive.ObjectID `json:"following"`
+	Followers []user.User          `json:"followers"`
+}
+
+// Exporter build in background the archives with the data of the users:
+// their profile, posts, comments, reactions, follows, collections and
+// awards as JSON, and the pictures of their posts under media/<post id>/.
+// The previous archives of a user are removed once a new one is ready.
+type Exporter struct {
+	queue     *queue.Queue
+	exports   account.ExportRepository
+	users     user.Repository
+	posts     post.Repository
+	comments  comment.Repository
+	reactions reaction.Repository
+	bookmarks bookmark.Repository
+	badges    badge.Repository
+	media     picture.Storage
+	archives  picture.Storage
+	log       logger.Logger
+}
+
+// Enqueue add an export to be built.
+func (e *Exporter) Enqueue(ex account.Export) error {
+	return e.queue.Enqueue(ex)
+}
+
+// Start launch the worker and enqueue the exports left pending.
+func (e *Exporter) Start(ctx context.Context) error {
+	if err := e.queue.Start(ctx); err != nil {
+		return err
+	}
+
+	exports, err := e.exports.GetPending(ctx)
+	if err != nil {
+		e.log.Errorf("cannot get pending exports: %v", err)
+		return err
+	}
+
+	for _, ex := range exports {
+		if err := e.Enqueue(ex); err != nil {
+			e.log.Warnf("cannot enqueue export %s: %v", ex.ID.Hex(), err)
+		}
+	}
+
+	return nil
+}
+
+// Close stop receiving exports and wait for the worker to finish.
+func (e *Exporter) Close(ctx context.Context) error {
+	return e.queue.Close(ctx)
+}
+
+func (e *Exporter) process(ex account.Export) {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTime*time.Second)
+	defer cancel()
+
+	key, err := e.build(ctx, ex)
+	if err != nil {
+		e.log.Errorf("cannot build export %s: %v", ex.ID.Hex(), err)
+		ex.Status = account.Failed
+	} else {
+		now := time.Now()
+		expiresAt := now.Add(account.ExportTTL)
+		ex.Status = account.Ready
+		ex.Key = key
+		ex.CompletedAt = &now
+		ex.ExpiresAt = &expiresAt
+	}
+
+	if err := e.exports.Update(ctx, &ex); err != nil {
+		e.log.Errorf("cannot update export %s: %v", ex.ID.Hex(), err)
+		return
+	}
+
+	if ex.Status == account.Ready {
+		e.prune(ctx, ex)
+	}
+}
+
+// build write the archive of the export to the storage and returns its key.
+func (e *Exporter) build(ctx context.Context, ex account.Export) (string, error) {
+	key := fmt.Sprintf("%s/%s.zip", ex.UserID.Hex(), ex.ID.Hex())
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(e.write(ctx, ex.UserID, pw))
+	}()
+
+	_, err := e.archives.Save(ctx, key, pr)
+	pr.CloseWithError(err)
+	if err != nil {
+		if err := e.archives.Delete(ctx, key); err != nil {
+			e.log.Warnf("cannot delete the archive %s: %v", key, err)
+		}
+		return "", err
+	}
+
+	return key, nil
+}
+
+// write write to w the zip archive with the data of a user.
+func (e *Exporter) write(ctx context.Context, userID primitive.ObjectID, w io.Writer) error {
+	u, err := e.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	followers, err := e.users.GetFollowers(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	posts, err := e.posts.GetAllForUser(ctx, userID, userID)
+	if err != nil {
+		return err
+	}
+
+	comments, err := e.comments.GetAllForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	reactions, err := e.reactions.GetAllForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	collections, err := e.collections(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	awards, err := e.badges.GetAwards(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	following := u.Following
+	if following == nil {
+		following = []primitive.ObjectID{}
+	}
+
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		v    interface{}
+	}{
+		{"profile.json", u},
+		{"posts.json", posts},
+		{"comments.json", comments},
+		{"reactions.json", reactions},
+		{"follows.json", follows{Following: following, Followers: followers}},
+		{"collections.json", collections},
+		{"badges.json", awards},
+	}
+
+	for _, f := range files {
+		if err := writeJSON(zw, f.name, f.v); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range posts {
+		for _, pic := range p.Pictures {
+			if err := e.writeMedia(ctx, zw, p.ID, pic); err != nil {
+				return err
+			}
+		}
+	}
+
+	return zw.Close()
+}
+
+// collections returns the collections of a user with their items.
+func (e *Exporter) collections(ctx context.Context, userID primitive.ObjectID) ([]bookmark.Collection, error) {
+	collections, err := e.bookmarks.GetAllForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, c := range collections {
+		collections[i], err = e.bookmarks.GetByID(ctx, userID, c.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return collections, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// writeMedia copy the original of a picture to the archive, a picture
+// missing from the storage is skipped.
+func (e *Exporter) writeMedia(ctx context.Context, zw *zip.Writer, postID primitive.ObjectID, pic picture.Picture) error {
+	if pic.Key == "" {
+		return nil
+	}
+
+	rc, err := e.media.Open(ctx, pic.Key)
+	if err != nil {
+		e.log.Warnf("cannot open the picture %s: %v", pic.Key, err)
+		return nil
+	}
+	defer rc.Close()
+
+	f, err := zw.Create(fmt.Sprintf("media/%s/%s%s", postID.Hex(), pic.ID.Hex(), path.Ext(pic.Key)))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, rc)
+
+	return err
+}
+
+// prune remove the archives of the user older than the given export.
+func (e *Exporter) prune(ctx context.Context, ex account.Export) {
+	exports, err := e.exports.GetAllForUser(ctx, ex.UserID)
+	if err != nil {
+		e.log.Errorf("cannot get the exports of %s: %v", ex.UserID.Hex(), err)
+		return
+	}
+
+	for _, old := range exports {
+		if old.ID == ex.ID || old.Status == account.Pending {
+			continue
+		}
+
+		if old.Key != "" {
+			if err := e.archives.Delete(ctx, old.Key); err != nil {
+				e.log.Errorf("cannot delete the archive %s: %v", old.Key, err)
+				continue
+			}
+		}
+
+		if err := e.exports.Delete(ctx, old.ID); err != nil {
+			e.log.Errorf("cannot delete export %s: %v", old.ID.Hex(), err)
+		}
+	}
+}
+
+// New create a new Exporter, media stores the pictures of the posts and
+// archives the exports.
+func New(
+	log logger.Logger,
+	exports account.ExportRepository,
+	users user.Repository,
+	posts post.Repository,
+	comments comment.Repository,
+	reactions reaction.Repository,
+	bookmarks bookmark.Repository,
+	badges badge.Repository,
+	media picture.Storage,
+	archives picture.Storage,
+) *Exporter {
+	e := &Exporter{
+		exports:   exports,
+		users:     users,
+		posts:     posts,
+		comments:  comments,
+		reactions: reactions,
+		bookmarks: bookmarks,
+		badges:    badges,
+		media:     media,
+		archives:  archives,
+		log:       log,
+	}
+	e.queue = queue.New(queueSize, 1, func(job interface{}) {
+		e.process(job.(account.Export))
+	})
+
+	return e
+}
@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	amock "github.com/Zucke/social_prove/pkg/account/mock"
+	"github.com/Zucke/social_prove/pkg/badge"
+	gmock "github.com/Zucke/social_prove/pkg/badge/mock"
+	"github.com/Zucke/social_prove/pkg/bookmark"
+	bmock "github.com/Zucke/social_prove/pkg/bookmark/mock"
+	"github.com/Zucke/social_prove/pkg/comment"
+	cmock "github.com/Zucke/social_prove/pkg/comment/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/picture"
+	smock "github.com/Zucke/social_prove/pkg/picture/mock"
+	"github.com/Zucke/social_prove/pkg/picture/storage"
+	"github.com/Zucke/social_prove/pkg/post"
+	pmock "github.com/Zucke/social_prove/pkg/post/mock"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	rmock "github.com/Zucke/social_prove/pkg/reaction/mock"
+	"github.com/Zucke/social_prove/pkg/user"
+	umock "github.com/Zucke/social_prove/pkg/user/mock"
+)
+
+func TestExporter_Process(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := primitive.NewObjectID()
+	postID := primitive.NewObjectID()
+	picID := primitive.NewObjectID()
+	collectionID := primitive.NewObjectID()
+	ex := account.Export{ID: primitive.NewObjectID(), UserID: userID, Status: account.Pending}
+	old := account.Export{ID: primitive.NewObjectID(), UserID: userID, Status: account.Ready, Key: "old.zip"}
+
+	em := amock.NewMockExportRepository(ctrl)
+	um := umock.NewMockRepository(ctrl)
+	pm := pmock.NewMockRepository(ctrl)
+	cm := cmock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	bm := bmock.NewMockRepository(ctrl)
+	gm := gmock.NewMockRepository(ctrl)
+	sm := smock.NewMockStorage(ctrl)
+	archives := storage.Local(t.TempDir(), "")
+
+	_, err := archives.Save(context.Background(), old.Key, strings.NewReader("old"))
+	assert.NoError(t, err)
+
+	um.
+		EXPECT().
+		GetByID(gomock.Any(), userID).
+		Return(user.User{ID: userID, FirstName: "Jane"}, nil).
+		Times(1)
+	um.
+		EXPECT().
+		GetFollowers(gomock.Any(), userID).
+		Return([]user.User{{ID: primitive.NewObjectID()}}, nil).
+		Times(1)
+	pm.
+		EXPECT().
+		GetAllForUser(gomock.Any(), userID, userID).
+		Return([]post.Post{{
+			ID:       postID,
+			UserID:   userID,
+			Pictures: []picture.Picture{{ID: picID, Key: "posts/1/2.png"}},
+		}}, nil).
+		Times(1)
+	cm.
+		EXPECT().
+		GetAllForUser(gomock.Any(), userID).
+		Return([]comment.Comment{{ID: primitive.NewObjectID(), PostID: postID, UserID: userID, Body: "nice"}}, nil).
+		Times(1)
+	rm.
+		EXPECT().
+		GetAllForUser(gomock.Any(), userID).
+		Return([]reaction.Reaction{{TargetID: primitive.NewObjectID(), Type: reaction.Love}}, nil).
+		Times(1)
+	bm.
+		EXPECT().
+		GetAllForUser(gomock.Any(), userID).
+		Return([]bookmark.Collection{{ID: collectionID}}, nil).
+		Times(1)
+	bm.
+		EXPECT().
+		GetByID(gomock.Any(), userID, collectionID).
+		Return(bookmark.Collection{ID: collectionID, Items: []bookmark.Item{{PostID: postID}}}, nil).
+		Times(1)
+	gm.
+		EXPECT().
+		GetAwards(gomock.Any(), userID).
+		Return([]badge.Award{}, nil).
+		Times(1)
+	sm.
+		EXPECT().
+		Open(gomock.Any(), "posts/1/2.png").
+		Return(ioutil.NopCloser(strings.NewReader("png")), nil).
+		Times(1)
+
+	var key string
+	em.
+		EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *account.Export) error {
+			assert.Equal(t, account.Ready, e.Status)
+			assert.NotNil(t, e.ExpiresAt)
+			key = e.Key
+			return nil
+		}).
+		Times(1)
+	em.
+		EXPECT().
+		GetAllForUser(gomock.Any(), userID).
+		Return([]account.Export{ex, old}, nil).
+		Times(1)
+	em.
+		EXPECT().
+		Delete(gomock.Any(), old.ID).
+		Return(nil).
+		Times(1)
+
+	e := New(logger.NewMock(), em, um, pm, cm, rm, bm, gm, sm, archives)
+	e.process(ex)
+
+	rc, err := archives.Open(context.Background(), key)
+	assert.NoError(t, err)
+	defer rc.Close()
+
+	b, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	assert.NoError(t, err)
+
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"profile.json",
+		"posts.json",
+		"comments.json",
+		"reactions.json",
+		"follows.json",
+		"collections.json",
+		"badges.json",
+		"media/" + postID.Hex() + "/" + picID.Hex() + ".png",
+	}, names)
+
+	_, err = archives.Open(context.Background(), old.Key)
+	assert.Error(t, err)
+}
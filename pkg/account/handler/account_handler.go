@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	"github.com/Zucke/social_prove/pkg/account/service"
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+// Handler is the router of the account of the logged user.
+type Handler struct {
+	service account.Service
+	log     logger.Logger
+}
+
+// ExportHandler request an archive with the data of the user, it's built
+// in background.
+func (h *Handler) ExportHandler(w http.ResponseWriter, r *http.Request) {
+	var e account.Export
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		e, err = h.service.RequestExport(ctx, lID)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+e.ID.Hex())
+	_ = response.JSON(w, http.StatusAccepted, render.M{"export": e})
+}
+
+// GetExportHandler response an export of the user with its download link
+// once ready.
+func (h *Handler) GetExportHandler(w http.ResponseWriter, r *http.Request) {
+	var e account.Export
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		e, err = h.service.GetExport(ctx, lID, id)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	if e.Status == account.Ready {
+		e.URL = strings.TrimSuffix(r.URL.Path, "/") + "/download"
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{"export": e})
+}
+
+// DownloadHandler response the archive of a ready export of the user.
+func (h *Handler) DownloadHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		rc io.ReadCloser
+		e  account.Export
+	)
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		rc, e, err = h.service.OpenExport(ctx, lID, id)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="export-%s.zip"`, e.ID.Hex()))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, rc); err != nil {
+		h.log.WithContext(ctx).Error(err)
+	}
+}
+
+// EraseHandler remove for good the user and all its data, the data is
+// erased in background once the request is accepted.
+func (h *Handler) EraseHandler(w http.ResponseWriter, r *http.Request) {
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		err = h.service.Erase(ctx, lID)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Routes configure and return the routes of the account of the user,
+// mounted on /me.
+func (h *Handler) Routes(a *auth.Authenticator) http.Handler {
+	r := chi.NewRouter()
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Post("/export", h.ExportHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Get("/export/{id}", h.GetExportHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Get("/export/{id}/download", h.DownloadHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Delete("/", h.EraseHandler)
+
+	return r
+}
+
+// New create and configure a new Handler, archives stores the exports.
+func New(coll, userColl *mongo.Collection, log logger.Logger, timeout time.Duration, archives picture.Storage, queue account.Queue, erasures account.ErasureQueue) *Handler {
+	return &Handler{
+		log:     log,
+		service: service.New(coll, userColl, log, timeout, archives, queue, erasures),
+	}
+}
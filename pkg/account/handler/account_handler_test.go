@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	mock "github.com/Zucke/social_prove/pkg/account/mock"
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+func TestHandler_Download(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+
+	tests := []struct {
+		name string
+		code int
+		err  error
+	}{
+		{
+			name: "Success",
+			code: http.StatusOK,
+		},
+		{
+			name: "Failure not ready",
+			code: http.StatusConflict,
+			err:  account.ErrExportNotReady,
+		},
+		{
+			name: "Failure expired",
+			code: http.StatusGone,
+			err:  account.ErrExportExpired,
+		},
+		{
+			name: "Failure not found",
+			code: http.StatusNotFound,
+			err:  response.ErrorNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var rc *readCloser
+			if test.err == nil {
+				rc = &readCloser{Reader: strings.NewReader("zip")}
+				m.
+					EXPECT().
+					OpenExport(gomock.Any(), id2.Hex(), id1.Hex()).
+					Return(rc, account.Export{ID: id1}, nil).
+					Times(1)
+			} else {
+				m.
+					EXPECT().
+					OpenExport(gomock.Any(), id2.Hex(), id1.Hex()).
+					Return(nil, account.Export{}, test.err).
+					Times(1)
+			}
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/me/export/"+id1.Hex()+"/download", nil)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			mux.Get("/me/export/{id}/download", h.DownloadHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+			if test.err != nil {
+				return
+			}
+
+			body, _ := ioutil.ReadAll(w.Body)
+			assert.Equal(t, "zip", string(body))
+			assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+			assert.True(t, rc.closed)
+		})
+	}
+}
+
+func TestHandler_GetExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+
+	tests := []struct {
+		name   string
+		status account.ExportStatus
+		url    string
+	}{
+		{
+			name:   "Success ready",
+			status: account.Ready,
+			url:    "/me/export/" + id1.Hex() + "/download",
+		},
+		{
+			name:   "Success pending",
+			status: account.Pending,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetExport(gomock.Any(), id2.Hex(), id1.Hex()).
+				Return(account.Export{ID: id1, Status: test.status}, nil).
+				Times(1)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/me/export/"+id1.Hex(), nil)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			mux.Get("/me/export/{id}", h.GetExportHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			if test.url == "" {
+				assert.NotContains(t, w.Body.String(), `"url"`)
+			} else {
+				assert.Contains(t, w.Body.String(), `"url":"`+test.url+`"`)
+			}
+		})
+	}
+}
+
+func TestHandler_Erase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name string
+		code int
+		err  error
+	}{
+		{
+			name: "Success",
+			code: http.StatusAccepted,
+		},
+		{
+			name: "Failure not found",
+			code: http.StatusNotFound,
+			err:  response.ErrorNotFound,
+		},
+		{
+			name: "Failure internal",
+			code: http.StatusInternalServerError,
+			err:  response.ErrorInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Erase(gomock.Any(), id.Hex()).
+				Return(test.err).
+				Times(1)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodDelete, "/me", nil)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id))
+
+			mux := chi.NewRouter()
+			mux.Delete("/me", h.EraseHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
+type readCloser struct {
+	*strings.Reader
+	closed bool
+}
+
+func (rc *readCloser) Close() error {
+	rc.closed = true
+	return nil
+}
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Zucke/social_prove/pkg/account (interfaces: Eraser)
+
+// Package mock_account is a generated GoMock package.
+package mock_account
+
+import (
+	context "context"
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	reflect "reflect"
+)
+
+// MockEraser is a mock of Eraser interface
+type MockEraser struct {
+	ctrl     *gomock.Controller
+	recorder *MockEraserMockRecorder
+}
+
+// MockEraserMockRecorder is the mock recorder for MockEraser
+type MockEraserMockRecorder struct {
+	mock *MockEraser
+}
+
+// NewMockEraser creates a new mock instance
+func NewMockEraser(ctrl *gomock.Controller) *MockEraser {
+	mock := &MockEraser{ctrl: ctrl}
+	mock.recorder = &MockEraserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockEraser) EXPECT() *MockEraserMockRecorder {
+	return m.recorder
+}
+
+// ErasePost mocks base method
+func (m *MockEraser) ErasePost(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ErasePost", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ErasePost indicates an expected call of ErasePost
+func (mr *MockEraserMockRecorder) ErasePost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ErasePost", reflect.TypeOf((*MockEraser)(nil).ErasePost), arg0, arg1)
+}
+
+// EraseUser mocks base method
+func (m *MockEraser) EraseUser(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EraseUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EraseUser indicates an expected call of EraseUser
+func (mr *MockEraserMockRecorder) EraseUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseUser", reflect.TypeOf((*MockEraser)(nil).EraseUser), arg0, arg1)
+}
@@ -0,0 +1,48 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Zucke/social_prove/pkg/account (interfaces: ErasureQueue)
+
+// Package mock_account is a generated GoMock package.
+package mock_account
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	reflect "reflect"
+)
+
+// MockErasureQueue is a mock of ErasureQueue interface
+type MockErasureQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockErasureQueueMockRecorder
+}
+
+// MockErasureQueueMockRecorder is the mock recorder for MockErasureQueue
+type MockErasureQueueMockRecorder struct {
+	mock *MockErasureQueue
+}
+
+// NewMockErasureQueue creates a new mock instance
+func NewMockErasureQueue(ctrl *gomock.Controller) *MockErasureQueue {
+	mock := &MockErasureQueue{ctrl: ctrl}
+	mock.recorder = &MockErasureQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockErasureQueue) EXPECT() *MockErasureQueueMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method
+func (m *MockErasureQueue) Enqueue(arg0 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enqueue indicates an expected call of Enqueue
+func (mr *MockErasureQueueMockRecorder) Enqueue(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockErasureQueue)(nil).Enqueue), arg0)
+}
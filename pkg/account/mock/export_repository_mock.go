@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Zucke/social_prove/pkg/account (interfaces: ExportRepository)
+
+// Package mock_account is a generated GoMock package.
+package mock_account
+
+import (
+	context "context"
+	account "github.com/Zucke/social_prove/pkg/account"
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	reflect "reflect"
+)
+
+// MockExportRepository is a mock of ExportRepository interface
+type MockExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportRepositoryMockRecorder
+}
+
+// MockExportRepositoryMockRecorder is the mock recorder for MockExportRepository
+type MockExportRepositoryMockRecorder struct {
+	mock *MockExportRepository
+}
+
+// NewMockExportRepository creates a new mock instance
+func NewMockExportRepository(ctrl *gomock.Controller) *MockExportRepository {
+	mock := &MockExportRepository{ctrl: ctrl}
+	mock.recorder = &MockExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockExportRepository) EXPECT() *MockExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockExportRepository) Create(arg0 context.Context, arg1 *account.Export) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockExportRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockExportRepository)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockExportRepository) Delete(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockExportRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockExportRepository)(nil).Delete), arg0, arg1)
+}
+
+// GetAllForUser mocks base method
+func (m *MockExportRepository) GetAllForUser(arg0 context.Context, arg1 primitive.ObjectID) ([]account.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllForUser", arg0, arg1)
+	ret0, _ := ret[0].([]account.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllForUser indicates an expected call of GetAllForUser
+func (mr *MockExportRepositoryMockRecorder) GetAllForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllForUser", reflect.TypeOf((*MockExportRepository)(nil).GetAllForUser), arg0, arg1)
+}
+
+// GetByID mocks base method
+func (m *MockExportRepository) GetByID(arg0 context.Context, arg1, arg2 primitive.ObjectID) (account.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(account.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID
+func (mr *MockExportRepositoryMockRecorder) GetByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockExportRepository)(nil).GetByID), arg0, arg1, arg2)
+}
+
+// GetPending mocks base method
+func (m *MockExportRepository) GetPending(arg0 context.Context) ([]account.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", arg0)
+	ret0, _ := ret[0].([]account.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending
+func (mr *MockExportRepositoryMockRecorder) GetPending(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockExportRepository)(nil).GetPending), arg0)
+}
+
+// Update mocks base method
+func (m *MockExportRepository) Update(arg0 context.Context, arg1 *account.Export) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockExportRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockExportRepository)(nil).Update), arg0, arg1)
+}
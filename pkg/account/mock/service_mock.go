@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Zucke/social_prove/pkg/account (interfaces: Service)
+
+// Package mock_account is a generated GoMock package.
+package mock_account
+
+import (
+	context "context"
+	account "github.com/Zucke/social_prove/pkg/account"
+	gomock "github.com/golang/mock/gomock"
+	io "io"
+	reflect "reflect"
+)
+
+// MockService is a mock of Service interface
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Erase mocks base method
+func (m *MockService) Erase(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erase", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Erase indicates an expected call of Erase
+func (mr *MockServiceMockRecorder) Erase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erase", reflect.TypeOf((*MockService)(nil).Erase), arg0, arg1)
+}
+
+// GetExport mocks base method
+func (m *MockService) GetExport(arg0 context.Context, arg1, arg2 string) (account.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExport", arg0, arg1, arg2)
+	ret0, _ := ret[0].(account.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExport indicates an expected call of GetExport
+func (mr *MockServiceMockRecorder) GetExport(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExport", reflect.TypeOf((*MockService)(nil).GetExport), arg0, arg1, arg2)
+}
+
+// OpenExport mocks base method
+func (m *MockService) OpenExport(arg0 context.Context, arg1, arg2 string) (io.ReadCloser, account.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenExport", arg0, arg1, arg2)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(account.Export)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenExport indicates an expected call of OpenExport
+func (mr *MockServiceMockRecorder) OpenExport(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenExport", reflect.TypeOf((*MockService)(nil).OpenExport), arg0, arg1, arg2)
+}
+
+// RequestExport mocks base method
+func (m *MockService) RequestExport(arg0 context.Context, arg1 string) (account.Export, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestExport", arg0, arg1)
+	ret0, _ := ret[0].(account.Export)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestExport indicates an expected call of RequestExport
+func (mr *MockServiceMockRecorder) RequestExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestExport", reflect.TypeOf((*MockService)(nil).RequestExport), arg0, arg1)
+}
@@ -0,0 +1,17 @@
+package account
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportRepository handle the storage of the exports.
+type ExportRepository interface {
+	Create(ctx context.Context, e *Export) error
+	GetByID(ctx context.Context, userID, id primitive.ObjectID) (Export, error)
+	GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]Export, error)
+	GetPending(ctx context.Context) ([]Export, error)
+	Update(ctx context.Context, e *Export) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// Repository storage to the export model.
+type Repository struct {
+	coll *mongo.Collection
+	log  logger.Logger
+}
+
+// Create store a new export.
+func (r *Repository) Create(ctx context.Context, e *account.Export) error {
+	_, err := r.coll.InsertOne(ctx, e)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrCouldNotInsert
+	}
+
+	return nil
+}
+
+// GetByID returns an export of the user by ID.
+func (r *Repository) GetByID(ctx context.Context, userID, id primitive.ObjectID) (account.Export, error) {
+	e := account.Export{}
+	err := r.coll.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&e)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return account.Export{}, response.ErrorNotFound
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return account.Export{}, response.ErrorInternalServerError
+	}
+
+	return e, nil
+}
+
+// GetAllForUser returns the exports of a user, the newest first.
+func (r *Repository) GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]account.Export, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+// GetPending returns the exports left to build.
+func (r *Repository) GetPending(ctx context.Context) ([]account.Export, error) {
+	return r.find(ctx, bson.M{"status": account.Pending})
+}
+
+func (r *Repository) find(ctx context.Context, filter bson.M) ([]account.Export, error) {
+	opt := options.Find().SetSort(bson.M{"created_at": -1})
+	exports := make([]account.Export, 0)
+
+	cursor, err := r.coll.Find(ctx, filter, opt)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		e := account.Export{}
+		if err := cursor.Decode(&e); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		exports = append(exports, e)
+	}
+
+	return exports, nil
+}
+
+// Update store the status of an export.
+func (r *Repository) Update(ctx context.Context, e *account.Export) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":       e.Status,
+			"key":          e.Key,
+			"completed_at": e.CompletedAt,
+			"expires_at":   e.ExpiresAt,
+		},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": e.ID}, update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if result.MatchedCount == 0 {
+		return response.ErrorNotFound
+	}
+
+	return nil
+}
+
+// Delete remove an export by ID.
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// Mongo create a new Repository.
+func Mongo(coll *mongo.Collection, log logger.Logger) account.ExportRepository {
+	return &Repository{
+		coll: coll,
+		log:  log,
+	}
+}
@@ -0,0 +1,34 @@
+package account
+
+import (
+	"context"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Service the account service, the exports and the erasure of the data of
+// a user.
+type Service interface {
+	RequestExport(ctx context.Context, userID string) (Export, error)
+	GetExport(ctx context.Context, userID, id string) (Export, error)
+	OpenExport(ctx context.Context, userID, id string) (io.ReadCloser, Export, error)
+	Erase(ctx context.Context, userID string) error
+}
+
+// Queue receives the exports to be built asynchronously.
+type Queue interface {
+	Enqueue(e Export) error
+}
+
+// ErasureQueue receives the users to be erased asynchronously.
+type ErasureQueue interface {
+	Enqueue(userID primitive.ObjectID) error
+}
+
+// Eraser remove for good a deleted user or post with everything that
+// depends on it.
+type Eraser interface {
+	EraseUser(ctx context.Context, id primitive.ObjectID) error
+	ErasePost(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	"github.com/Zucke/social_prove/pkg/account/repository"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/tracing"
+	"github.com/Zucke/social_prove/pkg/user"
+	userrepository "github.com/Zucke/social_prove/pkg/user/repository"
+)
+
+// AccountService the account service.
+type AccountService struct {
+	exports  account.ExportRepository
+	users    user.Repository
+	archives picture.Storage
+	queue    account.Queue
+	erasures account.ErasureQueue
+	timeout  time.Duration
+	log      logger.Logger
+}
+
+// RequestExport create an export of the data of the user to be built in
+// background. An export already pending is returned instead.
+func (as *AccountService) RequestExport(ctx context.Context, userID string) (account.Export, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.RequestExport")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, as.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return account.Export{}, response.ErrInvalidID
+	}
+
+	exports, err := as.exports.GetAllForUser(ctx, objectUserID)
+	if err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return account.Export{}, err
+	}
+
+	for _, e := range exports {
+		if e.Status == account.Pending {
+			return e, nil
+		}
+	}
+
+	e := account.Export{
+		ID:        primitive.NewObjectID(),
+		UserID:    objectUserID,
+		Status:    account.Pending,
+		CreatedAt: time.Now(),
+	}
+	if err := as.exports.Create(ctx, &e); err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return account.Export{}, err
+	}
+
+	if err := as.queue.Enqueue(e); err != nil {
+		as.log.WithContext(ctx).Warnf("export %s left pending: %v", e.ID.Hex(), err)
+	}
+
+	return e, nil
+}
+
+// GetExport returns an export of the user by ID.
+func (as *AccountService) GetExport(ctx context.Context, userID, id string) (account.Export, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.GetExport")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, as.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return account.Export{}, response.ErrInvalidID
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return account.Export{}, response.ErrInvalidID
+	}
+
+	e, err := as.exports.GetByID(ctx, objectUserID, objectID)
+	if err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return account.Export{}, err
+	}
+
+	return e, nil
+}
+
+// OpenExport returns the archive of a ready export of the user, the caller
+// must close it.
+func (as *AccountService) OpenExport(ctx context.Context, userID, id string) (io.ReadCloser, account.Export, error) {
+	ctx, span := tracing.Start(ctx, "AccountService.OpenExport")
+	defer span.End()
+
+	e, err := as.GetExport(ctx, userID, id)
+	if err != nil {
+		return nil, account.Export{}, err
+	}
+
+	if err := e.Downloadable(time.Now()); err != nil {
+		return nil, account.Export{}, err
+	}
+
+	rc, err := as.archives.Open(ctx, e.Key)
+	if err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return nil, account.Export{}, response.ErrorInternalServerError
+	}
+
+	return rc, e, nil
+}
+
+// Erase remove for good the user and its data. The user is deleted at once
+// and its data erased in background, an erasure that keeps failing is
+// finished by the retention purge once the retention is over.
+func (as *AccountService) Erase(ctx context.Context, userID string) error {
+	ctx, span := tracing.Start(ctx, "AccountService.Erase")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, as.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return response.ErrInvalidID
+	}
+
+	at := time.Now()
+	if err := as.users.Delete(ctx, user.Super, objectUserID, at); err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	if err := as.erasures.Enqueue(objectUserID); err != nil {
+		as.log.WithContext(ctx).Warnf("erasure of the user %s left to the retention purge: %v", userID, err)
+	}
+
+	return nil
+}
+
+// New create a new AccountService, archives stores the exports and erasures
+// erase the deleted users.
+func New(coll, userColl *mongo.Collection, log logger.Logger, timeout time.Duration, archives picture.Storage, queue account.Queue, erasures account.ErasureQueue) *AccountService {
+	return &AccountService{
+		exports:  repository.Mongo(coll, log),
+		users:    userrepository.Mongo(userColl, log),
+		archives: archives,
+		queue:    queue,
+		erasures: erasures,
+		log:      log,
+		timeout:  timeout,
+	}
+}
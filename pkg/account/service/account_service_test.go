@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	mock "github.com/Zucke/social_prove/pkg/account/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	smock "github.com/Zucke/social_prove/pkg/picture/mock"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+	umock "github.com/Zucke/social_prove/pkg/user/mock"
+)
+
+func TestAccountService_RequestExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockExportRepository(ctrl)
+	qm := mock.NewMockQueue(ctrl)
+	userID := primitive.NewObjectID()
+	pending := account.Export{ID: primitive.NewObjectID(), UserID: userID, Status: account.Pending}
+	ready := account.Export{ID: primitive.NewObjectID(), UserID: userID, Status: account.Ready}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name        string
+		userID      string
+		exports     []account.Export
+		err         error
+		timesGet    int
+		timesCreate int
+		wantPending bool
+	}{
+		{
+			name:        "succes new export",
+			userID:      userID.Hex(),
+			exports:     []account.Export{ready},
+			timesGet:    1,
+			timesCreate: 1,
+		},
+		{
+			name:        "succes export already pending",
+			userID:      userID.Hex(),
+			exports:     []account.Export{pending, ready},
+			timesGet:    1,
+			wantPending: true,
+		},
+		{
+			name:   "failure bad id",
+			userID: "1234",
+			err:    response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetAllForUser(gomock.Any(), userID).
+				Return(test.exports, nil).
+				Times(test.timesGet)
+			m.
+				EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				Return(nil).
+				Times(test.timesCreate)
+			qm.
+				EXPECT().
+				Enqueue(gomock.Any()).
+				Return(nil).
+				Times(test.timesCreate)
+
+			s := AccountService{
+				exports: m,
+				queue:   qm,
+				log:     l,
+			}
+
+			e, err := s.RequestExport(ctx, test.userID)
+			assert.Equal(t, test.err, err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, account.Pending, e.Status)
+			assert.Equal(t, userID, e.UserID)
+			if test.wantPending {
+				assert.Equal(t, pending.ID, e.ID)
+			} else {
+				assert.NotEqual(t, pending.ID, e.ID)
+			}
+		})
+	}
+}
+
+func TestAccountService_OpenExport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockExportRepository(ctrl)
+	sm := smock.NewMockStorage(ctrl)
+	userID := primitive.NewObjectID()
+	id := primitive.NewObjectID()
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name      string
+		export    account.Export
+		getErr    error
+		err       error
+		timesOpen int
+	}{
+		{
+			name:      "succes",
+			export:    account.Export{ID: id, Status: account.Ready, Key: "a.zip", ExpiresAt: &future},
+			timesOpen: 1,
+		},
+		{
+			name:   "failure pending",
+			export: account.Export{ID: id, Status: account.Pending},
+			err:    account.ErrExportNotReady,
+		},
+		{
+			name:   "failure expired",
+			export: account.Export{ID: id, Status: account.Ready, Key: "a.zip", ExpiresAt: &past},
+			err:    account.ErrExportExpired,
+		},
+		{
+			name:   "failure not found",
+			getErr: response.ErrorNotFound,
+			err:    response.ErrorNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), userID, id).
+				Return(test.export, test.getErr).
+				Times(1)
+			sm.
+				EXPECT().
+				Open(gomock.Any(), "a.zip").
+				Return(ioutil.NopCloser(strings.NewReader("zip")), nil).
+				Times(test.timesOpen)
+
+			s := AccountService{
+				exports:  m,
+				archives: sm,
+				log:      l,
+			}
+
+			rc, e, err := s.OpenExport(ctx, userID.Hex(), id.Hex())
+			assert.Equal(t, test.err, err)
+			if err != nil {
+				return
+			}
+			defer rc.Close()
+
+			assert.Equal(t, id, e.ID)
+		})
+	}
+}
+
+func TestAccountService_Erase(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	um := umock.NewMockRepository(ctrl)
+	qm := mock.NewMockErasureQueue(ctrl)
+	userID := primitive.NewObjectID()
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name       string
+		userID     string
+		deleteErr  error
+		enqueueErr error
+		err        error
+		timesUser  int
+		timesErase int
+	}{
+		{
+			name:       "succes",
+			userID:     userID.Hex(),
+			timesUser:  1,
+			timesErase: 1,
+		},
+		{
+			// The user is already deleted, the purge erases it later.
+			name:       "succes not enqueued",
+			userID:     userID.Hex(),
+			enqueueErr: response.ErrorInternalServerError,
+			timesUser:  1,
+			timesErase: 1,
+		},
+		{
+			name:      "failure already deleted",
+			userID:    userID.Hex(),
+			deleteErr: response.ErrorNotFound,
+			err:       response.ErrorNotFound,
+			timesUser: 1,
+		},
+		{
+			name:   "failure bad id",
+			userID: "1234",
+			err:    response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The data is erased in background, the posts by the eraser
+			// that updates the counters of the reposts.
+			um.
+				EXPECT().
+				Delete(gomock.Any(), user.Super, userID, gomock.Any()).
+				Return(test.deleteErr).
+				Times(test.timesUser)
+			qm.
+				EXPECT().
+				Enqueue(userID).
+				Return(test.enqueueErr).
+				Times(test.timesErase)
+
+			s := AccountService{
+				users:    um,
+				erasures: qm,
+				log:      l,
+			}
+
+			err := s.Erase(ctx, test.userID)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+// Action is an administrative action.
+type Action string
+
+// Audited actions.
+const (
+	CreateAdmin    Action = "user.create_admin"
+	UpdateUser     Action = "user.update"
+	DeleteUser     Action = "user.delete"
+	RestoreUser    Action = "user.restore"
+	DeactivateUser Action = "user.deactivate"
+	ActivateUser   Action = "user.activate"
+	SuspendUser    Action = "user.suspend"
+	UnsuspendUser  Action = "user.unsuspend"
+	SetLogLevel    Action = "log.set_level"
+	UpdatePost     Action = "post.update"
+	DeletePost     Action = "post.delete"
+	RestorePost    Action = "post.restore"
+	CreateBadge    Action = "badge.create"
+	UpdateBadge    Action = "badge.update"
+	DeleteBadge    Action = "badge.delete"
+)
+
+// Target types.
+const (
+	UserTarget  = "user"
+	LogTarget   = "log"
+	PostTarget  = "post"
+	BadgeTarget = "badge"
+)
+
+// Errors.
+var (
+	ErrInvalidQuery = response.NewError(response.Invalid, "invalid audit query")
+)
+
+// Entry is the record of an administrative action, with the target as it
+// was before and after the action.
+type Entry struct {
+	ID         primitive.ObjectID     `json:"id,omitempty" bson:"_id,omitempty"`
+	ActorID    primitive.ObjectID     `json:"actor_id,omitempty" bson:"actor_id,omitempty"`
+	ActorRole  user.Role              `json:"actor_role" bson:"actor_role"`
+	Action     Action                 `json:"action,omitempty" bson:"action,omitempty"`
+	TargetType string                 `json:"target_type,omitempty" bson:"target_type,omitempty"`
+	TargetID   primitive.ObjectID     `json:"target_id,omitempty" bson:"target_id,omitempty"`
+	Before     map[string]interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After      map[string]interface{} `json:"after,omitempty" bson:"after,omitempty"`
+	RequestID  string                 `json:"request_id,omitempty" bson:"request_id,omitempty"`
+	IP         string                 `json:"ip,omitempty" bson:"ip,omitempty"`
+	CreatedAt  time.Time              `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// Query filters the entries, the zero fields match everything.
+type Query struct {
+	ActorID    primitive.ObjectID
+	TargetID   primitive.ObjectID
+	TargetType string
+	Action     Action
+	From       time.Time
+	To         time.Time
+}
+
+// Snapshot returns v as it's rendered by the API, so the fields hidden from
+// the responses, like the password hashes, stay out of the log.
+func Snapshot(v interface{}) map[string]interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil
+	}
+
+	return m
+}
+
+type ipKey struct{}
+
+// WithIP is a middleware keeping the client IP in the request context for
+// the entries, it goes after middleware.RealIP.
+func WithIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), ipKey{}, r.RemoteAddr)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// IP returns the client IP kept by WithIP.
+func IP(ctx context.Context) string {
+	ip, _ := ctx.Value(ipKey{}).(string)
+	return ip
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/audit"
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/pagination"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+const defaultLimit = 50
+
+// Handler is the router of the audit log.
+type Handler struct {
+	service audit.Service
+	log     logger.Logger
+}
+
+// parseQuery returns the query of the request: actor_id, target_id,
+// target_type, action, and from and to in RFC 3339.
+func parseQuery(r *http.Request) (audit.Query, error) {
+	var (
+		q   audit.Query
+		err error
+	)
+	values := r.URL.Query()
+
+	if v := values.Get("actor_id"); v != "" {
+		if q.ActorID, err = primitive.ObjectIDFromHex(v); err != nil {
+			return audit.Query{}, audit.ErrInvalidQuery
+		}
+	}
+
+	if v := values.Get("target_id"); v != "" {
+		if q.TargetID, err = primitive.ObjectIDFromHex(v); err != nil {
+			return audit.Query{}, audit.ErrInvalidQuery
+		}
+	}
+
+	if v := values.Get("from"); v != "" {
+		if q.From, err = time.Parse(time.RFC3339, v); err != nil {
+			return audit.Query{}, audit.ErrInvalidQuery
+		}
+	}
+
+	if v := values.Get("to"); v != "" {
+		if q.To, err = time.Parse(time.RFC3339, v); err != nil {
+			return audit.Query{}, audit.ErrInvalidQuery
+		}
+	}
+
+	q.TargetType = values.Get("target_type")
+	q.Action = audit.Action(values.Get("action"))
+
+	return q, nil
+}
+
+// GetAllHandler response a page of the entries matching the query.
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		entries []audit.Entry
+		total   int64
+	)
+
+	q, err := parseQuery(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	page, limit, ok := pagination.GetPagination(r)
+	if !ok {
+		page, limit = 1, defaultLimit
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		entries, total, err = h.service.GetAll(ctx, q, page, limit)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, response.ErrorInternalServerError)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{
+		"entries": entries,
+		"total":   total,
+	})
+}
+
+// Routes configure and return the routes of the audit log.
+func (h *Handler) Routes(a *auth.Authenticator) http.Handler {
+	r := chi.NewRouter()
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Super)).
+		Get("/", h.GetAllHandler)
+
+	return r
+}
+
+// New create and configure a new Handler.
+func New(service audit.Service, log logger.Logger) *Handler {
+	return &Handler{
+		log:     log,
+		service: service,
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/audit"
+	mock "github.com/Zucke/social_prove/pkg/audit/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+)
+
+func TestHandler_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	actorID := primitive.NewObjectID()
+	from := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query string
+		want  audit.Query
+		code  int
+		times int
+	}{
+		{
+			name:  "Success",
+			query: "?actor_id=" + actorID.Hex() + "&action=user.delete&from=2021-01-02T03:04:05Z",
+			want:  audit.Query{ActorID: actorID, Action: audit.DeleteUser, From: from},
+			code:  http.StatusOK,
+			times: 1,
+		},
+		{
+			name:  "Failure bad actor",
+			query: "?actor_id=1234",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "Failure bad time",
+			query: "?to=yesterday",
+			code:  http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetAll(gomock.Any(), test.want, 1, defaultLimit).
+				Return([]audit.Entry{}, int64(0), nil).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/audit/"+test.query, nil)
+
+			mux := chi.NewRouter()
+			mux.Get("/audit/", h.GetAllHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository handle the storage of the entries, it's append only. The one
+// exception is Anonymize: the erasure of a user, which the GDPR requires,
+// removes it from the entries while the entries themselves are kept.
+type Repository interface {
+	Create(ctx context.Context, e *Entry) error
+	GetAll(ctx context.Context, q Query, skip, limit int64) ([]Entry, int64, error)
+	Anonymize(ctx context.Context, userID primitive.ObjectID) error
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/audit"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// Repository storage to the audit entries.
+type Repository struct {
+	coll *mongo.Collection
+	log  logger.Logger
+}
+
+// Create store a new entry.
+func (r *Repository) Create(ctx context.Context, e *audit.Entry) error {
+	_, err := r.coll.InsertOne(ctx, e)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrCouldNotInsert
+	}
+
+	return nil
+}
+
+// filter returns the mongo filter of a query.
+func filter(q audit.Query) bson.M {
+	f := bson.M{}
+
+	if !q.ActorID.IsZero() {
+		f["actor_id"] = q.ActorID
+	}
+
+	if !q.TargetID.IsZero() {
+		f["target_id"] = q.TargetID
+	}
+
+	if q.TargetType != "" {
+		f["target_type"] = q.TargetType
+	}
+
+	if q.Action != "" {
+		f["action"] = q.Action
+	}
+
+	createdAt := bson.M{}
+	if !q.From.IsZero() {
+		createdAt["$gte"] = q.From
+	}
+
+	if !q.To.IsZero() {
+		createdAt["$lt"] = q.To
+	}
+
+	if len(createdAt) > 0 {
+		f["created_at"] = createdAt
+	}
+
+	return f
+}
+
+// GetAll returns a page of the entries matching the query, the newest
+// first.
+func (r *Repository) GetAll(ctx context.Context, q audit.Query, skip, limit int64) ([]audit.Entry, int64, error) {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: filter(q)}},
+		bson.D{{Key: "$sort", Value: bson.M{"created_at": -1}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"total": bson.A{
+				bson.M{"$count": "count"},
+			},
+			"entries": bson.A{
+				bson.M{"$skip": skip},
+				bson.M{"$limit": limit},
+			},
+		}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, 0, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	result := struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Entries []audit.Entry `bson:"entries"`
+	}{}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			return nil, 0, response.ErrorInternalServerError
+		}
+	}
+
+	entries := result.Entries
+	if entries == nil {
+		entries = make([]audit.Entry, 0)
+	}
+
+	var total int64
+	if len(result.Total) > 0 {
+		total = result.Total[0].Count
+	}
+
+	return entries, total, nil
+}
+
+// Anonymize unset an erased user as the actor of the entries and remove
+// the snapshots of the entries targeting it, the entries themselves are
+// kept. It's the only update of the entries, for the right to erasure.
+func (r *Repository) Anonymize(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.coll.UpdateMany(ctx, bson.M{"actor_id": userID}, bson.M{
+		"$unset": bson.M{"actor_id": ""},
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	targetFilter := bson.M{
+		"target_type": audit.UserTarget,
+		"target_id":   userID,
+	}
+	_, err = r.coll.UpdateMany(ctx, targetFilter, bson.M{
+		"$unset": bson.M{
+			"target_id": "",
+			"before":    "",
+			"after":     "",
+		},
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// Mongo create a new Repository.
+func Mongo(coll *mongo.Collection, log logger.Logger) audit.Repository {
+	return &Repository{
+		coll: coll,
+		log:  log,
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/Zucke/social_prove/pkg/audit"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// keys returns the keys of doc.
+func keys(t *testing.T, doc bson.Raw) []string {
+	elems, err := doc.Elements()
+	assert.NoError(t, err)
+
+	keys := make([]string, 0, len(elems))
+	for _, e := range elems {
+		keys = append(keys, e.Key())
+	}
+
+	return keys
+}
+
+func TestRepository_Anonymize(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	userID := primitive.NewObjectID()
+
+	mt.Run("Success", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		r := Mongo(mt.Coll, logger.NewMock())
+
+		assert.NoError(mt, r.Anonymize(context.Background(), userID))
+
+		// Only the references to the user are removed, no entry is deleted.
+		events := mt.GetAllStartedEvents()
+		assert.Len(mt, events, 2)
+		for _, e := range events {
+			assert.Equal(mt, "update", e.CommandName)
+		}
+
+		updates := func(i int) bson.Raw {
+			return events[i].Command.Lookup("updates").Array().Index(0).Value().Document()
+		}
+
+		actor := updates(0)
+		assert.Equal(mt, userID, actor.Lookup("q", "actor_id").ObjectID())
+		assert.Equal(mt, []string{"actor_id"}, keys(mt.T, actor.Lookup("u", "$unset").Document()))
+		assert.True(mt, actor.Lookup("multi").Boolean())
+
+		target := updates(1)
+		assert.Equal(mt, audit.UserTarget, target.Lookup("q", "target_type").StringValue())
+		assert.Equal(mt, userID, target.Lookup("q", "target_id").ObjectID())
+		assert.ElementsMatch(mt, []string{"target_id", "before", "after"}, keys(mt.T, target.Lookup("u", "$unset").Document()))
+		assert.True(mt, target.Lookup("multi").Boolean())
+	})
+
+	mt.Run("Failure", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "failed"}))
+		r := Mongo(mt.Coll, logger.NewMock())
+
+		assert.Equal(mt, response.ErrorInternalServerError, r.Anonymize(context.Background(), userID))
+	})
+}
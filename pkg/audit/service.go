@@ -0,0 +1,16 @@
+package audit
+
+import (
+	"context"
+)
+
+// Recorder records the administrative actions.
+type Recorder interface {
+	Record(ctx context.Context, e Entry) error
+}
+
+// Service the audit service.
+type Service interface {
+	Recorder
+	GetAll(ctx context.Context, q Query, page, limit int) ([]Entry, int64, error)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/audit"
+	"github.com/Zucke/social_prove/pkg/audit/repository"
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/tracing"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+const (
+	maxLimit = 100
+)
+
+// AuditService the audit service.
+type AuditService struct {
+	repository audit.Repository
+	timeout    time.Duration
+	log        logger.Logger
+}
+
+// Record store an entry with the actor, the request ID and the IP of the
+// request in ctx.
+func (as *AuditService) Record(ctx context.Context, e audit.Entry) error {
+	ctx, span := tracing.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, as.timeout)
+	defer cancel()
+
+	if id, ok := ctx.Value(auth.IDKey).(primitive.ObjectID); ok {
+		e.ActorID = id
+	}
+
+	if role, ok := ctx.Value(auth.RoleKey).(user.Role); ok {
+		e.ActorRole = role
+	}
+
+	e.ID = primitive.NewObjectID()
+	e.RequestID = middleware.GetReqID(ctx)
+	e.IP = audit.IP(ctx)
+	e.CreatedAt = time.Now()
+
+	if err := as.repository.Create(ctx, &e); err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetAll returns a page of the entries matching the query, the newest
+// first, with the total of matching entries.
+func (as *AuditService) GetAll(ctx context.Context, q audit.Query, page, limit int) ([]audit.Entry, int64, error) {
+	ctx, span := tracing.Start(ctx, "AuditService.GetAll")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, as.timeout)
+	defer cancel()
+
+	if limit < 1 {
+		limit = 1
+	}
+
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	entries, total, err := as.repository.GetAll(ctx, q, int64((page-1)*limit), int64(limit))
+	if err != nil {
+		as.log.WithContext(ctx).Error(err)
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// New create a new AuditService.
+func New(coll *mongo.Collection, log logger.Logger, timeout time.Duration) audit.Service {
+	return &AuditService{
+		repository: repository.Mongo(coll, log),
+		log:        log,
+		timeout:    timeout,
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/audit"
+	mock "github.com/Zucke/social_prove/pkg/audit/mock"
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockRepository(ctrl)
+	actorID := primitive.NewObjectID()
+	targetID := primitive.NewObjectID()
+
+	ctx := context.WithValue(context.Background(), auth.IDKey, actorID)
+	ctx = context.WithValue(ctx, auth.RoleKey, user.Admin)
+	ctx = context.WithValue(ctx, middleware.RequestIDKey, "req-1")
+
+	m.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, e *audit.Entry) error {
+			assert.False(t, e.ID.IsZero())
+			assert.Equal(t, actorID, e.ActorID)
+			assert.Equal(t, user.Admin, e.ActorRole)
+			assert.Equal(t, audit.DeleteUser, e.Action)
+			assert.Equal(t, targetID, e.TargetID)
+			assert.Equal(t, "req-1", e.RequestID)
+			assert.False(t, e.CreatedAt.IsZero())
+			return nil
+		}).
+		Times(1)
+
+	s := AuditService{
+		repository: m,
+		log:        logger.NewMock(),
+	}
+
+	err := s.Record(ctx, audit.Entry{
+		Action:     audit.DeleteUser,
+		TargetType: audit.UserTarget,
+		TargetID:   targetID,
+	})
+	assert.NoError(t, err)
+}
+
+func TestAuditService_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockRepository(ctrl)
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name      string
+		page      int
+		limit     int
+		wantSkip  int64
+		wantLimit int64
+	}{
+		{
+			name:      "succes",
+			page:      3,
+			limit:     20,
+			wantSkip:  40,
+			wantLimit: 20,
+		},
+		{
+			name:      "succes limit too big",
+			page:      1,
+			limit:     1000,
+			wantSkip:  0,
+			wantLimit: maxLimit,
+		},
+		{
+			name:      "succes bad page",
+			page:      0,
+			limit:     0,
+			wantSkip:  0,
+			wantLimit: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetAll(gomock.Any(), audit.Query{}, test.wantSkip, test.wantLimit).
+				Return([]audit.Entry{}, int64(0), nil).
+				Times(1)
+
+			s := AuditService{
+				repository: m,
+				log:        l,
+			}
+
+			_, _, err := s.GetAll(ctx, audit.Query{}, test.page, test.limit)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -2,11 +2,11 @@ package auth
 
 import (
 	"context"
-	"errors"
 	"net/http"
-	"os"
+	"time"
 
 	"github.com/Zucke/social_prove/pkg/claim"
+	"github.com/Zucke/social_prove/pkg/logger"
 	"github.com/Zucke/social_prove/pkg/response"
 	"github.com/Zucke/social_prove/pkg/user"
 	"github.com/go-chi/chi"
@@ -26,14 +26,50 @@ const (
 
 //Errors
 var (
-	ErrUserNotAuthorized      = errors.New("not authorized")
-	ErrInsufficientPrivileges = errors.New("insufficient privileges")
-	ErrIDNotFound             = errors.New("user ID not found")
-	ErrIDNoValid              = errors.New("user ID is not valid")
-	ErrRoleNotFound           = errors.New("user role not found")
-	ErrRoleNoValid            = errors.New("user role is not valid")
+	ErrUserNotAuthorized      = response.NewError(response.Unauthenticated, "not authorized")
+	ErrInsufficientPrivileges = response.NewError(response.Forbidden, "insufficient privileges")
+	ErrIDNotFound             = response.NewError(response.Unauthenticated, "user ID not found")
+	ErrIDNoValid              = response.NewError(response.Unauthenticated, "user ID is not valid")
+	ErrRoleNotFound           = response.NewError(response.Unauthenticated, "user role not found")
+	ErrRoleNoValid            = response.NewError(response.Unauthenticated, "user role is not valid")
+	ErrNotConfigured          = response.NewError(response.Unavailable, "signing string not configured")
+	ErrClientCertRequired     = response.NewError(response.Forbidden, "client certificate required")
 )
 
+// Users looks up the users of the tokens.
+type Users interface {
+	GetByID(ctx context.Context, id primitive.ObjectID) (user.User, error)
+}
+
+// Authenticator verifies the tokens of the requests and the roles of their
+// users.
+type Authenticator struct {
+	signingString string
+	users         Users
+	adminCert     bool
+}
+
+// New create an Authenticator of the tokens signed with signingString. With
+// users it rejects the tokens of the users deleted, deactivated or
+// suspended after the token was issued, with adminCert the admins require a
+// client certificate verified by the server on the routes checking the roles.
+func New(signingString string, users Users, adminCert bool) *Authenticator {
+	return &Authenticator{
+		signingString: signingString,
+		users:         users,
+		adminCert:     adminCert,
+	}
+}
+
+// Configured check the Authenticator can verify the tokens.
+func (a *Authenticator) Configured(ctx context.Context) error {
+	if a.signingString == "" {
+		return ErrNotConfigured
+	}
+
+	return nil
+}
+
 // GetID returns user ID from the request context.
 func GetID(r *http.Request) (id string, err error) {
 	iID := r.Context().Value(IDKey)
@@ -65,39 +101,84 @@ func GetRole(r *http.Request) (role user.Role, err error) {
 	return userRole, nil
 }
 
-// Authenticator is an authentication middleware.
-func Authenticator(next http.Handler) http.Handler {
-	signingString := os.Getenv("SIGNING_STRING")
+// Authenticate is an authentication middleware.
+func (a *Authenticator) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString, err := claim.TokenFromAuthorization(r)
+		if err != nil {
+			_ = response.Error(w, response.WithKind(response.Unauthenticated, err))
+			return
+		}
+
+		c, err := claim.GetFromToken(tokenString, a.signingString)
+		if err != nil {
+			_ = response.Error(w, response.WithKind(response.Unauthenticated, err))
+			return
+		}
+
+		id, err := primitive.ObjectIDFromHex(c.ID)
+		if err != nil {
+			_ = response.Error(w, ErrIDNoValid)
+			return
+		}
+
+		if a.users != nil {
+			u, err := a.users.GetByID(r.Context(), id)
+			if err != nil {
+				_ = response.Error(w, ErrUserNotAuthorized)
+				return
+			}
+
+			if err := u.CheckStatus(time.Now()); err != nil {
+				_ = response.Error(w, err)
+				return
+			}
+		}
+
+		logger.SetUser(r.Context(), c.ID)
+
+		ctx := context.WithValue(r.Context(), RoleKey, user.Role(c.Role))
+		ctx = context.WithValue(ctx, IDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Identify is an optional Authenticate, the requests with a valid token get
+// its ID and role and the others go through as they are. The status of the
+// user isn't checked, the routes of the users still need Authenticate.
+func (a *Authenticator) Identify(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		tokenString, err := claim.TokenFromAuthorization(r)
 		if err != nil {
-			_ = response.HTTPError(w, http.StatusUnauthorized, err.Error())
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		c, err := claim.GetFromToken(tokenString, a.signingString)
+		if err != nil {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		c, err := claim.GetFromToken(tokenString, signingString)
+		id, err := primitive.ObjectIDFromHex(c.ID)
 		if err != nil {
-			_ = response.HTTPError(w, http.StatusUnauthorized, err.Error())
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), RoleKey, c.Role)
-		ctx = context.WithValue(ctx, IDKey, c.ID)
+		ctx := context.WithValue(r.Context(), RoleKey, user.Role(c.Role))
+		ctx = context.WithValue(ctx, IDKey, id)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
 // WithRole validate user role from request context.
-func WithRole(roles ...user.Role) func(next http.Handler) http.Handler {
+func (a *Authenticator) WithRole(roles ...user.Role) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			role, err := GetRole(r)
 			if err != nil {
-				_ = response.HTTPError(
-					w,
-					http.StatusUnauthorized,
-					ErrInsufficientPrivileges.Error(),
-				)
+				_ = response.Error(w, err)
 				return
 			}
 
@@ -112,11 +193,14 @@ func WithRole(roles ...user.Role) func(next http.Handler) http.Handler {
 			}
 
 			if !isInRoles(role, roles) {
-				_ = response.HTTPError(
-					w,
-					http.StatusUnauthorized,
-					ErrInsufficientPrivileges.Error(),
-				)
+				_ = response.Error(w, ErrInsufficientPrivileges)
+				return
+			}
+
+			// The admins use their powers on the routes shared with the
+			// clients as well.
+			if a.adminCert && role >= user.Admin && !hasClientCert(r) {
+				_ = response.Error(w, ErrClientCertRequired)
 				return
 			}
 
@@ -126,17 +210,19 @@ func WithRole(roles ...user.Role) func(next http.Handler) http.Handler {
 	}
 }
 
+// hasClientCert reports whether the client of r gave a certificate the
+// server verified.
+func hasClientCert(r *http.Request) bool {
+	return r.TLS != nil && len(r.TLS.VerifiedChains) > 0
+}
+
 // WithID validate user id from the request context.
 func WithID(id ...string) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			userID, err := GetID(r)
 			if err != nil {
-				_ = response.HTTPError(
-					w,
-					http.StatusUnauthorized,
-					ErrUserNotAuthorized.Error(),
-				)
+				_ = response.Error(w, ErrUserNotAuthorized)
 				return
 			}
 
@@ -144,11 +230,7 @@ func WithID(id ...string) func(next http.Handler) http.Handler {
 
 			userRole, err := GetRole(r)
 			if err != nil {
-				_ = response.HTTPError(
-					w,
-					http.StatusUnauthorized,
-					ErrUserNotAuthorized.Error(),
-				)
+				_ = response.Error(w, ErrUserNotAuthorized)
 				return
 			}
 
@@ -160,11 +242,7 @@ func WithID(id ...string) func(next http.Handler) http.Handler {
 			}
 
 			if checkID != userID && userRole < user.Admin {
-				_ = response.HTTPError(
-					w,
-					http.StatusUnauthorized,
-					ErrInsufficientPrivileges.Error(),
-				)
+				_ = response.Error(w, ErrInsufficientPrivileges)
 				return
 			}
 
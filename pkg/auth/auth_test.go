@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/claim"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+	mock "github.com/Zucke/social_prove/pkg/user/mock"
+)
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	const signingString = "secret"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockRepository(ctrl)
+	a := New(signingString, m, false)
+
+	id := primitive.NewObjectID()
+	until := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		stored user.User
+		getErr error
+		code   int
+	}{
+		{
+			name:   "Active user",
+			stored: user.User{ID: id, Role: user.Admin, Active: true},
+			code:   http.StatusOK,
+		},
+		{
+			name:   "Deactivated user",
+			stored: user.User{ID: id, Active: false},
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "Suspended user",
+			stored: user.User{ID: id, Active: true, Suspension: &user.Suspension{Reason: "spam", Until: &until}},
+			code:   http.StatusForbidden,
+		},
+		{
+			name:   "Deleted user",
+			getErr: response.ErrorNotFound,
+			code:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), id).
+				Return(test.stored, test.getErr).
+				Times(1)
+
+			token, err := claim.GenerateToken(signingString, id.Hex(), uint(user.Admin))
+			assert.NoError(t, err)
+
+			var (
+				gotID   string
+				gotRole user.Role
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotID, _ = GetID(r)
+				gotRole, _ = GetRole(r)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Authorization", "Bearer "+token)
+
+			a.Authenticate(next).ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+			if test.code == http.StatusOK {
+				assert.Equal(t, id.Hex(), gotID)
+				assert.Equal(t, user.Admin, gotRole)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Identify(t *testing.T) {
+	const signingString = "secret"
+	a := New(signingString, nil, false)
+
+	id := primitive.NewObjectID()
+	token, err := claim.GenerateToken(signingString, id.Hex(), uint(user.Admin))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		authorization string
+		id            string
+	}{
+		{
+			name:          "Valid token",
+			authorization: "Bearer " + token,
+			id:            id.Hex(),
+		},
+		{
+			name:          "Invalid token",
+			authorization: "Bearer " + token + "x",
+		},
+		{
+			name: "Without token",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var gotID string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotID, _ = GetID(r)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.authorization != "" {
+				r.Header.Set("Authorization", test.authorization)
+			}
+
+			a.Identify(next).ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, test.id, gotID)
+		})
+	}
+}
+
+func TestAuthenticator_WithRole(t *testing.T) {
+	a := New("secret", nil, true)
+
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+
+	tests := []struct {
+		name  string
+		roles []user.Role
+		role  user.Role
+		tls   *tls.ConnectionState
+		code  int
+	}{
+		{
+			name:  "Admin route with certificate",
+			roles: []user.Role{user.Admin, user.Super},
+			role:  user.Admin,
+			tls:   verified,
+			code:  http.StatusOK,
+		},
+		{
+			name:  "Admin route without certificate",
+			roles: []user.Role{user.Admin, user.Super},
+			role:  user.Admin,
+			tls:   &tls.ConnectionState{},
+			code:  http.StatusForbidden,
+		},
+		{
+			name:  "Admin route without TLS",
+			roles: []user.Role{user.Super},
+			role:  user.Super,
+			code:  http.StatusForbidden,
+		},
+		{
+			name:  "Shared route client without certificate",
+			roles: []user.Role{user.Client, user.Admin, user.Super},
+			role:  user.Client,
+			code:  http.StatusOK,
+		},
+		{
+			name:  "Shared route admin with certificate",
+			roles: []user.Role{user.Client, user.Admin, user.Super},
+			role:  user.Admin,
+			tls:   verified,
+			code:  http.StatusOK,
+		},
+		{
+			// The admin powers of the shared routes require the certificate.
+			name:  "Shared route admin without certificate",
+			roles: []user.Role{user.Client, user.Admin, user.Super},
+			role:  user.Admin,
+			tls:   &tls.ConnectionState{},
+			code:  http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(context.WithValue(r.Context(), RoleKey, test.role))
+			r.TLS = test.tls
+
+			a.WithRole(test.roles...)(next).ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
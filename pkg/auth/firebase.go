@@ -27,13 +27,13 @@ func (fa *FirebaseAuth) WithToken(next http.Handler) http.Handler {
 		defer cancel()
 		authString, err := claim.TokenFromAuthorization(r)
 		if err != nil {
-			_ = response.HTTPError(w, http.StatusForbidden, err.Error())
+			_ = response.Error(w, response.WithKind(response.Unauthenticated, err))
 			return
 		}
 
 		token, err := fa.client.VerifyIDTokenAndCheckRevoked(ctx, authString)
 		if err != nil {
-			_ = response.HTTPError(w, http.StatusForbidden, err.Error())
+			_ = response.Error(w, response.WithKind(response.Unauthenticated, err))
 			return
 		}
 
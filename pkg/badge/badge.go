@@ -0,0 +1,102 @@
+package badge
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// Kind of badge.
+type Kind string
+
+// Badge kinds, post badges are picked by the users for their posts while
+// achievements are awarded by rules.
+const (
+	PostBadge   Kind = "post"
+	Achievement Kind = "achievement"
+)
+
+// Metric counted by the achievement rules.
+type Metric string
+
+// Rule metrics.
+const (
+	Posts             Metric = "posts"
+	Trips             Metric = "trips"
+	LikesReceived     Metric = "likes_received"
+	ReactionsReceived Metric = "reactions_received"
+	Followers         Metric = "followers"
+)
+
+// Metrics all the valid rule metrics.
+var Metrics = []Metric{Posts, Trips, LikesReceived, ReactionsReceived, Followers}
+
+// Errors.
+var (
+	ErrInvalidBadge = response.NewError(response.Invalid, "invalid badge")
+	ErrInvalidKind  = response.NewError(response.Invalid, "invalid badge kind")
+	ErrInvalidRule  = response.NewError(response.Invalid, "invalid badge rule")
+	ErrNameTaken    = response.NewError(response.Conflict, "badge name already in use")
+)
+
+// Rule awards an achievement once the metric of the user reaches the threshold.
+type Rule struct {
+	Metric    Metric `json:"metric" bson:"metric"`
+	Threshold int64  `json:"threshold" bson:"threshold"`
+}
+
+// Badge is an entry of the badge catalog.
+type Badge struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name        string             `json:"name,omitempty" bson:"name,omitempty"`
+	Icon        string             `json:"icon,omitempty" bson:"icon,omitempty"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Kind        Kind               `json:"kind,omitempty" bson:"kind,omitempty"`
+	Rule        *Rule              `json:"rule,omitempty" bson:"rule,omitempty"`
+	CreatedAt   time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt   time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// Award is an achievement earned by a user.
+type Award struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	BadgeID   primitive.ObjectID `json:"badge_id,omitempty" bson:"badge_id,omitempty"`
+	Badge     *Badge             `json:"badge,omitempty" bson:"badge,omitempty"`
+	AwardedAt time.Time          `json:"awarded_at,omitempty" bson:"awarded_at,omitempty"`
+}
+
+// Valid reports whether m is a known metric.
+func (m Metric) Valid() bool {
+	for _, metric := range Metrics {
+		if m == metric {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate check the badge fields according to its kind.
+func (b Badge) Validate() error {
+	if b.Name == "" {
+		return ErrInvalidBadge
+	}
+
+	switch b.Kind {
+	case PostBadge:
+		if b.Rule != nil {
+			return ErrInvalidRule
+		}
+	case Achievement:
+		if b.Rule == nil || !b.Rule.Metric.Valid() || b.Rule.Threshold < 1 {
+			return ErrInvalidRule
+		}
+	default:
+		return ErrInvalidKind
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package badge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadge_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		badge Badge
+		err   error
+	}{
+		{
+			name:  "post badge",
+			badge: Badge{Name: "Explorer", Kind: PostBadge},
+		},
+		{
+			name:  "achievement",
+			badge: Badge{Name: "Traveler", Kind: Achievement, Rule: &Rule{Metric: Trips, Threshold: 10}},
+		},
+		{
+			name:  "without name",
+			badge: Badge{Kind: PostBadge},
+			err:   ErrInvalidBadge,
+		},
+		{
+			name:  "unknown kind",
+			badge: Badge{Name: "Explorer", Kind: Kind("other")},
+			err:   ErrInvalidKind,
+		},
+		{
+			name:  "post badge with rule",
+			badge: Badge{Name: "Explorer", Kind: PostBadge, Rule: &Rule{Metric: Trips, Threshold: 10}},
+			err:   ErrInvalidRule,
+		},
+		{
+			name:  "achievement without rule",
+			badge: Badge{Name: "Traveler", Kind: Achievement},
+			err:   ErrInvalidRule,
+		},
+		{
+			name:  "achievement unknown metric",
+			badge: Badge{Name: "Traveler", Kind: Achievement, Rule: &Rule{Metric: Metric("km"), Threshold: 10}},
+			err:   ErrInvalidRule,
+		},
+		{
+			name:  "achievement without threshold",
+			badge: Badge{Name: "Traveler", Kind: Achievement, Rule: &Rule{Metric: Trips}},
+			err:   ErrInvalidRule,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.err, test.badge.Validate())
+		})
+	}
+}
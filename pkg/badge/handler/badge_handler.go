@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/badge"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+// Handler is the router of the badges.
+type Handler struct {
+	service badge.Service
+	log     logger.Logger
+}
+
+// GetAllHandler response the badge catalog, optionally filtered by kind.
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		badges []badge.Badge
+		err    error
+	)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		badges, err = h.service.GetAll(ctx, badge.Kind(r.URL.Query().Get("kind")))
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{
+		"badges": badges,
+		"total":  len(badges),
+	})
+}
+
+// GetOneHandler response one badge by id.
+func (h *Handler) GetOneHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		b   badge.Badge
+		err error
+	)
+	id := chi.URLParam(r, "id")
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		b, err = h.service.GetByID(ctx, id)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{"badge": b})
+}
+
+// CreateHandler add a badge to the catalog.
+func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	var b badge.Badge
+
+	err := validation.Decode(r.Body, &b)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		err = h.service.Create(ctx, &b)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusCreated, render.M{"badge": b})
+}
+
+// UpdateHandler update a badge of the catalog by id.
+func (h *Handler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
+	var b, updatedBadge badge.Badge
+	id := chi.URLParam(r, "id")
+
+	err := validation.Decode(r.Body, &b)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		updatedBadge, err = h.service.Update(ctx, id, &b)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{"badge": updatedBadge})
+}
+
+// DeleteHandler remove a badge of the catalog by id.
+func (h *Handler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		err = h.service.Delete(ctx, id)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	render.JSON(w, r, render.M{})
+}
+
+// GetAwardsHandler response the achievements earned by a user.
+func (h *Handler) GetAwardsHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		awards []badge.Award
+		err    error
+	)
+	id := chi.URLParam(r, "id")
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		awards, err = h.service.GetAwards(ctx, id)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{
+		"badges": awards,
+		"total":  len(awards),
+	})
+}
+
+// Routes configure and return the routes of the badge catalog.
+func (h *Handler) Routes(a *auth.Authenticator) http.Handler {
+	r := chi.NewRouter()
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Get("/", h.GetAllHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Get("/{id}", h.GetOneHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Post("/", h.CreateHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Put("/{id}", h.UpdateHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Delete("/{id}", h.DeleteHandler)
+
+	return r
+}
+
+// UserRoutes configure and return the routes of the achievements of a
+// user, mounted on the user router.
+func (h *Handler) UserRoutes(a *auth.Authenticator) http.Handler {
+	r := chi.NewRouter()
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Get("/", h.GetAwardsHandler)
+
+	return r
+}
+
+// New create and configure a new Handler.
+func New(service badge.Service, log logger.Logger) *Handler {
+	return &Handler{
+		log:     log,
+		service: service,
+	}
+}
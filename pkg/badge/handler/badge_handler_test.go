@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Zucke/social_prove/pkg/badge"
+	mock "github.com/Zucke/social_prove/pkg/badge/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestHandler_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+
+	b := badge.Badge{
+		Name: "Explorer",
+		Kind: badge.PostBadge,
+	}
+
+	tests := []struct {
+		name  string
+		body  io.Reader
+		code  int
+		err   error
+		times int
+	}{
+		{
+			name:  "Success",
+			body:  strings.NewReader(`{"name":"Explorer","kind":"post"}`),
+			code:  http.StatusCreated,
+			err:   nil,
+			times: 1,
+		},
+		{
+			name:  "Failure bad request",
+			body:  strings.NewReader(`{"name":`),
+			code:  http.StatusBadRequest,
+			err:   nil,
+			times: 0,
+		},
+		{
+			name:  "Failure invalid kind",
+			body:  strings.NewReader(`{"name":"Explorer","kind":"post"}`),
+			code:  http.StatusBadRequest,
+			err:   badge.ErrInvalidKind,
+			times: 1,
+		},
+		{
+			name:  "Failure name taken",
+			body:  strings.NewReader(`{"name":"Explorer","kind":"post"}`),
+			code:  http.StatusConflict,
+			err:   badge.ErrNameTaken,
+			times: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Create(gomock.Any(), &b).
+				Return(test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/badge/", test.body)
+
+			mux := chi.NewRouter()
+			mux.Post("/badge/", h.CreateHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetAwards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id := primitive.NewObjectID()
+
+	awards := []badge.Award{{UserID: id, BadgeID: primitive.NewObjectID()}}
+
+	tests := []struct {
+		name  string
+		code  int
+		err   error
+		times int
+	}{
+		{
+			name:  "Success",
+			code:  http.StatusOK,
+			err:   nil,
+			times: 1,
+		},
+		{
+			name:  "Failure bad id",
+			code:  http.StatusBadRequest,
+			err:   response.ErrInvalidID,
+			times: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetAwards(gomock.Any(), id.Hex()).
+				Return(awards, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/user/"+id.Hex()+"/badges", nil)
+
+			mux := chi.NewRouter()
+			mux.Get("/user/{id}/badges", h.GetAwardsHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
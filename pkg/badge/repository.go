@@ -0,0 +1,24 @@
+package badge
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository handle the storage of the badge catalog and the awards.
+type Repository interface {
+	Create(ctx context.Context, b *Badge) error
+	GetAll(ctx context.Context, kind Kind) ([]Badge, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (Badge, error)
+	Update(ctx context.Context, id primitive.ObjectID, b *Badge) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	Award(ctx context.Context, a *Award) (bool, error)
+	GetAwards(ctx context.Context, userID primitive.ObjectID) ([]Award, error)
+	DeleteAwards(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// Counter counts the metrics of a user evaluated by the achievement rules.
+type Counter interface {
+	Count(ctx context.Context, userID primitive.ObjectID, metric Metric) (int64, error)
+}
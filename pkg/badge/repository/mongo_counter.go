@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/badge"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/post"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// Counter counts the metrics of the users from the stored documents.
+type Counter struct {
+	posts *mongo.Collection
+	trips *mongo.Collection
+	users *mongo.Collection
+	log   logger.Logger
+}
+
+// Count returns the value of a metric for a user.
+func (c *Counter) Count(ctx context.Context, userID primitive.ObjectID, metric badge.Metric) (int64, error) {
+	var (
+		n   int64
+		err error
+	)
+
+	switch metric {
+	case badge.Posts:
+		n, err = c.posts.CountDocuments(ctx, bson.M{
+			"user_id":    userID,
+			"status":     bson.M{"$in": bson.A{nil, post.Published}},
+			"deleted_at": bson.M{"$exists": false},
+		})
+	case badge.Trips:
+		n, err = c.trips.CountDocuments(ctx, bson.M{"user_id": userID})
+	case badge.Followers:
+		n, err = c.users.CountDocuments(ctx, bson.M{
+			"following":  userID,
+			"deleted_at": bson.M{"$exists": false},
+		})
+	case badge.LikesReceived:
+		n, err = c.sumReactions(ctx, userID, "$reactions."+string(reaction.Like))
+	case badge.ReactionsReceived:
+		n, err = c.sumReactions(ctx, userID, bson.M{"$sum": bson.M{"$map": bson.M{
+			"input": bson.M{"$objectToArray": bson.M{"$ifNull": bson.A{"$reactions", bson.M{}}}},
+			"in":    "$$this.v",
+		}}})
+	default:
+		return 0, badge.ErrInvalidRule
+	}
+
+	if err != nil {
+		c.log.WithContext(ctx).Error(err)
+		return 0, response.ErrorInternalServerError
+	}
+
+	return n, nil
+}
+
+// sumReactions adds up the value of expr over the posts of a user.
+func (c *Counter) sumReactions(ctx context.Context, userID primitive.ObjectID, expr interface{}) (int64, error) {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{
+			"user_id":    userID,
+			"deleted_at": bson.M{"$exists": false},
+		}}},
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": expr},
+		}}},
+	}
+
+	cursor, err := c.posts.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+
+	defer cursor.Close(ctx)
+
+	result := struct {
+		Total int64 `bson:"total"`
+	}{}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			return 0, err
+		}
+	}
+
+	return result.Total, cursor.Err()
+}
+
+// MongoCounter create a new metric counter over the posts, trips and users.
+func MongoCounter(postColl, tripColl, userColl *mongo.Collection, log logger.Logger) badge.Counter {
+	return &Counter{
+		posts: postColl,
+		trips: tripColl,
+		users: userColl,
+		log:   log,
+	}
+}
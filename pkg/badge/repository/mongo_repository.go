@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Zucke/social_prove/pkg/badge"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// duplicateKeyCode is the mongo error code of a unique index violation.
+const duplicateKeyCode = 11000
+
+// Repository storage to the badge model.
+type Repository struct {
+	coll   *mongo.Collection
+	awards *mongo.Collection
+	log    logger.Logger
+}
+
+// Create store a new badge.
+func (r *Repository) Create(ctx context.Context, b *badge.Badge) error {
+	_, err := r.coll.InsertOne(ctx, b)
+	if isDuplicateKey(err) {
+		return badge.ErrNameTaken
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrCouldNotInsert
+	}
+
+	return nil
+}
+
+// GetAll returns the badges of a kind, every badge when kind is empty.
+func (r *Repository) GetAll(ctx context.Context, kind badge.Kind) ([]badge.Badge, error) {
+	badges := make([]badge.Badge, 0)
+
+	filter := bson.M{}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		b := badge.Badge{}
+		if err := cursor.Decode(&b); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		badges = append(badges, b)
+	}
+
+	return badges, nil
+}
+
+// GetByID returns a badge by ID.
+func (r *Repository) GetByID(ctx context.Context, id primitive.ObjectID) (badge.Badge, error) {
+	b := badge.Badge{}
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&b)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return badge.Badge{}, response.ErrorNotFound
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return badge.Badge{}, response.ErrorInternalServerError
+	}
+
+	return b, nil
+}
+
+// Update replace the editable fields of a badge.
+func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, b *badge.Badge) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":        b.Name,
+			"icon":        b.Icon,
+			"description": b.Description,
+			"kind":        b.Kind,
+			"rule":        b.Rule,
+			"updated_at":  time.Now(),
+		},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if isDuplicateKey(err) {
+		return badge.ErrNameTaken
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if result.MatchedCount == 0 {
+		return response.ErrorNotFound
+	}
+
+	return nil
+}
+
+// Delete remove a badge by ID with its awards.
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if result.DeletedCount == 0 {
+		return response.ErrorNotFound
+	}
+
+	_, err = r.awards.DeleteMany(ctx, bson.M{"badge_id": id})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// Award store an award, it returns false when the user already had it.
+func (r *Repository) Award(ctx context.Context, a *badge.Award) (bool, error) {
+	_, err := r.awards.InsertOne(ctx, a)
+	if isDuplicateKey(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return false, response.ErrCouldNotInsert
+	}
+
+	return true, nil
+}
+
+// GetAwards returns the awards of a user with their badge.
+func (r *Repository) GetAwards(ctx context.Context, userID primitive.ObjectID) ([]badge.Award, error) {
+	awards := make([]badge.Award, 0)
+
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		bson.D{{Key: "$sort", Value: bson.M{"awarded_at": 1}}},
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         r.coll.Name(),
+			"localField":   "badge_id",
+			"foreignField": "_id",
+			"as":           "badge",
+		}}},
+		bson.D{{Key: "$unwind", Value: "$badge"}},
+	}
+
+	cursor, err := r.awards.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		a := badge.Award{}
+		if err := cursor.Decode(&a); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		awards = append(awards, a)
+	}
+
+	return awards, nil
+}
+
+// DeleteAwards remove every award of a user.
+func (r *Repository) DeleteAwards(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.awards.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// isDuplicateKey reports whether err is a unique index violation.
+func isDuplicateKey(err error) bool {
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if e.Code == duplicateKeyCode {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Mongo create a new badge repository.
+func Mongo(coll, awardColl *mongo.Collection, log logger.Logger) badge.Repository {
+	return &Repository{
+		coll:   coll,
+		awards: awardColl,
+		log:    log,
+	}
+}
@@ -0,0 +1,21 @@
+package badge
+
+import (
+	"context"
+)
+
+// Service the badge service.
+type Service interface {
+	Evaluator
+	Create(ctx context.Context, b *Badge) error
+	GetAll(ctx context.Context, kind Kind) ([]Badge, error)
+	GetByID(ctx context.Context, id string) (Badge, error)
+	Update(ctx context.Context, id string, b *Badge) (Badge, error)
+	Delete(ctx context.Context, id string) error
+	GetAwards(ctx context.Context, userID string) ([]Award, error)
+}
+
+// Evaluator awards the achievements a user earned.
+type Evaluator interface {
+	Evaluate(ctx context.Context, userID string) ([]Award, error)
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/audit"
+	"github.com/Zucke/social_prove/pkg/badge"
+	"github.com/Zucke/social_prove/pkg/badge/repository"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/tracing"
+)
+
+// BadgeService the badge service.
+type BadgeService struct {
+	repository badge.Repository
+	counter    badge.Counter
+	auditor    audit.Recorder
+	timeout    time.Duration
+	log        logger.Logger
+}
+
+// Create add a badge to the catalog.
+func (bs *BadgeService) Create(ctx context.Context, b *badge.Badge) error {
+	ctx, span := tracing.Start(ctx, "BadgeService.Create")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	if err := b.Validate(); err != nil {
+		return err
+	}
+
+	b.ID = primitive.NewObjectID()
+	b.CreatedAt = time.Now()
+	b.UpdatedAt = time.Now()
+
+	if err := bs.repository.Create(ctx, b); err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return err
+	}
+	bs.auditAction(ctx, audit.CreateBadge, b.ID, nil, b)
+
+	return nil
+}
+
+// GetAll returns the badges of the catalog, filtered by kind when not empty.
+func (bs *BadgeService) GetAll(ctx context.Context, kind badge.Kind) ([]badge.Badge, error) {
+	ctx, span := tracing.Start(ctx, "BadgeService.GetAll")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	if kind != "" && kind != badge.PostBadge && kind != badge.Achievement {
+		return nil, badge.ErrInvalidKind
+	}
+
+	badges, err := bs.repository.GetAll(ctx, kind)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return badges, nil
+}
+
+// GetByID returns a badge by ID.
+func (bs *BadgeService) GetByID(ctx context.Context, id string) (badge.Badge, error) {
+	ctx, span := tracing.Start(ctx, "BadgeService.GetByID")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return badge.Badge{}, response.ErrInvalidID
+	}
+
+	b, err := bs.repository.GetByID(ctx, objectID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return badge.Badge{}, err
+	}
+
+	return b, nil
+}
+
+// Update badge by ID.
+func (bs *BadgeService) Update(ctx context.Context, id string, b *badge.Badge) (badge.Badge, error) {
+	ctx, span := tracing.Start(ctx, "BadgeService.Update")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return badge.Badge{}, response.ErrInvalidID
+	}
+
+	if err := b.Validate(); err != nil {
+		return badge.Badge{}, err
+	}
+
+	before, err := bs.repository.GetByID(ctx, objectID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return badge.Badge{}, err
+	}
+
+	if err := bs.repository.Update(ctx, objectID, b); err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return badge.Badge{}, err
+	}
+
+	after, err := bs.GetByID(ctx, id)
+	if err != nil {
+		return badge.Badge{}, err
+	}
+	bs.auditAction(ctx, audit.UpdateBadge, objectID, &before, &after)
+
+	return after, nil
+}
+
+// Delete remove a badge from the catalog.
+func (bs *BadgeService) Delete(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "BadgeService.Delete")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return response.ErrInvalidID
+	}
+
+	before, err := bs.repository.GetByID(ctx, objectID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	if err := bs.repository.Delete(ctx, objectID); err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return err
+	}
+	bs.auditAction(ctx, audit.DeleteBadge, objectID, &before, nil)
+
+	return nil
+}
+
+// Evaluate award the achievements whose rule the user reached, it returns
+// the new awards.
+func (bs *BadgeService) Evaluate(ctx context.Context, userID string) ([]badge.Award, error) {
+	ctx, span := tracing.Start(ctx, "BadgeService.Evaluate")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return nil, response.ErrInvalidID
+	}
+
+	achievements, err := bs.repository.GetAll(ctx, badge.Achievement)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	awards := make([]badge.Award, 0)
+	counts := make(map[badge.Metric]int64)
+	for i := range achievements {
+		b := achievements[i]
+		if b.Rule == nil {
+			continue
+		}
+
+		n, ok := counts[b.Rule.Metric]
+		if !ok {
+			n, err = bs.counter.Count(ctx, objectUserID, b.Rule.Metric)
+			if errors.Is(err, badge.ErrInvalidRule) {
+				continue
+			}
+			if err != nil {
+				bs.log.WithContext(ctx).Error(err)
+				return nil, err
+			}
+			counts[b.Rule.Metric] = n
+		}
+
+		if n < b.Rule.Threshold {
+			continue
+		}
+
+		a := badge.Award{
+			ID:        primitive.NewObjectID(),
+			UserID:    objectUserID,
+			BadgeID:   b.ID,
+			AwardedAt: time.Now(),
+		}
+		awarded, err := bs.repository.Award(ctx, &a)
+		if err != nil {
+			bs.log.WithContext(ctx).Error(err)
+			return nil, err
+		}
+		if awarded {
+			a.Badge = &b
+			awards = append(awards, a)
+		}
+	}
+
+	return awards, nil
+}
+
+// GetAwards returns the achievements of a user, evaluated at the time of
+// the request.
+func (bs *BadgeService) GetAwards(ctx context.Context, userID string) ([]badge.Award, error) {
+	ctx, span := tracing.Start(ctx, "BadgeService.GetAwards")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return nil, response.ErrInvalidID
+	}
+
+	if _, err := bs.Evaluate(ctx, userID); err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	awards, err := bs.repository.GetAwards(ctx, objectUserID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return awards, nil
+}
+
+// auditAction record an administrative action on a badge of the catalog, a
+// failure is only logged as the action is already done.
+func (bs *BadgeService) auditAction(ctx context.Context, action audit.Action, id primitive.ObjectID, before, after *badge.Badge) {
+	e := audit.Entry{
+		Action:     action,
+		TargetType: audit.BadgeTarget,
+		TargetID:   id,
+	}
+	if before != nil {
+		e.Before = audit.Snapshot(*before)
+	}
+	if after != nil {
+		e.After = audit.Snapshot(*after)
+	}
+
+	if err := bs.auditor.Record(ctx, e); err != nil {
+		bs.log.WithContext(ctx).Errorf("cannot audit %s of %s: %v", action, id.Hex(), err)
+	}
+}
+
+// New create and configure badge services.
+func New(coll, awardColl, postColl, tripColl, userColl *mongo.Collection, log logger.Logger, timeout time.Duration, auditor audit.Recorder) badge.Service {
+	return &BadgeService{
+		repository: repository.Mongo(coll, awardColl, log),
+		counter:    repository.MongoCounter(postColl, tripColl, userColl, log),
+		auditor:    auditor,
+		log:        log,
+		timeout:    timeout,
+	}
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/audit"
+	amock "github.com/Zucke/social_prove/pkg/audit/mock"
+	"github.com/Zucke/social_prove/pkg/badge"
+	mock "github.com/Zucke/social_prove/pkg/badge/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+func TestBadgeService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	am := amock.NewMockRecorder(ctrl)
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name      string
+		badge     badge.Badge
+		createErr error
+		err       error
+		times     int
+		timesAud  int
+	}{
+		{
+			name:     "succes",
+			badge:    badge.Badge{Name: "Traveler", Kind: badge.Achievement, Rule: &badge.Rule{Metric: badge.Trips, Threshold: 10}},
+			times:    1,
+			timesAud: 1,
+		},
+		{
+			name:  "failure invalid rule",
+			badge: badge.Badge{Name: "Traveler", Kind: badge.Achievement},
+			err:   badge.ErrInvalidRule,
+		},
+		{
+			name:      "failure name taken",
+			badge:     badge.Badge{Name: "Explorer", Kind: badge.PostBadge},
+			createErr: badge.ErrNameTaken,
+			err:       badge.ErrNameTaken,
+			times:     1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Create(gomock.Any(), &test.badge).
+				Return(test.createErr).
+				Times(test.times)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					assert.Equal(t, audit.CreateBadge, e.Action)
+					assert.Equal(t, test.badge.ID, e.TargetID)
+					assert.Nil(t, e.Before)
+					assert.Equal(t, "Traveler", e.After["name"])
+					return nil
+				}).
+				Times(test.timesAud)
+
+			s := BadgeService{
+				repository: m,
+				auditor:    am,
+				log:        l,
+			}
+
+			err := s.Create(ctx, &test.badge)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestBadgeService_Update(t *testing.T) {
+	ctx := context.Background()
+	l := logger.NewMock()
+	stored := badge.Badge{ID: primitive.NewObjectID(), Name: "Traveler", Kind: badge.PostBadge}
+	changed := badge.Badge{ID: stored.ID, Name: "Explorer", Kind: badge.PostBadge}
+
+	tests := []struct {
+		name        string
+		id          string
+		badge       badge.Badge
+		getErr      error
+		updateErr   error
+		err         error
+		timesGet    int
+		timesUpdate int
+		timesAud    int
+	}{
+		{
+			name:        "succes",
+			id:          stored.ID.Hex(),
+			badge:       changed,
+			timesGet:    1,
+			timesUpdate: 1,
+			timesAud:    1,
+		},
+		{
+			name:     "failure not found",
+			id:       stored.ID.Hex(),
+			badge:    changed,
+			getErr:   response.ErrorNotFound,
+			err:      response.ErrorNotFound,
+			timesGet: 1,
+		},
+		{
+			name:        "failure name taken",
+			id:          stored.ID.Hex(),
+			badge:       changed,
+			updateErr:   badge.ErrNameTaken,
+			err:         badge.ErrNameTaken,
+			timesGet:    1,
+			timesUpdate: 1,
+		},
+		{
+			name:  "failure bad id",
+			id:    "1234",
+			badge: changed,
+			err:   response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+			am := amock.NewMockRecorder(ctrl)
+
+			gomock.InOrder(
+				m.
+					EXPECT().
+					GetByID(gomock.Any(), stored.ID).
+					Return(stored, test.getErr).
+					Times(test.timesGet),
+				m.
+					EXPECT().
+					Update(gomock.Any(), stored.ID, &test.badge).
+					Return(test.updateErr).
+					Times(test.timesUpdate),
+				m.
+					EXPECT().
+					GetByID(gomock.Any(), stored.ID).
+					Return(changed, nil).
+					Times(test.timesAud),
+			)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					assert.Equal(t, audit.UpdateBadge, e.Action)
+					assert.Equal(t, stored.ID, e.TargetID)
+					assert.Equal(t, "Traveler", e.Before["name"])
+					assert.Equal(t, "Explorer", e.After["name"])
+					return nil
+				}).
+				Times(test.timesAud)
+
+			s := BadgeService{
+				repository: m,
+				auditor:    am,
+				log:        l,
+			}
+
+			_, err := s.Update(ctx, test.id, &test.badge)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestBadgeService_Delete(t *testing.T) {
+	ctx := context.Background()
+	l := logger.NewMock()
+	stored := badge.Badge{ID: primitive.NewObjectID(), Name: "Traveler", Kind: badge.PostBadge}
+
+	tests := []struct {
+		name        string
+		getErr      error
+		err         error
+		timesDelete int
+	}{
+		{
+			name:        "succes",
+			timesDelete: 1,
+		},
+		{
+			name:   "failure not found",
+			getErr: response.ErrorNotFound,
+			err:    response.ErrorNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+			am := amock.NewMockRecorder(ctrl)
+
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), stored.ID).
+				Return(stored, test.getErr)
+			m.
+				EXPECT().
+				Delete(gomock.Any(), stored.ID).
+				Return(nil).
+				Times(test.timesDelete)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					// The deleted badge is kept in the entry.
+					assert.Equal(t, audit.DeleteBadge, e.Action)
+					assert.Equal(t, "Traveler", e.Before["name"])
+					assert.Nil(t, e.After)
+					return nil
+				}).
+				Times(test.timesDelete)
+
+			s := BadgeService{
+				repository: m,
+				auditor:    am,
+				log:        l,
+			}
+
+			err := s.Delete(ctx, stored.ID.Hex())
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestBadgeService_Evaluate(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	traveler := badge.Badge{
+		ID:   primitive.NewObjectID(),
+		Name: "Traveler",
+		Kind: badge.Achievement,
+		Rule: &badge.Rule{Metric: badge.Trips, Threshold: 10},
+	}
+	explorer := badge.Badge{
+		ID:   primitive.NewObjectID(),
+		Name: "Explorer",
+		Kind: badge.Achievement,
+		Rule: &badge.Rule{Metric: badge.Trips, Threshold: 50},
+	}
+	popular := badge.Badge{
+		ID:   primitive.NewObjectID(),
+		Name: "Popular",
+		Kind: badge.Achievement,
+		Rule: &badge.Rule{Metric: badge.LikesReceived, Threshold: 100},
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name         string
+		userID       string
+		trips        int64
+		likes        int64
+		alreadyOwned bool
+		countErr     error
+		err          error
+		awarded      []primitive.ObjectID
+		timesCount   int
+	}{
+		{
+			name:       "succes award reached rules",
+			userID:     userID.Hex(),
+			trips:      12,
+			likes:      100,
+			awarded:    []primitive.ObjectID{traveler.ID, popular.ID},
+			timesCount: 1,
+		},
+		{
+			name:         "succes already awarded",
+			userID:       userID.Hex(),
+			trips:        12,
+			alreadyOwned: true,
+			awarded:      []primitive.ObjectID{},
+			timesCount:   1,
+		},
+		{
+			name:       "failure counting",
+			userID:     userID.Hex(),
+			countErr:   response.ErrorInternalServerError,
+			err:        response.ErrorInternalServerError,
+			timesCount: 1,
+		},
+		{
+			name:   "failure bad id",
+			userID: "1234",
+			err:    response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+			c := mock.NewMockCounter(ctrl)
+
+			m.
+				EXPECT().
+				GetAll(gomock.Any(), badge.Achievement).
+				Return([]badge.Badge{traveler, explorer, popular}, nil).
+				Times(test.timesCount)
+			c.
+				EXPECT().
+				Count(gomock.Any(), userID, badge.Trips).
+				Return(test.trips, test.countErr).
+				Times(test.timesCount)
+			c.
+				EXPECT().
+				Count(gomock.Any(), userID, badge.LikesReceived).
+				Return(test.likes, nil).
+				AnyTimes()
+			m.
+				EXPECT().
+				Award(gomock.Any(), gomock.Any()).
+				Return(!test.alreadyOwned, nil).
+				AnyTimes()
+
+			s := BadgeService{
+				repository: m,
+				counter:    c,
+				log:        l,
+			}
+
+			awards, err := s.Evaluate(ctx, test.userID)
+			assert.Equal(t, test.err, err)
+			if test.err == nil {
+				ids := make([]primitive.ObjectID, 0)
+				for _, a := range awards {
+					ids = append(ids, a.BadgeID)
+				}
+				assert.Equal(t, test.awarded, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package bookmark
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/post"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// DefaultCollection name of the collection used when none is given.
+const DefaultCollection = "Saved"
+
+// Errors.
+var (
+	ErrInvalidName = response.NewError(response.Invalid, "invalid collection name")
+	ErrNameTaken   = response.NewError(response.Conflict, "collection name already in use")
+)
+
+// Collection is a named private list of posts saved by a user.
+type Collection struct {
+	ID         primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Name       string             `json:"name,omitempty" bson:"name,omitempty"`
+	Items      []Item             `json:"items,omitempty" bson:"items"`
+	ItemsCount int                `json:"items_count" bson:"items_count,omitempty"`
+	CreatedAt  time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt  time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// Item is a post saved in a collection.
+type Item struct {
+	PostID  primitive.ObjectID `json:"post_id,omitempty" bson:"post_id,omitempty"`
+	Post    *post.Post         `json:"post,omitempty" bson:"-"`
+	SavedAt time.Time          `json:"saved_at,omitempty" bson:"saved_at,omitempty"`
+}
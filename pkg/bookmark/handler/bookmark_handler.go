@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/bookmark"
+	"github.com/Zucke/social_prove/pkg/bookmark/service"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+// Handler is the router of the bookmarks.
+type Handler struct {
+	service bookmark.Service
+	log     logger.Logger
+}
+
+// saveRequest selects the collection by ID or by name, the default
+// collection when both are empty.
+type saveRequest struct {
+	CollectionID string `json:"collection_id"`
+	Name         string `json:"name"`
+}
+
+// SaveHandler save a post into a collection of the user.
+func (h *Handler) SaveHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		body saveRequest
+		c    bookmark.Collection
+	)
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	err = validation.DecodeOptional(r.Body, &body)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		c, err = h.service.Save(ctx, lID, id, body.CollectionID, body.Name)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	render.JSON(w, r, render.M{"collection": c})
+}
+
+// UnsaveHandler remove a post from a collection of the user.
+func (h *Handler) UnsaveHandler(w http.ResponseWriter, r *http.Request) {
+	var c bookmark.Collection
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		c, err = h.service.Unsave(ctx, lID, id, r.URL.Query().Get("collection_id"))
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	render.JSON(w, r, render.M{"collection": c})
+}
+
+// GetAllHandler response the collections of the user.
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	var collections []bookmark.Collection
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		collections, err = h.service.GetAll(ctx, lID)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{
+		"collections": collections,
+		"total":       len(collections),
+	})
+}
+
+// GetOneHandler response a collection of the user with its posts.
+func (h *Handler) GetOneHandler(w http.ResponseWriter, r *http.Request) {
+	var c bookmark.Collection
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		c, err = h.service.GetByID(ctx, lID, id)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{"collection": c})
+}
+
+// Routes configure and return the routes of the collections of the user.
+func (h *Handler) Routes(a *auth.Authenticator) http.Handler {
+	r := chi.NewRouter()
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Get("/", h.GetAllHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Get("/{id}", h.GetOneHandler)
+
+	return r
+}
+
+// PostRoutes configure and return the routes to save a post, mounted on
+// the post router.
+func (h *Handler) PostRoutes(a *auth.Authenticator) http.Handler {
+	r := chi.NewRouter()
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Post("/", h.SaveHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Delete("/", h.UnsaveHandler)
+
+	return r
+}
+
+// New create and configure a new Handler.
+func New(coll, postColl *mongo.Collection, log logger.Logger, timeout time.Duration) *Handler {
+	return &Handler{
+		log:     log,
+		service: service.New(coll, postColl, log, timeout),
+	}
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/bookmark"
+	mock "github.com/Zucke/social_prove/pkg/bookmark/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestHandler_Save(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+	cID := primitive.NewObjectID()
+
+	c := bookmark.Collection{
+		ID:   cID,
+		Name: bookmark.DefaultCollection,
+	}
+
+	tests := []struct {
+		name         string
+		body         io.Reader
+		collectionID string
+		cName        string
+		code         int
+		err          error
+		times        int
+	}{
+		{
+			name:  "Success default collection",
+			body:  strings.NewReader(``),
+			code:  http.StatusOK,
+			err:   nil,
+			times: 1,
+		},
+		{
+			name:         "Success by id",
+			body:         strings.NewReader(`{"collection_id":"` + cID.Hex() + `"}`),
+			collectionID: cID.Hex(),
+			code:         http.StatusOK,
+			err:          nil,
+			times:        1,
+		},
+		{
+			name:  "Failure bad request",
+			body:  strings.NewReader(`{"name":`),
+			code:  http.StatusBadRequest,
+			err:   nil,
+			times: 0,
+		},
+		{
+			name:  "Failure invalid name",
+			body:  strings.NewReader(`{"name":"x"}`),
+			cName: "x",
+			code:  http.StatusBadRequest,
+			err:   bookmark.ErrInvalidName,
+			times: 1,
+		},
+		{
+			name:  "Failure not found",
+			body:  strings.NewReader(``),
+			code:  http.StatusNotFound,
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Save(gomock.Any(), id2.Hex(), id1.Hex(), test.collectionID, test.cName).
+				Return(c, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/post/"+id1.Hex()+"/save", test.body)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			mux.Post("/post/{id}/save", h.SaveHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetOne(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+
+	c := bookmark.Collection{
+		ID:   id1,
+		Name: bookmark.DefaultCollection,
+	}
+
+	tests := []struct {
+		name  string
+		code  int
+		err   error
+		times int
+	}{
+		{
+			name:  "Success",
+			code:  http.StatusOK,
+			err:   nil,
+			times: 1,
+		},
+		{
+			name:  "Failure not found",
+			code:  http.StatusNotFound,
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+		{
+			name:  "Failure internal error",
+			code:  http.StatusInternalServerError,
+			err:   response.ErrorInternalServerError,
+			times: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), id2.Hex(), id1.Hex()).
+				Return(c, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/me/collections/"+id1.Hex(), nil)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			mux.Get("/me/collections/{id}", h.GetOneHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
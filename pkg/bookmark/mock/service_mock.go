@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Zucke/social_prove/pkg/bookmark (interfaces: Service)
+
+// Package mock_bookmark is a generated GoMock package.
+package mock_bookmark
+
+import (
+	context "context"
+	bookmark "github.com/Zucke/social_prove/pkg/bookmark"
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockService is a mock of Service interface
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method
+func (m *MockService) GetAll(arg0 context.Context, arg1 string) ([]bookmark.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", arg0, arg1)
+	ret0, _ := ret[0].([]bookmark.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockServiceMockRecorder) GetAll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockService)(nil).GetAll), arg0, arg1)
+}
+
+// GetByID mocks base method
+func (m *MockService) GetByID(arg0 context.Context, arg1, arg2 string) (bookmark.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bookmark.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID
+func (mr *MockServiceMockRecorder) GetByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), arg0, arg1, arg2)
+}
+
+// Save mocks base method
+func (m *MockService) Save(arg0 context.Context, arg1, arg2, arg3, arg4 string) (bookmark.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bookmark.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save
+func (mr *MockServiceMockRecorder) Save(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockService)(nil).Save), arg0, arg1, arg2, arg3, arg4)
+}
+
+// Unsave mocks base method
+func (m *MockService) Unsave(arg0 context.Context, arg1, arg2, arg3 string) (bookmark.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsave", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bookmark.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unsave indicates an expected call of Unsave
+func (mr *MockServiceMockRecorder) Unsave(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsave", reflect.TypeOf((*MockService)(nil).Unsave), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,19 @@
+package bookmark
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Repository handle the storage of the collections.
+type Repository interface {
+	Create(ctx context.Context, c *Collection) error
+	GetByID(ctx context.Context, userID, id primitive.ObjectID) (Collection, error)
+	GetByName(ctx context.Context, userID primitive.ObjectID, name string) (Collection, error)
+	GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]Collection, error)
+	AddItem(ctx context.Context, id primitive.ObjectID, item Item) error
+	RemoveItem(ctx context.Context, id, postID primitive.ObjectID) error
+	RemovePost(ctx context.Context, postID primitive.ObjectID) error
+	DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/bookmark"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// duplicateKeyCode is the mongo error code of a unique index violation.
+const duplicateKeyCode = 11000
+
+// Repository storage to the bookmark model.
+type Repository struct {
+	coll *mongo.Collection
+	log  logger.Logger
+}
+
+// Create store a new collection.
+func (r *Repository) Create(ctx context.Context, c *bookmark.Collection) error {
+	if c.Items == nil {
+		c.Items = []bookmark.Item{}
+	}
+
+	_, err := r.coll.InsertOne(ctx, c)
+	if isDuplicateKey(err) {
+		return bookmark.ErrNameTaken
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrCouldNotInsert
+	}
+
+	return nil
+}
+
+// GetByID returns a collection of the user by ID.
+func (r *Repository) GetByID(ctx context.Context, userID, id primitive.ObjectID) (bookmark.Collection, error) {
+	return r.findOne(ctx, bson.M{"_id": id, "user_id": userID})
+}
+
+// GetByName returns a collection of the user by name.
+func (r *Repository) GetByName(ctx context.Context, userID primitive.ObjectID, name string) (bookmark.Collection, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID, "name": name})
+}
+
+func (r *Repository) findOne(ctx context.Context, filter bson.M) (bookmark.Collection, error) {
+	c := bookmark.Collection{}
+	err := r.coll.FindOne(ctx, filter).Decode(&c)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return bookmark.Collection{}, response.ErrorNotFound
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, response.ErrorInternalServerError
+	}
+
+	c.ItemsCount = len(c.Items)
+
+	return c, nil
+}
+
+// GetAllForUser returns the collections of a user with only the post IDs of
+// their items.
+func (r *Repository) GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]bookmark.Collection, error) {
+	collections := make([]bookmark.Collection, 0)
+
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"user_id": userID}}},
+		bson.D{{Key: "$sort", Value: bson.M{"created_at": 1}}},
+		bson.D{{Key: "$project", Value: bson.M{
+			"user_id":       1,
+			"name":          1,
+			"items.post_id": 1,
+			"created_at":    1,
+			"updated_at":    1,
+		}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		c := bookmark.Collection{}
+		if err := cursor.Decode(&c); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		collections = append(collections, c)
+	}
+
+	return collections, nil
+}
+
+// AddItem append a post to a collection, a post already saved in the
+// collection is kept as is.
+func (r *Repository) AddItem(ctx context.Context, id primitive.ObjectID, item bookmark.Item) error {
+	filter := bson.M{
+		"_id":           id,
+		"items.post_id": bson.M{"$ne": item.PostID},
+	}
+
+	update := bson.M{
+		"$push": bson.M{"items": item},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	_, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// RemoveItem remove a post from a collection.
+func (r *Repository) RemoveItem(ctx context.Context, id, postID primitive.ObjectID) error {
+	update := bson.M{
+		"$pull": bson.M{"items": bson.M{"post_id": postID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if result.MatchedCount == 0 {
+		return response.ErrorNotFound
+	}
+
+	return nil
+}
+
+// RemovePost remove a post from every collection it was saved in.
+func (r *Repository) RemovePost(ctx context.Context, postID primitive.ObjectID) error {
+	filter := bson.M{"items.post_id": postID}
+	update := bson.M{"$pull": bson.M{"items": bson.M{"post_id": postID}}}
+
+	_, err := r.coll.UpdateMany(ctx, filter, update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// DeleteAllForUser remove every collection of a user.
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// isDuplicateKey reports whether err is a unique index violation.
+func isDuplicateKey(err error) bool {
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if e.Code == duplicateKeyCode {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Mongo create a new bookmark repository.
+func Mongo(coll *mongo.Collection, log logger.Logger) bookmark.Repository {
+	return &Repository{
+		coll: coll,
+		log:  log,
+	}
+}
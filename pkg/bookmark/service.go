@@ -0,0 +1,13 @@
+package bookmark
+
+import (
+	"context"
+)
+
+// Service the bookmark service.
+type Service interface {
+	Save(ctx context.Context, userID, postID, collectionID, name string) (Collection, error)
+	Unsave(ctx context.Context, userID, postID, collectionID string) (Collection, error)
+	GetAll(ctx context.Context, userID string) ([]Collection, error)
+	GetByID(ctx context.Context, userID, id string) (Collection, error)
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/bookmark"
+	"github.com/Zucke/social_prove/pkg/bookmark/repository"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/post"
+	postrepository "github.com/Zucke/social_prove/pkg/post/repository"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/tracing"
+)
+
+const (
+	maxNameLength = 50
+)
+
+// BookmarkService the bookmark service.
+type BookmarkService struct {
+	repository bookmark.Repository
+	posts      post.Repository
+	timeout    time.Duration
+	log        logger.Logger
+}
+
+// Save add a post to a collection of the user. Without collectionID the
+// collection is looked up by name and created when it does not exist.
+func (bs *BookmarkService) Save(ctx context.Context, userID, postID, collectionID, name string) (bookmark.Collection, error) {
+	ctx, span := tracing.Start(ctx, "BookmarkService.Save")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, response.ErrInvalidID
+	}
+	objectPostID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, response.ErrInvalidID
+	}
+
+	p, err := bs.posts.GetByID(ctx, objectPostID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, err
+	}
+	if !p.VisibleTo(objectUserID) {
+		return bookmark.Collection{}, response.ErrorNotFound
+	}
+
+	var c bookmark.Collection
+	if collectionID != "" {
+		c, err = bs.getCollection(ctx, objectUserID, collectionID)
+	} else {
+		c, err = bs.getOrCreate(ctx, objectUserID, name)
+	}
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, err
+	}
+
+	item := bookmark.Item{
+		PostID:  objectPostID,
+		SavedAt: time.Now(),
+	}
+	if err := bs.repository.AddItem(ctx, c.ID, item); err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, err
+	}
+
+	return bs.GetByID(ctx, userID, c.ID.Hex())
+}
+
+// Unsave remove a post from a collection of the user, the default
+// collection when collectionID is empty.
+func (bs *BookmarkService) Unsave(ctx context.Context, userID, postID, collectionID string) (bookmark.Collection, error) {
+	ctx, span := tracing.Start(ctx, "BookmarkService.Unsave")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, response.ErrInvalidID
+	}
+	objectPostID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, response.ErrInvalidID
+	}
+
+	var c bookmark.Collection
+	if collectionID != "" {
+		c, err = bs.getCollection(ctx, objectUserID, collectionID)
+	} else {
+		c, err = bs.repository.GetByName(ctx, objectUserID, bookmark.DefaultCollection)
+	}
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, err
+	}
+
+	if err := bs.repository.RemoveItem(ctx, c.ID, objectPostID); err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, err
+	}
+
+	return bs.GetByID(ctx, userID, c.ID.Hex())
+}
+
+// GetAll returns the collections of the user without their items, the
+// posts not available anymore aren't counted.
+func (bs *BookmarkService) GetAll(ctx context.Context, userID string) ([]bookmark.Collection, error) {
+	ctx, span := tracing.Start(ctx, "BookmarkService.GetAll")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return nil, response.ErrInvalidID
+	}
+
+	collections, err := bs.repository.GetAllForUser(ctx, objectUserID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0)
+	for _, c := range collections {
+		for _, item := range c.Items {
+			ids = append(ids, item.PostID)
+		}
+	}
+
+	posts, err := bs.posts.GetByIDs(ctx, ids, objectUserID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	visible := make(map[primitive.ObjectID]bool, len(posts))
+	for _, p := range posts {
+		visible[p.ID] = true
+	}
+
+	for i, c := range collections {
+		count := 0
+		for _, item := range c.Items {
+			if visible[item.PostID] {
+				count++
+			}
+		}
+		collections[i].Items = nil
+		collections[i].ItemsCount = count
+	}
+
+	return collections, nil
+}
+
+// GetByID returns a collection of the user with its saved posts, the
+// posts not available anymore are left out.
+func (bs *BookmarkService) GetByID(ctx context.Context, userID, id string) (bookmark.Collection, error) {
+	ctx, span := tracing.Start(ctx, "BookmarkService.GetByID")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, bs.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, response.ErrInvalidID
+	}
+
+	c, err := bs.getCollection(ctx, objectUserID, id)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, err
+	}
+
+	ids := make([]primitive.ObjectID, len(c.Items))
+	for i, item := range c.Items {
+		ids[i] = item.PostID
+	}
+
+	posts, err := bs.posts.GetByIDs(ctx, ids, objectUserID)
+	if err != nil {
+		bs.log.WithContext(ctx).Error(err)
+		return bookmark.Collection{}, err
+	}
+
+	byID := make(map[primitive.ObjectID]post.Post, len(posts))
+	for _, p := range posts {
+		byID[p.ID] = p
+	}
+
+	items := make([]bookmark.Item, 0, len(c.Items))
+	for _, item := range c.Items {
+		p, ok := byID[item.PostID]
+		if !ok {
+			continue
+		}
+		item.Post = &p
+		items = append(items, item)
+	}
+	c.Items = items
+	c.ItemsCount = len(items)
+
+	return c, nil
+}
+
+func (bs *BookmarkService) getCollection(ctx context.Context, userID primitive.ObjectID, id string) (bookmark.Collection, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return bookmark.Collection{}, response.ErrInvalidID
+	}
+
+	return bs.repository.GetByID(ctx, userID, objectID)
+}
+
+func (bs *BookmarkService) getOrCreate(ctx context.Context, userID primitive.ObjectID, name string) (bookmark.Collection, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = bookmark.DefaultCollection
+	}
+	if len(name) > maxNameLength {
+		return bookmark.Collection{}, bookmark.ErrInvalidName
+	}
+
+	c, err := bs.repository.GetByName(ctx, userID, name)
+	if !errors.Is(err, response.ErrorNotFound) {
+		return c, err
+	}
+
+	now := time.Now()
+	c = bookmark.Collection{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err = bs.repository.Create(ctx, &c)
+	if errors.Is(err, bookmark.ErrNameTaken) {
+		// Created by a concurrent request.
+		return bs.repository.GetByName(ctx, userID, name)
+	}
+	if err != nil {
+		return bookmark.Collection{}, err
+	}
+
+	return c, nil
+}
+
+// New create and configure bookmark services.
+func New(coll, postColl *mongo.Collection, log logger.Logger, timeout time.Duration) bookmark.Service {
+	return &BookmarkService{
+		repository: repository.Mongo(coll, log),
+		posts:      postrepository.Mongo(postColl, log),
+		log:        log,
+		timeout:    timeout,
+	}
+}
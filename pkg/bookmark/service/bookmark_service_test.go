@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/bookmark"
+	mock "github.com/Zucke/social_prove/pkg/bookmark/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/post"
+	pmock "github.com/Zucke/social_prove/pkg/post/mock"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+func TestBookmarkService_Save(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	pm := pmock.NewMockRepository(ctrl)
+	userID := primitive.NewObjectID()
+	p := post.Post{
+		ID:          primitive.NewObjectID(),
+		Description: "contend bla bla bla, bla",
+	}
+	c := bookmark.Collection{
+		ID:     primitive.NewObjectID(),
+		UserID: userID,
+		Name:   "Trips",
+		Items:  []bookmark.Item{{PostID: p.ID}},
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name         string
+		postID       string
+		collectionID string
+		cName        string
+		lookupName   string
+		getNameErr   error
+		err          error
+		timesPost    int
+		timesID      int
+		timesName    int
+		timesCreate  int
+		timesAdd     int
+	}{
+		{
+			name:         "succes by id",
+			postID:       p.ID.Hex(),
+			collectionID: c.ID.Hex(),
+			timesPost:    1,
+			timesID:      2,
+			timesAdd:     1,
+		},
+		{
+			name:       "succes by name",
+			postID:     p.ID.Hex(),
+			cName:      " Trips ",
+			lookupName: "Trips",
+			timesPost:  1,
+			timesID:    1,
+			timesName:  1,
+			timesAdd:   1,
+		},
+		{
+			name:        "succes new default collection",
+			postID:      p.ID.Hex(),
+			lookupName:  bookmark.DefaultCollection,
+			getNameErr:  response.ErrorNotFound,
+			timesPost:   1,
+			timesID:     1,
+			timesName:   1,
+			timesCreate: 1,
+			timesAdd:    1,
+		},
+		{
+			name:      "failure invalid name",
+			postID:    p.ID.Hex(),
+			cName:     strings.Repeat("a", maxNameLength+1),
+			err:       bookmark.ErrInvalidName,
+			timesPost: 1,
+		},
+		{
+			name:   "failure bad id",
+			postID: "1234",
+			err:    response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pm.
+				EXPECT().
+				GetByID(gomock.Any(), p.ID).
+				Return(p, nil).
+				Times(test.timesPost)
+			m.
+				EXPECT().
+				GetByName(gomock.Any(), userID, test.lookupName).
+				Return(c, test.getNameErr).
+				Times(test.timesName)
+			m.
+				EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, nc *bookmark.Collection) error {
+					nc.ID = c.ID
+					return nil
+				}).
+				Times(test.timesCreate)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), userID, c.ID).
+				Return(c, nil).
+				Times(test.timesID)
+			m.
+				EXPECT().
+				AddItem(gomock.Any(), c.ID, gomock.Any()).
+				Return(nil).
+				Times(test.timesAdd)
+			pm.
+				EXPECT().
+				GetByIDs(gomock.Any(), []primitive.ObjectID{p.ID}, userID).
+				Return([]post.Post{p}, nil).
+				Times(test.timesAdd)
+
+			s := BookmarkService{
+				repository: m,
+				posts:      pm,
+				log:        l,
+			}
+
+			result, err := s.Save(ctx, userID.Hex(), test.postID, test.collectionID, test.cName)
+			assert.Equal(t, test.err, err)
+			if test.err == nil {
+				assert.Equal(t, c.ID, result.ID)
+				assert.Equal(t, 1, result.ItemsCount)
+				assert.Equal(t, p.Description, result.Items[0].Post.Description)
+			}
+		})
+	}
+}
+
+func TestBookmarkService_GetByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	pm := pmock.NewMockRepository(ctrl)
+	userID := primitive.NewObjectID()
+	p := post.Post{
+		ID:          primitive.NewObjectID(),
+		Description: "contend bla bla bla, bla",
+	}
+	deletedID := primitive.NewObjectID()
+	c := bookmark.Collection{
+		ID:     primitive.NewObjectID(),
+		UserID: userID,
+		Name:   bookmark.DefaultCollection,
+		Items:  []bookmark.Item{{PostID: deletedID}, {PostID: p.ID}},
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name       string
+		id         string
+		getErr     error
+		err        error
+		count      int
+		timesGet   int
+		timesPosts int
+	}{
+		{
+			name:       "succes skip unavailable posts",
+			id:         c.ID.Hex(),
+			count:      1,
+			timesGet:   1,
+			timesPosts: 1,
+		},
+		{
+			name:     "failure not found",
+			id:       c.ID.Hex(),
+			getErr:   response.ErrorNotFound,
+			err:      response.ErrorNotFound,
+			timesGet: 1,
+		},
+		{
+			name: "failure bad id",
+			id:   "1234",
+			err:  response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), userID, c.ID).
+				Return(c, test.getErr).
+				Times(test.timesGet)
+			pm.
+				EXPECT().
+				GetByIDs(gomock.Any(), []primitive.ObjectID{deletedID, p.ID}, userID).
+				Return([]post.Post{p}, nil).
+				Times(test.timesPosts)
+
+			s := BookmarkService{
+				repository: m,
+				posts:      pm,
+				log:        l,
+			}
+
+			result, err := s.GetByID(ctx, userID.Hex(), test.id)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.count, result.ItemsCount)
+		})
+	}
+}
+
+func TestBookmarkService_GetAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	pm := pmock.NewMockRepository(ctrl)
+	userID := primitive.NewObjectID()
+	p := post.Post{ID: primitive.NewObjectID()}
+	deletedID := primitive.NewObjectID()
+	collections := []bookmark.Collection{
+		{ID: primitive.NewObjectID(), Name: bookmark.DefaultCollection, Items: []bookmark.Item{{PostID: deletedID}, {PostID: p.ID}}},
+		{ID: primitive.NewObjectID(), Name: "Trips", Items: []bookmark.Item{{PostID: deletedID}}},
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name       string
+		userID     string
+		postsErr   error
+		err        error
+		counts     []int
+		timesGet   int
+		timesPosts int
+	}{
+		{
+			name:       "succes count available posts",
+			userID:     userID.Hex(),
+			counts:     []int{1, 0},
+			timesGet:   1,
+			timesPosts: 1,
+		},
+		{
+			name:       "failure posts",
+			userID:     userID.Hex(),
+			postsErr:   response.ErrorInternalServerError,
+			err:        response.ErrorInternalServerError,
+			timesGet:   1,
+			timesPosts: 1,
+		},
+		{
+			name:   "failure bad id",
+			userID: "1234",
+			err:    response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stored := make([]bookmark.Collection, len(collections))
+			copy(stored, collections)
+			m.
+				EXPECT().
+				GetAllForUser(gomock.Any(), userID).
+				Return(stored, nil).
+				Times(test.timesGet)
+			pm.
+				EXPECT().
+				GetByIDs(gomock.Any(), []primitive.ObjectID{deletedID, p.ID, deletedID}, userID).
+				Return([]post.Post{p}, test.postsErr).
+				Times(test.timesPosts)
+
+			s := BookmarkService{
+				repository: m,
+				posts:      pm,
+				log:        l,
+			}
+
+			result, err := s.GetAll(ctx, test.userID)
+			assert.Equal(t, test.err, err)
+			assert.Len(t, result, len(test.counts))
+			for i, c := range result {
+				assert.Equal(t, test.counts[i], c.ItemsCount)
+				assert.Nil(t, c.Items)
+			}
+		})
+	}
+}
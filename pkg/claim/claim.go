@@ -1,21 +1,22 @@
 package claim
 
 import (
-	"errors"
 	"net/http"
 	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
+
+	"github.com/Zucke/social_prove/pkg/response"
 )
 
 // Errors
 var (
-	ErrInsufficientPrivileges    = errors.New("Insufficient privileges")
-	ErrInvalidToken              = errors.New("invalid token")
-	ErrInvalidClaim              = errors.New("invalid claim")
-	ErrUserNotAuthorized         = errors.New("not authorized")
-	ErrInvalidAutorizationFormat = errors.New("invalid autorization format")
+	ErrInsufficientPrivileges    = response.NewError(response.Forbidden, "Insufficient privileges")
+	ErrInvalidToken              = response.NewError(response.Unauthenticated, "invalid token")
+	ErrInvalidClaim              = response.NewError(response.Unauthenticated, "invalid claim")
+	ErrUserNotAuthorized         = response.NewError(response.Unauthenticated, "not authorized")
+	ErrInvalidAutorizationFormat = response.NewError(response.Unauthenticated, "invalid autorization format")
 )
 
 // Claim what goes in token claims.
@@ -60,7 +61,8 @@ func TokenFromAuthorization(r *http.Request) (string, error) {
 
 // GetFromToken get claims from a token string.
 func GetFromToken(tokenString, signingString string) (*Claim, error) {
-	token, err := jwt.Parse(tokenString, func(*jwt.Token) (interface{}, error) {
+	c := &Claim{}
+	token, err := jwt.ParseWithClaims(tokenString, c, func(*jwt.Token) (interface{}, error) {
 		return []byte(signingString), nil
 	})
 	if err != nil {
@@ -71,20 +73,9 @@ func GetFromToken(tokenString, signingString string) (*Claim, error) {
 		return nil, ErrInvalidToken
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
+	if c.ID == "" {
 		return nil, ErrInvalidClaim
 	}
 
-	role, ok := claims["role"].(uint)
-	if !ok {
-		return nil, ErrInsufficientPrivileges
-	}
-
-	id, ok := claims["id"].(string)
-	if !ok {
-		return nil, ErrInsufficientPrivileges
-	}
-
-	return &Claim{ID: id, Role: role}, nil
+	return c, nil
 }
@@ -0,0 +1,43 @@
+package comment
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+// MaxBodyLength is the limit of the body of a comment.
+const MaxBodyLength = 1000
+
+// Comment is a comment of a user on a post, with the counts of the
+// reactions to it.
+type Comment struct {
+	ID         primitive.ObjectID      `json:"id,omitempty" bson:"_id,omitempty"`
+	PostID     primitive.ObjectID      `json:"post_id,omitempty" bson:"post_id,omitempty"`
+	UserID     primitive.ObjectID      `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Body       string                  `json:"body,omitempty" bson:"body,omitempty"`
+	Reactions  map[reaction.Type]int64 `json:"reactions,omitempty" bson:"reactions,omitempty"`
+	MyReaction reaction.Type           `json:"my_reaction,omitempty" bson:"-"`
+	CreatedAt  time.Time               `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt  time.Time               `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+}
+
+// Request is the body of a new comment, the rest of the fields are set by
+// the server.
+type Request struct {
+	Body string `json:"body"`
+}
+
+// Validate check the body isn't blank nor too long.
+func (r Request) Validate() error {
+	var v validation.Validator
+	if v.Required("body", r.Body) {
+		v.Length("body", strings.TrimSpace(r.Body), 0, MaxBodyLength)
+	}
+
+	return v.Err()
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/comment"
+	"github.com/Zucke/social_prove/pkg/comment/service"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/pagination"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+const defaultCommentsLimit = 20
+
+// Handler is the router of the comments of the posts.
+type Handler struct {
+	service comment.Service
+	log     logger.Logger
+}
+
+// CreateHandler add a comment of the user to a post.
+func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		req comment.Request
+		c   comment.Comment
+	)
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	err = validation.Decode(r.Body, &req)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		c, err = h.service.Create(ctx, lID, id, req)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusCreated, render.M{"comment": c})
+}
+
+// GetAllHandler response a page of the comments of a post.
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		comments []comment.Comment
+		total    int
+	)
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	page, limit, ok := pagination.GetPagination(r)
+	if !ok {
+		page, limit = 1, defaultCommentsLimit
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		comments, total, err = h.service.GetAll(ctx, lID, id, page, limit)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{
+		"comments": comments,
+		"total":    total,
+	})
+}
+
+// DeleteHandler remove a comment of a post.
+func (h *Handler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+	role, err := auth.GetRole(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		err = h.service.Delete(ctx, lID, role, id, commentID)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	render.JSON(w, r, render.M{})
+}
+
+// ReactHandler set the reaction of the user to a comment.
+func (h *Handler) ReactHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		body struct {
+			Type reaction.Type `json:"type"`
+		}
+		c comment.Comment
+	)
+	id := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	err = validation.Decode(r.Body, &body)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		c, err = h.service.React(ctx, lID, id, commentID, body.Type)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	render.JSON(w, r, render.M{"comment": c})
+}
+
+// UnreactHandler remove the reaction of the user to a comment.
+func (h *Handler) UnreactHandler(w http.ResponseWriter, r *http.Request) {
+	var c comment.Comment
+	id := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "commentID")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		c, err = h.service.Unreact(ctx, lID, id, commentID)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	render.JSON(w, r, render.M{"comment": c})
+}
+
+// Routes configure and return the routes of the comments, mounted on the
+// post router.
+func (h *Handler) Routes(a *auth.Authenticator) http.Handler {
+	r := chi.NewRouter()
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Get("/", h.GetAllHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Post("/", h.CreateHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Delete("/{commentID}", h.DeleteHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Put("/{commentID}/reaction", h.ReactHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Delete("/{commentID}/reaction", h.UnreactHandler)
+
+	return r
+}
+
+// New create and configure a new Handler.
+func New(coll, postColl, reactionColl *mongo.Collection, log logger.Logger, timeout time.Duration) *Handler {
+	return &Handler{
+		log:     log,
+		service: service.New(coll, postColl, reactionColl, log, timeout),
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/comment"
+	mock "github.com/Zucke/social_prove/pkg/comment/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/validation"
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestHandler_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+
+	tests := []struct {
+		name  string
+		body  io.Reader
+		code  int
+		err   error
+		times int
+	}{
+		{
+			name:  "Success",
+			body:  strings.NewReader(`{"body":"nice picture"}`),
+			code:  http.StatusCreated,
+			err:   nil,
+			times: 1,
+		},
+		{
+			name:  "Failure bad request",
+			body:  strings.NewReader(`{"body":`),
+			code:  http.StatusBadRequest,
+			err:   nil,
+			times: 0,
+		},
+		{
+			name:  "Failure invalid body",
+			body:  strings.NewReader(`{"body":"nice picture"}`),
+			code:  http.StatusBadRequest,
+			err:   validation.Errors{{Field: "body", Code: validation.TooLong, Message: "must have at most 1000 characters"}},
+			times: 1,
+		},
+		{
+			name:  "Failure not found",
+			body:  strings.NewReader(`{"body":"nice picture"}`),
+			code:  http.StatusNotFound,
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Create(gomock.Any(), id2.Hex(), id1.Hex(), comment.Request{Body: "nice picture"}).
+				Return(comment.Comment{ID: primitive.NewObjectID()}, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/post/"+id1.Hex()+"/comments", test.body)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			mux.Post("/post/{id}/comments", h.CreateHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
+func TestHandler_React(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+	cID := primitive.NewObjectID()
+
+	tests := []struct {
+		name  string
+		body  io.Reader
+		code  int
+		err   error
+		times int
+	}{
+		{
+			name:  "Success",
+			body:  strings.NewReader(`{"type":"love"}`),
+			code:  http.StatusOK,
+			err:   nil,
+			times: 1,
+		},
+		{
+			name:  "Failure invalid type",
+			body:  strings.NewReader(`{"type":"love"}`),
+			code:  http.StatusBadRequest,
+			err:   reaction.ErrInvalidType,
+			times: 1,
+		},
+		{
+			name:  "Failure not found",
+			body:  strings.NewReader(`{"type":"love"}`),
+			code:  http.StatusNotFound,
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				React(gomock.Any(), id2.Hex(), id1.Hex(), cID.Hex(), reaction.Love).
+				Return(comment.Comment{ID: cID, MyReaction: reaction.Love}, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPut, "/post/"+id1.Hex()+"/comments/"+cID.Hex()+"/reaction", test.body)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			mux.Put("/post/{id}/comments/{commentID}/reaction", h.ReactHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Zucke/social_prove/pkg/comment (interfaces: Repository)
+
+// Package mock_comment is a generated GoMock package.
+package mock_comment
+
+import (
+	context "context"
+	comment "github.com/Zucke/social_prove/pkg/comment"
+	reaction "github.com/Zucke/social_prove/pkg/reaction"
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	reflect "reflect"
+)
+
+// MockRepository is a mock of Repository interface
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockRepository) Create(arg0 context.Context, arg1 *comment.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method
+func (m *MockRepository) Delete(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0, arg1)
+}
+
+// DeleteAllForPost mocks base method
+func (m *MockRepository) DeleteAllForPost(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllForPost", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAllForPost indicates an expected call of DeleteAllForPost
+func (mr *MockRepositoryMockRecorder) DeleteAllForPost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllForPost", reflect.TypeOf((*MockRepository)(nil).DeleteAllForPost), arg0, arg1)
+}
+
+// DeleteAllForUser mocks base method
+func (m *MockRepository) DeleteAllForUser(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllForUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAllForUser indicates an expected call of DeleteAllForUser
+func (mr *MockRepositoryMockRecorder) DeleteAllForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllForUser", reflect.TypeOf((*MockRepository)(nil).DeleteAllForUser), arg0, arg1)
+}
+
+// GetAllForPost mocks base method
+func (m *MockRepository) GetAllForPost(arg0 context.Context, arg1 primitive.ObjectID, arg2, arg3 int64) ([]comment.Comment, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllForPost", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]comment.Comment)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAllForPost indicates an expected call of GetAllForPost
+func (mr *MockRepositoryMockRecorder) GetAllForPost(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllForPost", reflect.TypeOf((*MockRepository)(nil).GetAllForPost), arg0, arg1, arg2, arg3)
+}
+
+// GetAllForUser mocks base method
+func (m *MockRepository) GetAllForUser(arg0 context.Context, arg1 primitive.ObjectID) ([]comment.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllForUser", arg0, arg1)
+	ret0, _ := ret[0].([]comment.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllForUser indicates an expected call of GetAllForUser
+func (mr *MockRepositoryMockRecorder) GetAllForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllForUser", reflect.TypeOf((*MockRepository)(nil).GetAllForUser), arg0, arg1)
+}
+
+// GetByID mocks base method
+func (m *MockRepository) GetByID(arg0 context.Context, arg1 primitive.ObjectID) (comment.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
+	ret0, _ := ret[0].(comment.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID
+func (mr *MockRepositoryMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepository)(nil).GetByID), arg0, arg1)
+}
+
+// GetIDsForPost mocks base method
+func (m *MockRepository) GetIDsForPost(arg0 context.Context, arg1 primitive.ObjectID) ([]primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIDsForPost", arg0, arg1)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIDsForPost indicates an expected call of GetIDsForPost
+func (mr *MockRepositoryMockRecorder) GetIDsForPost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIDsForPost", reflect.TypeOf((*MockRepository)(nil).GetIDsForPost), arg0, arg1)
+}
+
+// IncReactions mocks base method
+func (m *MockRepository) IncReactions(arg0 context.Context, arg1 primitive.ObjectID, arg2 map[reaction.Type]int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncReactions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncReactions indicates an expected call of IncReactions
+func (mr *MockRepositoryMockRecorder) IncReactions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncReactions", reflect.TypeOf((*MockRepository)(nil).IncReactions), arg0, arg1, arg2)
+}
@@ -0,0 +1,22 @@
+package comment
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/reaction"
+)
+
+// Repository handle the storage of the comments.
+type Repository interface {
+	Create(ctx context.Context, c *Comment) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (Comment, error)
+	GetAllForPost(ctx context.Context, postID primitive.ObjectID, skip, limit int64) ([]Comment, int64, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	IncReactions(ctx context.Context, id primitive.ObjectID, counts map[reaction.Type]int) error
+	GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]Comment, error)
+	GetIDsForPost(ctx context.Context, postID primitive.ObjectID) ([]primitive.ObjectID, error)
+	DeleteAllForPost(ctx context.Context, postID primitive.ObjectID) error
+	DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/comment"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+var pipeLineColl = "users"
+
+// Repository storage to the comment model.
+type Repository struct {
+	coll *mongo.Collection
+	log  logger.Logger
+}
+
+// Create store a new comment.
+func (r *Repository) Create(ctx context.Context, c *comment.Comment) error {
+	_, err := r.coll.InsertOne(ctx, c)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrCouldNotInsert
+	}
+
+	return nil
+}
+
+// GetByID returns a comment by ID.
+func (r *Repository) GetByID(ctx context.Context, id primitive.ObjectID) (comment.Comment, error) {
+	c := comment.Comment{}
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&c)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return comment.Comment{}, response.ErrorNotFound
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, response.ErrorInternalServerError
+	}
+
+	return c, nil
+}
+
+// GetAllForPost returns a page of the comments of a post, oldest first.
+// The comments of the deleted users are left out.
+func (r *Repository) GetAllForPost(ctx context.Context, postID primitive.ObjectID, skip, limit int64) ([]comment.Comment, int64, error) {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"post_id": postID}}},
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         pipeLineColl,
+			"localField":   "user_id",
+			"foreignField": "_id",
+			"as":           "user",
+		}}},
+		bson.D{{Key: "$unwind", Value: "$user"}},
+		bson.D{{Key: "$match", Value: bson.M{"user.deleted_at": bson.M{"$exists": false}}}},
+		bson.D{{Key: "$project", Value: bson.M{"user": 0}}},
+		bson.D{{Key: "$sort", Value: bson.M{"created_at": 1}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"total": bson.A{
+				bson.M{"$count": "count"},
+			},
+			"comments": bson.A{
+				bson.M{"$skip": skip},
+				bson.M{"$limit": limit},
+			},
+		}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, 0, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	result := struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Comments []comment.Comment `bson:"comments"`
+	}{}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			return nil, 0, response.ErrorInternalServerError
+		}
+	}
+
+	comments := result.Comments
+	if comments == nil {
+		comments = make([]comment.Comment, 0)
+	}
+
+	var total int64
+	if len(result.Total) > 0 {
+		total = result.Total[0].Count
+	}
+
+	return comments, total, nil
+}
+
+// Delete remove a comment.
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if result.DeletedCount == 0 {
+		return response.ErrorNotFound
+	}
+
+	return nil
+}
+
+// IncReactions add the counts to the reaction counters of a comment.
+func (r *Repository) IncReactions(ctx context.Context, id primitive.ObjectID, counts map[reaction.Type]int) error {
+	inc := bson.M{}
+	for t, n := range counts {
+		inc["reactions."+string(t)] = n
+	}
+
+	result := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$inc": inc})
+	if err := result.Err(); err != nil {
+		r.log.WithContext(ctx).Error(err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return response.ErrorNotFound
+		}
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// GetAllForUser returns every comment of a user.
+func (r *Repository) GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]comment.Comment, error) {
+	comments := make([]comment.Comment, 0)
+
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		c := comment.Comment{}
+		if err := cursor.Decode(&c); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		comments = append(comments, c)
+	}
+
+	return comments, nil
+}
+
+// GetIDsForPost returns the IDs of the comments of a post.
+func (r *Repository) GetIDsForPost(ctx context.Context, postID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	values, err := r.coll.Distinct(ctx, "_id", bson.M{"post_id": postID})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// DeleteAllForPost remove every comment of a post.
+func (r *Repository) DeleteAllForPost(ctx context.Context, postID primitive.ObjectID) error {
+	return r.deleteAll(ctx, bson.M{"post_id": postID})
+}
+
+// DeleteAllForUser remove every comment of a user.
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	return r.deleteAll(ctx, bson.M{"user_id": userID})
+}
+
+func (r *Repository) deleteAll(ctx context.Context, filter bson.M) error {
+	_, err := r.coll.DeleteMany(ctx, filter)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// Mongo create a new comment repository.
+func Mongo(coll *mongo.Collection, log logger.Logger) comment.Repository {
+	return &Repository{
+		coll: coll,
+		log:  log,
+	}
+}
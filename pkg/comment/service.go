@@ -0,0 +1,17 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+// Service the comment service.
+type Service interface {
+	Create(ctx context.Context, userID, postID string, req Request) (Comment, error)
+	GetAll(ctx context.Context, viewerID, postID string, page, limit int) ([]Comment, int, error)
+	Delete(ctx context.Context, userID string, role user.Role, postID, id string) error
+	React(ctx context.Context, userID, postID, id string, t reaction.Type) (Comment, error)
+	Unreact(ctx context.Context, userID, postID, id string) (Comment, error)
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/comment"
+	"github.com/Zucke/social_prove/pkg/comment/repository"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/post"
+	postrepository "github.com/Zucke/social_prove/pkg/post/repository"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	reactionrepository "github.com/Zucke/social_prove/pkg/reaction/repository"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/tracing"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+// CommentService the comment service.
+type CommentService struct {
+	repository comment.Repository
+	posts      post.Repository
+	reactions  reaction.Repository
+	timeout    time.Duration
+	log        logger.Logger
+}
+
+// Create add a comment of the user to a post it can see.
+func (cs *CommentService) Create(ctx context.Context, userID, postID string, req comment.Request) (comment.Comment, error) {
+	ctx, span := tracing.Start(ctx, "CommentService.Create")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, cs.timeout)
+	defer cancel()
+
+	if err := req.Validate(); err != nil {
+		return comment.Comment{}, err
+	}
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, response.ErrInvalidID
+	}
+
+	p, err := cs.getPost(ctx, objectUserID, postID)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, err
+	}
+
+	now := time.Now()
+	c := comment.Comment{
+		ID:        primitive.NewObjectID(),
+		PostID:    p.ID,
+		UserID:    objectUserID,
+		Body:      strings.TrimSpace(req.Body),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := cs.repository.Create(ctx, &c); err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, err
+	}
+
+	return c, nil
+}
+
+// GetAll returns a page of the comments of a post the viewer can see,
+// with the reactions of the viewer.
+func (cs *CommentService) GetAll(ctx context.Context, viewerID, postID string, page, limit int) ([]comment.Comment, int, error) {
+	ctx, span := tracing.Start(ctx, "CommentService.GetAll")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, cs.timeout)
+	defer cancel()
+
+	objectViewerID, err := primitive.ObjectIDFromHex(viewerID)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return nil, 0, response.ErrInvalidID
+	}
+
+	p, err := cs.getPost(ctx, objectViewerID, postID)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return nil, 0, err
+	}
+
+	if limit < 1 {
+		limit = 1
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	comments, total, err := cs.repository.GetAllForPost(ctx, p.ID, int64((page-1)*limit), int64(limit))
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return nil, 0, err
+	}
+
+	if err := cs.markReactions(ctx, objectViewerID, comments); err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return nil, 0, err
+	}
+
+	return comments, int(total), nil
+}
+
+// Delete remove a comment and the reactions to it, only its author and the
+// admins can delete it.
+func (cs *CommentService) Delete(ctx context.Context, userID string, role user.Role, postID, id string) error {
+	ctx, span := tracing.Start(ctx, "CommentService.Delete")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, cs.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return response.ErrInvalidID
+	}
+
+	c, err := cs.getComment(ctx, postID, id)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	if role == user.Client && c.UserID != objectUserID {
+		return response.ErrorUnauthorized
+	}
+
+	// The reactions go first, so a failed delete can be retried.
+	if err := cs.reactions.DeleteAllForTarget(ctx, reaction.Comment, c.ID); err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	if err := cs.repository.Delete(ctx, c.ID); err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// React set the reaction of a user to a comment, replacing the previous
+// one.
+func (cs *CommentService) React(ctx context.Context, userID, postID, id string, t reaction.Type) (comment.Comment, error) {
+	ctx, span := tracing.Start(ctx, "CommentService.React")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, cs.timeout)
+	defer cancel()
+
+	if !t.Valid() {
+		return comment.Comment{}, reaction.ErrInvalidType
+	}
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, response.ErrInvalidID
+	}
+
+	if _, err := cs.getPost(ctx, objectUserID, postID); err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, err
+	}
+
+	c, err := cs.getComment(ctx, postID, id)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, err
+	}
+
+	now := time.Now()
+	re := reaction.Reaction{
+		ID:        primitive.NewObjectID(),
+		Target:    reaction.Comment,
+		TargetID:  c.ID,
+		UserID:    objectUserID,
+		Type:      t,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	previous, err := cs.reactions.Set(ctx, &re)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, err
+	}
+
+	if previous != t {
+		counts := map[reaction.Type]int{t: 1}
+		if previous != "" {
+			counts[previous] = -1
+		}
+
+		if err := cs.repository.IncReactions(ctx, c.ID, counts); err != nil {
+			cs.log.WithContext(ctx).Error(err)
+			return comment.Comment{}, err
+		}
+	}
+
+	return cs.get(ctx, objectUserID, c.ID)
+}
+
+// Unreact remove the reaction of a user to a comment.
+func (cs *CommentService) Unreact(ctx context.Context, userID, postID, id string) (comment.Comment, error) {
+	ctx, span := tracing.Start(ctx, "CommentService.Unreact")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, cs.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, response.ErrInvalidID
+	}
+
+	c, err := cs.getComment(ctx, postID, id)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, err
+	}
+
+	previous, err := cs.reactions.Delete(ctx, reaction.Comment, c.ID, objectUserID)
+	switch {
+	case errors.Is(err, reaction.ErrNoReaction):
+	case err != nil:
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, err
+	default:
+		counts := map[reaction.Type]int{previous: -1}
+		if err := cs.repository.IncReactions(ctx, c.ID, counts); err != nil {
+			cs.log.WithContext(ctx).Error(err)
+			return comment.Comment{}, err
+		}
+	}
+
+	return cs.get(ctx, objectUserID, c.ID)
+}
+
+// get returns a comment with the reaction of the viewer.
+func (cs *CommentService) get(ctx context.Context, viewerID, id primitive.ObjectID) (comment.Comment, error) {
+	c, err := cs.repository.GetByID(ctx, id)
+	if err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, err
+	}
+
+	comments := []comment.Comment{c}
+	if err := cs.markReactions(ctx, viewerID, comments); err != nil {
+		cs.log.WithContext(ctx).Error(err)
+		return comment.Comment{}, err
+	}
+
+	return comments[0], nil
+}
+
+// getPost returns a post the user can see.
+func (cs *CommentService) getPost(ctx context.Context, userID primitive.ObjectID, postID string) (post.Post, error) {
+	objectPostID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		return post.Post{}, response.ErrInvalidID
+	}
+
+	p, err := cs.posts.GetByID(ctx, objectPostID)
+	if err != nil {
+		return post.Post{}, err
+	}
+	if !p.VisibleTo(userID) {
+		return post.Post{}, response.ErrorNotFound
+	}
+
+	return p, nil
+}
+
+// getComment returns a comment of a post.
+func (cs *CommentService) getComment(ctx context.Context, postID, id string) (comment.Comment, error) {
+	objectPostID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		return comment.Comment{}, response.ErrInvalidID
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return comment.Comment{}, response.ErrInvalidID
+	}
+
+	c, err := cs.repository.GetByID(ctx, objectID)
+	if err != nil {
+		return comment.Comment{}, err
+	}
+	if c.PostID != objectPostID {
+		return comment.Comment{}, response.ErrorNotFound
+	}
+
+	return c, nil
+}
+
+// markReactions set MyReaction on the comments the viewer reacted to.
+func (cs *CommentService) markReactions(ctx context.Context, viewerID primitive.ObjectID, comments []comment.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, len(comments))
+	for i, c := range comments {
+		ids[i] = c.ID
+	}
+
+	reactions, err := cs.reactions.GetUserReactions(ctx, reaction.Comment, viewerID, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		comments[i].MyReaction = reactions[comments[i].ID]
+	}
+
+	return nil
+}
+
+// New create and configure comment services.
+func New(coll, postColl, reactionColl *mongo.Collection, log logger.Logger, timeout time.Duration) comment.Service {
+	return &CommentService{
+		repository: repository.Mongo(coll, log),
+		posts:      postrepository.Mongo(postColl, log),
+		reactions:  reactionrepository.Mongo(reactionColl, log),
+		log:        log,
+		timeout:    timeout,
+	}
+}
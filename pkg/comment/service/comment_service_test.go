@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/comment"
+	mock "github.com/Zucke/social_prove/pkg/comment/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/post"
+	pmock "github.com/Zucke/social_prove/pkg/post/mock"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	rmock "github.com/Zucke/social_prove/pkg/reaction/mock"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+func TestCommentService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockRepository(ctrl)
+	pm := pmock.NewMockRepository(ctrl)
+	userID := primitive.NewObjectID()
+	published := post.Post{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Status: post.Published}
+	draft := post.Post{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Status: post.Draft}
+
+	tests := []struct {
+		name        string
+		post        post.Post
+		body        string
+		getErr      error
+		err         error
+		timesPost   int
+		timesCreate int
+	}{
+		{
+			name:        "succes",
+			post:        published,
+			body:        " nice picture ",
+			timesPost:   1,
+			timesCreate: 1,
+		},
+		{
+			name: "failure empty body",
+			post: published,
+			body: "   ",
+			err: validation.Errors{
+				{Field: "body", Code: validation.Required, Message: "is required"},
+			},
+		},
+		{
+			name:      "failure post not found",
+			post:      published,
+			body:      "nice picture",
+			getErr:    response.ErrorNotFound,
+			err:       response.ErrorNotFound,
+			timesPost: 1,
+		},
+		{
+			name:      "failure post not visible",
+			post:      draft,
+			body:      "nice picture",
+			err:       response.ErrorNotFound,
+			timesPost: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pm.
+				EXPECT().
+				GetByID(gomock.Any(), test.post.ID).
+				Return(test.post, test.getErr).
+				Times(test.timesPost)
+			m.
+				EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				Return(nil).
+				Times(test.timesCreate)
+
+			s := CommentService{
+				repository: m,
+				posts:      pm,
+				log:        logger.NewMock(),
+			}
+
+			c, err := s.Create(context.Background(), userID.Hex(), test.post.ID.Hex(), comment.Request{Body: test.body})
+			assert.Equal(t, test.err, err)
+			if err == nil {
+				assert.Equal(t, "nice picture", c.Body)
+				assert.Equal(t, userID, c.UserID)
+				assert.Equal(t, test.post.ID, c.PostID)
+			}
+		})
+	}
+}
+
+func TestCommentService_React(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockRepository(ctrl)
+	pm := pmock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	userID := primitive.NewObjectID()
+	p := post.Post{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+	c := comment.Comment{ID: primitive.NewObjectID(), PostID: p.ID, UserID: p.UserID}
+
+	tests := []struct {
+		name     string
+		t        reaction.Type
+		previous reaction.Type
+		counts   map[reaction.Type]int
+		err      error
+		times    int
+		timesInc int
+	}{
+		{
+			name:     "succes new reaction",
+			t:        reaction.Love,
+			counts:   map[reaction.Type]int{reaction.Love: 1},
+			times:    1,
+			timesInc: 1,
+		},
+		{
+			name:     "succes changed reaction",
+			t:        reaction.Love,
+			previous: reaction.Like,
+			counts:   map[reaction.Type]int{reaction.Love: 1, reaction.Like: -1},
+			times:    1,
+			timesInc: 1,
+		},
+		{
+			name:     "succes same reaction",
+			t:        reaction.Love,
+			previous: reaction.Love,
+			times:    1,
+		},
+		{
+			name: "failure invalid type",
+			t:    "meh",
+			err:  reaction.ErrInvalidType,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pm.
+				EXPECT().
+				GetByID(gomock.Any(), p.ID).
+				Return(p, nil).
+				Times(test.times)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), c.ID).
+				Return(c, nil).
+				Times(2 * test.times)
+			rm.
+				EXPECT().
+				Set(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, re *reaction.Reaction) (reaction.Type, error) {
+					assert.Equal(t, reaction.Comment, re.Target)
+					assert.Equal(t, c.ID, re.TargetID)
+					return test.previous, nil
+				}).
+				Times(test.times)
+			m.
+				EXPECT().
+				IncReactions(gomock.Any(), c.ID, test.counts).
+				Return(nil).
+				Times(test.timesInc)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Comment, userID, []primitive.ObjectID{c.ID}).
+				Return(map[primitive.ObjectID]reaction.Type{c.ID: test.t}, nil).
+				Times(test.times)
+
+			s := CommentService{
+				repository: m,
+				posts:      pm,
+				reactions:  rm,
+				log:        logger.NewMock(),
+			}
+
+			got, err := s.React(context.Background(), userID.Hex(), p.ID.Hex(), c.ID.Hex(), test.t)
+			assert.Equal(t, test.err, err)
+			if err == nil {
+				assert.Equal(t, test.t, got.MyReaction)
+			}
+		})
+	}
+}
+
+func TestCommentService_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	authorID := primitive.NewObjectID()
+	postID := primitive.NewObjectID()
+	c := comment.Comment{ID: primitive.NewObjectID(), PostID: postID, UserID: authorID}
+
+	tests := []struct {
+		name   string
+		userID primitive.ObjectID
+		role   user.Role
+		postID primitive.ObjectID
+		err    error
+		times  int
+	}{
+		{
+			name:   "succes author",
+			userID: authorID,
+			role:   user.Client,
+			postID: postID,
+			times:  1,
+		},
+		{
+			name:   "succes admin",
+			userID: primitive.NewObjectID(),
+			role:   user.Admin,
+			postID: postID,
+			times:  1,
+		},
+		{
+			name:   "failure not the author",
+			userID: primitive.NewObjectID(),
+			role:   user.Client,
+			postID: postID,
+			err:    response.ErrorUnauthorized,
+		},
+		{
+			name:   "failure other post",
+			userID: authorID,
+			role:   user.Client,
+			postID: primitive.NewObjectID(),
+			err:    response.ErrorNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), c.ID).
+				Return(c, nil).
+				Times(1)
+			rm.
+				EXPECT().
+				DeleteAllForTarget(gomock.Any(), reaction.Comment, c.ID).
+				Return(nil).
+				Times(test.times)
+			m.
+				EXPECT().
+				Delete(gomock.Any(), c.ID).
+				Return(nil).
+				Times(test.times)
+
+			s := CommentService{
+				repository: m,
+				reactions:  rm,
+				log:        logger.NewMock(),
+			}
+
+			err := s.Delete(context.Background(), test.userID.Hex(), test.role, test.postID.Hex(), c.ID.Hex())
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
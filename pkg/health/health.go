@@ -0,0 +1,115 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// timeout limits each check of a readiness probe.
+const timeout = 2 * time.Second
+
+// Status of the API or of one of its dependencies.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check reports whether a dependency works, nil is up.
+type Check func(ctx context.Context) error
+
+// Result is the status of a dependency and how long its check took.
+type Result struct {
+	Status  string  `json:"status"`
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Report is the body of the probes.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker answers the liveness and readiness probes of the orchestrators.
+type Checker struct {
+	checks  []namedCheck
+	timeout time.Duration
+}
+
+// New returns a Checker without dependencies.
+func New() *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add a dependency the API isn't ready without.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Liveness answers while the process is up, it doesn't check the
+// dependencies so a failing database doesn't restart the API.
+func (c *Checker) Liveness(w http.ResponseWriter, r *http.Request) {
+	_ = response.JSON(w, http.StatusOK, Report{Status: StatusUp})
+}
+
+// Readiness runs the checks at once and answers 503 Service Unavailable
+// when any dependency is down.
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := c.Run(r.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusUp {
+		status = http.StatusServiceUnavailable
+	}
+
+	_ = response.JSON(w, status, report)
+}
+
+// Run the checks of the dependencies.
+func (c *Checker) Run(ctx context.Context) Report {
+	results := make([]Result, len(c.checks))
+
+	var wg sync.WaitGroup
+	for i, nc := range c.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}(i, nc.check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(c.checks))}
+	for i, nc := range c.checks {
+		report.Checks[nc.name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	latency := float64(time.Since(start).Microseconds()) / 1000
+
+	if err != nil {
+		return Result{Status: StatusDown, Latency: latency, Error: err.Error()}
+	}
+
+	return Result{Status: StatusUp, Latency: latency}
+}
@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Readiness(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name   string
+		checks map[string]Check
+		status int
+		want   map[string]string
+	}{
+		{
+			name:   "without dependencies",
+			status: http.StatusOK,
+			want:   map[string]string{},
+		},
+		{
+			name:   "all up",
+			checks: map[string]Check{"mongo": up, "auth": up},
+			status: http.StatusOK,
+			want:   map[string]string{"mongo": StatusUp, "auth": StatusUp},
+		},
+		{
+			name:   "one down",
+			checks: map[string]Check{"mongo": down, "auth": up},
+			status: http.StatusServiceUnavailable,
+			want:   map[string]string{"mongo": StatusDown, "auth": StatusUp},
+		},
+		{
+			name:   "timeout",
+			checks: map[string]Check{"mongo": slow},
+			status: http.StatusServiceUnavailable,
+			want:   map[string]string{"mongo": StatusDown},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := New()
+			c.timeout = 10 * time.Millisecond
+			for name, check := range test.checks {
+				c.Add(name, check)
+			}
+
+			w := httptest.NewRecorder()
+			c.Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, test.status, w.Code)
+
+			var report Report
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+
+			got := map[string]string{}
+			for name, result := range report.Checks {
+				got[name] = result.Status
+				assert.Equal(t, result.Status == StatusDown, result.Error != "")
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestChecker_Liveness(t *testing.T) {
+	c := New()
+	c.Add("mongo", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	w := httptest.NewRecorder()
+	c.Liveness(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"up"}`, w.Body.String())
+}
@@ -0,0 +1,22 @@
+package lease
+
+import (
+	"context"
+	"time"
+)
+
+// Lease is an exclusive lock on a named task held by one replica until it
+// expires or is released.
+type Lease struct {
+	Name      string    `json:"name" bson:"_id"`
+	Holder    string    `json:"holder" bson:"holder"`
+	ExpiresAt time.Time `json:"expires_at" bson:"expires_at"`
+}
+
+// Locker acquire and release leases shared by the replicas.
+type Locker interface {
+	// Acquire take or renew the lease for ttl, it returns false when
+	// another holder has it.
+	Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name string) error
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Zucke/social_prove/pkg/lease"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// duplicateKeyCode is the mongo error code of a unique index violation.
+const duplicateKeyCode = 11000
+
+// Repository storage of the leases, one document per lease name.
+type Repository struct {
+	coll   *mongo.Collection
+	holder string
+	log    logger.Logger
+}
+
+// Acquire take the lease when it is free, expired or already held by
+// this replica.
+func (r *Repository) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"holder": r.holder},
+			bson.M{"expires_at": bson.M{"$lte": now}},
+		},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"holder":     r.holder,
+			"expires_at": now.Add(ttl),
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.coll.UpdateOne(ctx, filter, update, opts)
+	if isDuplicateKey(err) {
+		// The lease exists and another replica holds it.
+		return false, nil
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return false, response.ErrorInternalServerError
+	}
+
+	return true, nil
+}
+
+// Release free the lease if this replica holds it.
+func (r *Repository) Release(ctx context.Context, name string) error {
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": name, "holder": r.holder})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// isDuplicateKey reports whether err is a unique index violation.
+func isDuplicateKey(err error) bool {
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if e.Code == duplicateKeyCode {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Mongo create a new lease repository, the holder identifies this process.
+func Mongo(coll *mongo.Collection, log logger.Logger) lease.Locker {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &Repository{
+		coll:   coll,
+		holder: fmt.Sprintf("%s-%d-%s", host, os.Getpid(), primitive.NewObjectID().Hex()),
+		log:    log,
+	}
+}
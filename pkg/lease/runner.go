@@ -0,0 +1,112 @@
+package lease
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+)
+
+// Task is the work of a Runner, ctx expires after the timeout of the runner.
+type Task func(ctx context.Context)
+
+// Runner run a task every interval on the replica holding the lease named
+// name, the others stay on standby. The lease lasts a few intervals so the
+// holder keeps it while it's alive.
+type Runner struct {
+	name     string
+	locker   Locker
+	interval time.Duration
+	timeout  time.Duration
+	task     Task
+	log      logger.Logger
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// Start launch the runner loop.
+func (r *Runner) Start(ctx context.Context) error {
+	r.wg.Add(1)
+	go r.run()
+
+	return nil
+}
+
+// Close stop the runner and release the lease.
+func (r *Runner) Close(ctx context.Context) error {
+	first := false
+	r.once.Do(func() {
+		close(r.stop)
+		first = true
+	})
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if !first {
+		return nil
+	}
+
+	return r.locker.Release(ctx, r.name)
+}
+
+func (r *Runner) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.tick()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.tick()
+		}
+	}
+}
+
+// tick run the task when the lease is acquired.
+func (r *Runner) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	acquired, err := r.locker.Acquire(ctx, r.name, 3*r.interval)
+	if err != nil {
+		r.log.Errorf("cannot acquire the %s lease: %v", r.name, err)
+		return
+	}
+
+	if !acquired {
+		return
+	}
+
+	r.task(ctx)
+}
+
+// NewRunner create a new Runner of task every interval under the lease
+// name, each run is limited to timeout.
+func NewRunner(log logger.Logger, locker Locker, name string, interval, timeout time.Duration, task Task) *Runner {
+	return &Runner{
+		name:     name,
+		locker:   locker,
+		interval: interval,
+		timeout:  timeout,
+		task:     task,
+		log:      log,
+		stop:     make(chan struct{}),
+	}
+}
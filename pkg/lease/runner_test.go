@@ -0,0 +1,82 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+)
+
+// locker is a Locker answering acquired or err, lease/mock can't be used
+// here since it imports this package.
+type locker struct {
+	acquired bool
+	err      error
+	ttl      time.Duration
+	released int
+}
+
+func (l *locker) Acquire(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	l.ttl = ttl
+	return l.acquired, l.err
+}
+
+func (l *locker) Release(ctx context.Context, name string) error {
+	l.released++
+	return nil
+}
+
+func TestRunner_Tick(t *testing.T) {
+	tests := []struct {
+		name     string
+		acquired bool
+		err      error
+		runs     int
+	}{
+		{
+			name:     "lease holder run",
+			acquired: true,
+			runs:     1,
+		},
+		{
+			name:     "standby replica",
+			acquired: false,
+		},
+		{
+			name: "lease unavailable",
+			err:  errors.New("unavailable"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := &locker{acquired: test.acquired, err: test.err}
+			runs := 0
+
+			r := NewRunner(logger.NewMock(), l, "task", time.Second, time.Second, func(ctx context.Context) {
+				_, ok := ctx.Deadline()
+				assert.True(t, ok)
+				runs++
+			})
+			r.tick()
+
+			assert.Equal(t, 3*time.Second, l.ttl)
+			assert.Equal(t, test.runs, runs)
+		})
+	}
+}
+
+func TestRunner_Close(t *testing.T) {
+	l := &locker{}
+	r := NewRunner(logger.NewMock(), l, "task", time.Hour, time.Second, func(ctx context.Context) {})
+	ctx := context.Background()
+
+	assert.NoError(t, r.Start(ctx))
+	assert.NoError(t, r.Close(ctx))
+	assert.NoError(t, r.Close(ctx))
+	assert.Equal(t, 1, l.released)
+}
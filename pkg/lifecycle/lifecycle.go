@@ -0,0 +1,102 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+)
+
+// Component is a part of the API with a lifetime, like the server or a
+// background worker.
+type Component interface {
+	Start(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// Hook is a component of functions, a nil one does nothing.
+type Hook struct {
+	OnStart func(ctx context.Context) error
+	OnClose func(ctx context.Context) error
+}
+
+// Start calls OnStart.
+func (h Hook) Start(ctx context.Context) error {
+	if h.OnStart == nil {
+		return nil
+	}
+
+	return h.OnStart(ctx)
+}
+
+// Close calls OnClose.
+func (h Hook) Close(ctx context.Context) error {
+	if h.OnClose == nil {
+		return nil
+	}
+
+	return h.OnClose(ctx)
+}
+
+type component struct {
+	name string
+	Component
+}
+
+// Group starts its components in the order they were added and closes them
+// in the reverse order, so a component is closed before the ones it uses.
+type Group struct {
+	components []component
+	started    int
+	log        logger.Logger
+}
+
+// Add a component to the group.
+func (g *Group) Add(name string, c Component) {
+	g.components = append(g.components, component{name, c})
+}
+
+// Start the components. When one fails the ones started are closed.
+func (g *Group) Start(ctx context.Context) error {
+	for _, c := range g.components[g.started:] {
+		if err := c.Start(ctx); err != nil {
+			err = fmt.Errorf("cannot start %s: %w", c.name, err)
+			if cerr := g.Close(ctx); cerr != nil {
+				g.log.Error(cerr)
+			}
+			return err
+		}
+
+		g.log.Debugf("%s started", c.name)
+		g.started++
+	}
+
+	return nil
+}
+
+// Close the components started, all of them even if one fails.
+func (g *Group) Close(ctx context.Context) error {
+	var errs []string
+	for ; g.started > 0; g.started-- {
+		c := g.components[g.started-1]
+		if err := c.Close(ctx); err != nil {
+			errs = append(errs, fmt.Sprintf("cannot close %s: %v", c.name, err))
+			continue
+		}
+
+		g.log.Debugf("%s closed", c.name)
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// New create an empty group.
+func New(log logger.Logger) *Group {
+	return &Group{log: log}
+}
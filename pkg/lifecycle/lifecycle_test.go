@@ -0,0 +1,85 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+)
+
+func TestGroup(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name     string
+		startErr map[string]error
+		closeErr map[string]error
+		events   []string
+		start    bool
+		close    bool
+	}{
+		{
+			name:   "Success",
+			events: []string{"start mongo", "start worker", "start server", "close server", "close worker", "close mongo"},
+			start:  true,
+			close:  true,
+		},
+		{
+			name:     "Failure start",
+			startErr: map[string]error{"server": errFailed},
+			events:   []string{"start mongo", "start worker", "start server", "close worker", "close mongo"},
+			close:    true,
+		},
+		{
+			name:     "Failure close",
+			closeErr: map[string]error{"worker": errFailed},
+			events:   []string{"start mongo", "start worker", "start server", "close server", "close worker", "close mongo"},
+			start:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var events []string
+			hook := func(name string) Hook {
+				return Hook{
+					OnStart: func(ctx context.Context) error {
+						events = append(events, "start "+name)
+						return test.startErr[name]
+					},
+					OnClose: func(ctx context.Context) error {
+						events = append(events, "close "+name)
+						return test.closeErr[name]
+					},
+				}
+			}
+
+			g := New(logger.NewMock())
+			g.Add("mongo", hook("mongo"))
+			g.Add("worker", hook("worker"))
+			g.Add("server", hook("server"))
+
+			ctx := context.Background()
+			err := g.Start(ctx)
+			assert.Equal(t, test.start, err == nil)
+			if test.start {
+				err = g.Close(ctx)
+				assert.Equal(t, test.close, err == nil)
+			}
+			assert.Equal(t, test.events, events)
+
+			// Everything is closed once.
+			assert.NoError(t, g.Close(ctx))
+			assert.Equal(t, test.events, events)
+		})
+	}
+}
+
+func TestHook(t *testing.T) {
+	var h Hook
+	assert.NoError(t, h.Start(context.Background()))
+	assert.NoError(t, h.Close(context.Background()))
+}
@@ -0,0 +1,16 @@
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying the fields, WithContext adds them to the
+// logger.
+func NewContext(ctx context.Context, fields ...interface{}) context.Context {
+	return context.WithValue(ctx, contextKey{}, append(fromContext(ctx), fields...))
+}
+
+func fromContext(ctx context.Context) []interface{} {
+	fields, _ := ctx.Value(contextKey{}).([]interface{})
+	return fields[:len(fields):len(fields)]
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/Zucke/social_prove/pkg/audit"
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+// defaultComponent names the default level in the routes.
+const defaultComponent = "default"
+
+// Handler is the router of the log levels.
+type Handler struct {
+	levels  *logger.Levels
+	auditor audit.Recorder
+	log     logger.Logger
+}
+
+type levelRequest struct {
+	Level string `json:"level"`
+}
+
+func (h *Handler) view() render.M {
+	return render.M{
+		"default":    h.levels.Default(),
+		"components": h.levels.Components(),
+	}
+}
+
+// GetAllHandler response the default level and the level of each
+// component.
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	_ = response.JSON(w, http.StatusOK, h.view())
+}
+
+// UpdateHandler set the level of a component, or the default level. The
+// empty level makes a component follow the default level again.
+func (h *Handler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
+	var req levelRequest
+	if err := validation.Decode(r.Body, &req); err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	component := chi.URLParam(r, "component")
+
+	var v validation.Validator
+	if req.Level != "" || component == defaultComponent {
+		v.OneOf("level", req.Level, logger.LevelNames...)
+	}
+	if err := v.Err(); err != nil {
+		_ = response.Error(w, err)
+		return
+	}
+
+	before := h.levels.Default()
+	var err error
+	if component == defaultComponent {
+		err = h.levels.SetDefault(req.Level)
+	} else {
+		before = h.levels.Components()[component]
+		err = h.levels.Set(component, req.Level)
+	}
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	h.log.WithContext(r.Context()).With("component", component, "level", req.Level).Info("log level changed")
+
+	e := audit.Entry{
+		Action:     audit.SetLogLevel,
+		TargetType: audit.LogTarget,
+		Before:     map[string]interface{}{"component": component, "level": before},
+		After:      map[string]interface{}{"component": component, "level": req.Level},
+	}
+	if err := h.auditor.Record(r.Context(), e); err != nil {
+		h.log.WithContext(r.Context()).Errorf("cannot audit %s of %s: %v", e.Action, component, err)
+	}
+
+	_ = response.JSON(w, http.StatusOK, h.view())
+}
+
+// Routes configure and return the routes of the log levels.
+func (h *Handler) Routes(a *auth.Authenticator) http.Handler {
+	r := chi.NewRouter()
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Get("/", h.GetAllHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Put("/{component}", h.UpdateHandler)
+
+	return r
+}
+
+// New create and configure a new Handler.
+func New(levels *logger.Levels, auditor audit.Recorder, log logger.Logger) *Handler {
+	return &Handler{
+		levels:  levels,
+		auditor: auditor,
+		log:     log,
+	}
+}
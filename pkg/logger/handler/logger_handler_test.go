@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mock "github.com/Zucke/social_prove/pkg/audit/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+)
+
+func TestHandler_GetAll(t *testing.T) {
+	l := logger.NewMock()
+	levels := l.Levels()
+	assert.NoError(t, levels.Apply("info", map[string]string{"mongo": "debug"}))
+
+	h := Handler{
+		levels: levels,
+		log:    l,
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/log/levels/", nil)
+
+	mux := chi.NewRouter()
+	mux.Get("/log/levels/", h.GetAllHandler)
+	mux.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"default":"info","components":{"mongo":"debug"}}`, w.Body.String())
+}
+
+func TestHandler_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockRecorder(ctrl)
+	l := logger.NewMock()
+
+	tests := []struct {
+		name      string
+		component string
+		body      string
+		code      int
+		times     int
+		want      string
+	}{
+		{
+			name:      "Success component",
+			component: "mongo",
+			body:      `{"level":"debug"}`,
+			code:      http.StatusOK,
+			times:     1,
+			want:      `{"default":"info","components":{"mongo":"debug"}}`,
+		},
+		{
+			name:      "Success default",
+			component: "default",
+			body:      `{"level":"warn"}`,
+			code:      http.StatusOK,
+			times:     1,
+			want:      `{"default":"warn","components":{"mongo":"warn"}}`,
+		},
+		{
+			name:      "Success reset component",
+			component: "mongo",
+			body:      `{"level":""}`,
+			code:      http.StatusOK,
+			times:     1,
+			want:      `{"default":"info","components":{"mongo":"info"}}`,
+		},
+		{
+			name:      "Failure invalid level",
+			component: "mongo",
+			body:      `{"level":"trace"}`,
+			code:      http.StatusBadRequest,
+		},
+		{
+			name:      "Failure default without level",
+			component: "default",
+			body:      `{}`,
+			code:      http.StatusBadRequest,
+		},
+		{
+			name:      "Failure unknown component",
+			component: "cache",
+			body:      `{"level":"debug"}`,
+			code:      http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				Return(nil).
+				Times(test.times)
+
+			levels := l.Levels()
+			assert.NoError(t, levels.Apply("info", map[string]string{"mongo": ""}))
+
+			h := Handler{
+				levels:  levels,
+				auditor: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/log/levels/"+test.component, strings.NewReader(test.body))
+
+			mux := chi.NewRouter()
+			mux.Put("/log/levels/{component}", h.UpdateHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+			if test.want != "" {
+				assert.JSONEq(t, test.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package logger
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// Sampling of the debug entries, each second the first sampleFirst
+// entries of a message are written and then one of each sampleThereafter.
+const (
+	sampleFirst      = 100
+	sampleThereafter = 100
+)
+
+// LevelNames are the levels that can be set.
+var LevelNames = []string{"debug", "info", "warn", "error"}
+
+// Errors.
+var (
+	ErrUnknownComponent = response.NewError(response.NotFound, "unknown log component")
+	ErrInvalidLevel     = response.NewError(response.Invalid, "invalid log level")
+)
+
+// Levels are the levels of the components of a logger, a component
+// without a level of its own follows the default level.
+type Levels struct {
+	mu         sync.Mutex
+	initial    zapcore.Level
+	def        zap.AtomicLevel
+	components map[string]*componentLevel
+}
+
+type componentLevel struct {
+	level zap.AtomicLevel
+	// own is true when the level was set for the component.
+	own bool
+}
+
+func newLevels(def zapcore.Level) *Levels {
+	return &Levels{
+		initial:    def,
+		def:        zap.NewAtomicLevelAt(def),
+		components: map[string]*componentLevel{},
+	}
+}
+
+// level returns the level of a component, registered if it wasn't.
+func (lv *Levels) level(component string) zap.AtomicLevel {
+	if component == "" {
+		return lv.def
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	return lv.component(component).level
+}
+
+func (lv *Levels) component(name string) *componentLevel {
+	c, ok := lv.components[name]
+	if !ok {
+		c = &componentLevel{level: zap.NewAtomicLevelAt(lv.def.Level())}
+		lv.components[name] = c
+	}
+
+	return c
+}
+
+// Default returns the default level.
+func (lv *Levels) Default() string {
+	return lv.def.Level().String()
+}
+
+// Components returns the level of each component.
+func (lv *Levels) Components() map[string]string {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	levels := make(map[string]string, len(lv.components))
+	for name, c := range lv.components {
+		levels[name] = c.level.Level().String()
+	}
+
+	return levels
+}
+
+// Names returns the components sorted.
+func (lv *Levels) Names() []string {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	names := make([]string, 0, len(lv.components))
+	for name := range lv.components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SetDefault set the default level, the components without a level of
+// their own follow it.
+func (lv *Levels) SetDefault(level string) error {
+	l, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	lv.setDefault(l)
+	return nil
+}
+
+func (lv *Levels) setDefault(l zapcore.Level) {
+	lv.def.SetLevel(l)
+	for _, c := range lv.components {
+		if !c.own {
+			c.level.SetLevel(l)
+		}
+	}
+}
+
+// Set the level of a component, the empty level makes it follow the
+// default level again.
+func (lv *Levels) Set(component, level string) error {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	c, ok := lv.components[component]
+	if !ok {
+		return ErrUnknownComponent
+	}
+
+	return lv.set(c, level)
+}
+
+func (lv *Levels) set(c *componentLevel, level string) error {
+	if level == "" {
+		c.own = false
+		c.level.SetLevel(lv.def.Level())
+		return nil
+	}
+
+	l, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	c.own = true
+	c.level.SetLevel(l)
+	return nil
+}
+
+// Apply the default level and the levels of the components, the
+// components left out follow the default level. The empty default level is
+// the level the logger was created with.
+func (lv *Levels) Apply(def string, components map[string]string) error {
+	l := lv.initial
+	if def != "" {
+		var err error
+		if l, err = parseLevel(def); err != nil {
+			return err
+		}
+	}
+
+	for _, level := range components {
+		if level == "" {
+			continue
+		}
+		if _, err := parseLevel(level); err != nil {
+			return err
+		}
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	lv.setDefault(l)
+	for name := range components {
+		lv.component(name)
+	}
+	for name, c := range lv.components {
+		if err := lv.set(c, components[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseLevel(level string) (zapcore.Level, error) {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil || l < zapcore.DebugLevel || l > zapcore.ErrorLevel {
+		return l, ErrInvalidLevel
+	}
+
+	return l, nil
+}
+
+// levelCore writes the entries enabled by its level only.
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(l zapcore.Level) bool {
+	return c.level.Enabled(l)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{c.Core.With(fields), c.level}
+}
+
+func (c *levelCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(e.Level) {
+		return ce
+	}
+
+	return c.Core.Check(e, ce)
+}
+
+// withLevel filters core by level, instead of the level of the previous
+// component.
+func withLevel(core zapcore.Core, level zapcore.LevelEnabler) zapcore.Core {
+	if lc, ok := core.(*levelCore); ok {
+		core = lc.Core
+	}
+
+	return &levelCore{core, level}
+}
+
+// sample drops the repeated debug entries, the others are all written.
+func sample(core zapcore.Core) zapcore.Core {
+	debug := zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l == zapcore.DebugLevel })
+	others := zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l > zapcore.DebugLevel })
+
+	return zapcore.NewTee(
+		zapcore.NewSamplerWithOptions(&levelCore{core, debug}, time.Second, sampleFirst, sampleThereafter),
+		&levelCore{core, others},
+	)
+}
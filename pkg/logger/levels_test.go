@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observed(level zapcore.Level) (*logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	levels := newLevels(level)
+	z := zap.New(withLevel(sample(core), levels.def))
+
+	return &logger{z.Sugar(), levels, ""}, logs
+}
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(lv *Levels) error
+		want   []string
+	}{
+		{
+			name:   "default",
+			change: func(lv *Levels) error { return nil },
+			want:   []string{"root info", "http info", "mongo info"},
+		},
+		{
+			name:   "component",
+			change: func(lv *Levels) error { return lv.Set("mongo", "debug") },
+			want:   []string{"root info", "http info", "mongo debug", "mongo info"},
+		},
+		{
+			name:   "default followed by the components",
+			change: func(lv *Levels) error { return lv.SetDefault("warn") },
+			want:   []string{},
+		},
+		{
+			name: "component keeps its level",
+			change: func(lv *Levels) error {
+				if err := lv.Set("http", "info"); err != nil {
+					return err
+				}
+				return lv.SetDefault("error")
+			},
+			want: []string{"http info"},
+		},
+		{
+			name: "apply",
+			change: func(lv *Levels) error {
+				if err := lv.Set("http", "error"); err != nil {
+					return err
+				}
+				return lv.Apply("", map[string]string{"mongo": "debug"})
+			},
+			want: []string{"root info", "http info", "mongo debug", "mongo info"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			log, logs := observed(zapcore.InfoLevel)
+			http := log.Named("http")
+			mongo := log.Named("mongo")
+
+			assert.NoError(t, test.change(log.Levels()))
+
+			log.Debug("root debug")
+			log.Info("root info")
+			http.Debug("http debug")
+			http.Info("http info")
+			mongo.Debug("mongo debug")
+			mongo.Info("mongo info")
+
+			got := []string{}
+			for _, e := range logs.All() {
+				got = append(got, e.Message)
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestLevels_Errors(t *testing.T) {
+	log, _ := observed(zapcore.InfoLevel)
+	log.Named("http")
+	lv := log.Levels()
+
+	assert.Equal(t, ErrUnknownComponent, lv.Set("cache", "debug"))
+	assert.Equal(t, ErrInvalidLevel, lv.Set("http", "trace"))
+	assert.Equal(t, ErrInvalidLevel, lv.SetDefault("fatal"))
+	assert.Equal(t, ErrInvalidLevel, lv.Apply("info", map[string]string{"http": "verbose"}))
+	assert.Equal(t, map[string]string{"http": "info"}, lv.Components())
+}
+
+func TestLogger_Named(t *testing.T) {
+	log, logs := observed(zapcore.InfoLevel)
+	db := log.Named("db").Named("users")
+
+	assert.Equal(t, []string{"db", "db.users"}, log.Levels().Names())
+	assert.NoError(t, log.Levels().Set("db.users", "debug"))
+
+	db.Debug("query")
+	assert.Equal(t, 1, logs.Len())
+	assert.Equal(t, "db.users", logs.All()[0].LoggerName)
+}
+
+func TestSample(t *testing.T) {
+	log, logs := observed(zapcore.DebugLevel)
+
+	for i := 0; i < sampleFirst+sampleThereafter; i++ {
+		log.Debug("noisy")
+		log.Info("steady")
+	}
+
+	assert.Equal(t, sampleFirst+1, logs.FilterMessage("noisy").Len())
+	assert.Equal(t, sampleFirst+sampleThereafter, logs.FilterMessage("steady").Len())
+}
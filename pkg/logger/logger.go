@@ -1,6 +1,12 @@
 package logger
 
-import "go.uber.org/zap"
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
 
 // Logger to print information to standard output.
 type Logger interface {
@@ -13,6 +19,10 @@ type Logger interface {
 	Warn(args ...interface{})
 	Warnf(template string, args ...interface{})
 	SetLevel(level logLevel)
+	With(fields ...interface{}) Logger
+	WithContext(ctx context.Context) Logger
+	Named(component string) Logger
+	Levels() *Levels
 }
 
 // logLevel represents a level of log.
@@ -25,35 +35,92 @@ const (
 	DEBUG
 )
 
-// logger is an adapted zap logger.
+// logger is an adapted zap logger, the level of its component filters
+// the entries.
 type logger struct {
 	*zap.SugaredLogger
-	config *zap.Config
+	levels    *Levels
+	component string
 }
 
-// New creates a new zap logger.
+// New creates a new zap logger, at info level in production and debug
+// level otherwise.
 func New(serviceName string, production bool) Logger {
+	config := zap.NewDevelopmentConfig()
+	level := zapcore.DebugLevel
 	if production {
-		config := zap.NewProductionConfig()
-		l, _ := config.Build()
-		return &logger{l.Sugar().With(zap.String("service", serviceName)), &config}
+		config = zap.NewProductionConfig()
+		level = zapcore.InfoLevel
 	}
 
-	config := zap.NewDevelopmentConfig()
-	l, _ := config.Build()
-	return &logger{l.Sugar().With(zap.String("service", serviceName)), &config}
+	// The components filter the entries and only the debug ones are
+	// sampled.
+	config.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	config.Sampling = nil
+
+	levels := newLevels(level)
+	l, _ := config.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return withLevel(sample(core), levels.def)
+	}))
+
+	return &logger{l.Sugar().With(zap.String("service", serviceName)), levels, ""}
 }
 
-// SetLevel sets the current log level.
+// SetLevel sets the current log level of the component of the logger.
 func (l *logger) SetLevel(level logLevel) {
-	switch level {
-	case DEBUG:
-		l.config.Level.SetLevel(zap.DebugLevel)
-	case INFO:
-		l.config.Level.SetLevel(zap.InfoLevel)
-	case WARNING:
-		l.config.Level.SetLevel(zap.WarnLevel)
-	case ERROR:
-		l.config.Level.SetLevel(zap.ErrorLevel)
+	levels := map[logLevel]string{
+		DEBUG:   "debug",
+		INFO:    "info",
+		WARNING: "warn",
+		ERROR:   "error",
+	}
+
+	if l.component == "" {
+		_ = l.levels.SetDefault(levels[level])
+		return
+	}
+	_ = l.levels.Set(l.component, levels[level])
+}
+
+// With returns the logger with the fields, alternated keys and values.
+func (l *logger) With(fields ...interface{}) Logger {
+	return &logger{l.SugaredLogger.With(fields...), l.levels, l.component}
+}
+
+// WithContext returns the logger with the fields of the request of ctx,
+// and the trace and span IDs of the span of ctx.
+func (l *logger) WithContext(ctx context.Context) Logger {
+	fields := fromContext(ctx)
+
+	sc := trace.SpanContextFromContext(ctx)
+	if sc.IsValid() {
+		fields = append(fields, "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+	}
+
+	if len(fields) == 0 {
+		return l
+	}
+
+	return l.With(fields...)
+}
+
+// Named returns the logger of a component, filtered by the level of the
+// component. The names of the nested components are joined by dots.
+func (l *logger) Named(component string) Logger {
+	name := component
+	if l.component != "" {
+		name = l.component + "." + component
 	}
+
+	level := l.levels.level(name)
+	z := l.Desugar().Named(component).WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return withLevel(core, level)
+	}))
+
+	return &logger{z.Sugar(), l.levels, name}
+}
+
+// Levels returns the levels of the components of the logger.
+func (l *logger) Levels() *Levels {
+	return l.levels
 }
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+
+	"github.com/Zucke/social_prove/pkg/metrics"
+)
+
+// request is what's known of a request once it's routed and
+// authenticated, read when each line is written.
+type request struct {
+	mu     sync.RWMutex
+	r      *http.Request
+	route  string
+	userID string
+}
+
+type requestKey struct{}
+
+// routeField is the route pattern of the request, the pattern is complete
+// once the request is routed.
+type routeField struct{ *request }
+
+func (f routeField) String() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.route != "" {
+		return f.route
+	}
+	return metrics.Route(f.r)
+}
+
+// userField is the ID of the authenticated user of the request.
+type userField struct{ *request }
+
+func (f userField) String() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.userID
+}
+
+// SetUser set the user of the request of ctx, the authenticator sets it.
+func SetUser(ctx context.Context, id string) {
+	if req, ok := ctx.Value(requestKey{}).(*request); ok {
+		req.mu.Lock()
+		req.userID = id
+		req.mu.Unlock()
+	}
+}
+
+// Middleware injects in the context of each request its request ID, route
+// and user for WithContext, and writes a line for the request once served.
+// It must follow middleware.RequestID.
+func Middleware(log Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			req := &request{r: r}
+
+			ctx := context.WithValue(r.Context(), requestKey{}, req)
+			ctx = NewContext(
+				ctx,
+				"request_id", middleware.GetReqID(r.Context()),
+				"route", routeField{req},
+				"user_id", userField{req},
+			)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			// The route is kept, chi reuses its context after the request.
+			req.mu.Lock()
+			req.route = metrics.Route(r)
+			req.mu.Unlock()
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			l := log.WithContext(ctx).With(
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", ww.BytesWritten(),
+				"duration", time.Since(start),
+				"remote_ip", r.RemoteAddr,
+				"user_agent", r.UserAgent(),
+			)
+			if status >= http.StatusInternalServerError {
+				l.Error("request served")
+				return
+			}
+			l.Info("request served")
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		status int
+		level  zapcore.Level
+	}{
+		{
+			name:   "authenticated",
+			userID: "5fe1d8e0e4b0a1b2c3d4e5f6",
+			status: http.StatusOK,
+			level:  zapcore.InfoLevel,
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			level:  zapcore.ErrorLevel,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			log := &logger{zap.New(core).Sugar(), newLevels(zapcore.DebugLevel), ""}
+
+			r := chi.NewRouter()
+			r.Use(middleware.RequestID)
+			r.Use(Middleware(log))
+			r.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+				if test.userID != "" {
+					SetUser(r.Context(), test.userID)
+				}
+				log.WithContext(r.Context()).Warn("handled")
+				w.WriteHeader(test.status)
+			})
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/1", nil))
+
+			entries := logs.AllUntimed()
+			assert.Len(t, entries, 2)
+
+			handled := entries[0].ContextMap()
+			assert.Equal(t, "handled", entries[0].Message)
+			assert.NotEmpty(t, handled["request_id"])
+			assert.Equal(t, "/user/{id}", handled["route"])
+			assert.Equal(t, test.userID, handled["user_id"])
+
+			served := entries[1].ContextMap()
+			assert.Equal(t, test.level, entries[1].Level)
+			assert.Equal(t, handled["request_id"], served["request_id"])
+			assert.Equal(t, "/user/{id}", served["route"])
+			assert.Equal(t, test.userID, served["user_id"])
+			assert.Equal(t, int64(test.status), served["status"])
+			assert.Equal(t, "/user/1", served["path"])
+		})
+	}
+}
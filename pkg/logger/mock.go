@@ -2,7 +2,10 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"log"
+
+	"go.uber.org/zap/zapcore"
 )
 
 // Mock to logger.
@@ -44,6 +47,22 @@ func (m Mock) Warnf(template string, args ...interface{}) {
 
 func (m Mock) SetLevel(level logLevel) {}
 
+func (m Mock) With(fields ...interface{}) Logger {
+	return m
+}
+
+func (m Mock) WithContext(ctx context.Context) Logger {
+	return m
+}
+
+func (m Mock) Named(component string) Logger {
+	return m
+}
+
+func (m Mock) Levels() *Levels {
+	return newLevels(zapcore.DebugLevel)
+}
+
 // NewMock returns a new mock logger.
 func NewMock() Logger {
 	return &Mock{
@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "social"
+
+// Methods of the signups and logins.
+const (
+	Password = "password"
+	Google   = "google"
+)
+
+// unmatched is the route of the requests without route, so the unknown
+// paths don't make a series each.
+const unmatched = "unmatched"
+
+// HTTP metrics, by method, route pattern and status code.
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests handled.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// MongoDuration is the latency of the MongoDB commands, by command and
+// whether it succeeded or failed.
+var MongoDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "mongo",
+	Name:      "command_duration_seconds",
+	Help:      "Latency of the MongoDB commands.",
+	Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"command", "status"})
+
+// Business events.
+var (
+	Signups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signups_total",
+		Help:      "Users signed up, by method.",
+	}, []string{"method"})
+
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Successful logins, by method.",
+	}, []string{"method"})
+
+	PostsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "posts_created_total",
+		Help:      "Posts created.",
+	})
+
+	Reactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reactions_total",
+		Help:      "Reactions to posts, by type, like are the likes.",
+	}, []string{"type"})
+
+	Follows = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "follows_total",
+		Help:      "Users followed.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		HTTPRequests,
+		HTTPDuration,
+		MongoDuration,
+		Signups,
+		Logins,
+		PostsCreated,
+		Reactions,
+		Follows,
+	)
+}
+
+// Handler serves the metrics in the Prometheus format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware measures the requests by route pattern, it must wrap the
+// router so the pattern is complete once the request is served.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		labels := prometheus.Labels{"method": r.Method, "route": Route(r), "status": strconv.Itoa(status)}
+		HTTPRequests.With(labels).Inc()
+		HTTPDuration.With(labels).Observe(time.Since(start).Seconds())
+	})
+}
+
+// Route returns the route pattern of a served request, or unmatched for
+// the requests without route.
+func Route(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.RoutePattern() == "" {
+		return unmatched
+	}
+
+	// The mounted routers join their patterns with a slash each.
+	return strings.ReplaceAll(rctx.RoutePattern(), "//", "/")
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	users := chi.NewRouter()
+	users.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	users.Post("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Mount("/api/v1/user", users)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		route  string
+		status string
+	}{
+		{
+			name:   "route pattern",
+			method: http.MethodGet,
+			path:   "/api/v1/user/5fe1d8e0e4b0a1b2c3d4e5f6",
+			route:  "/api/v1/user/{id}",
+			status: "404",
+		},
+		{
+			name:   "status not written",
+			method: http.MethodPost,
+			path:   "/api/v1/user/",
+			route:  "/api/v1/user/",
+			status: "200",
+		},
+		{
+			name:   "unmatched",
+			method: http.MethodGet,
+			path:   "/wp-login.php",
+			route:  unmatched,
+			status: "404",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			counter := HTTPRequests.WithLabelValues(test.method, test.route, test.status)
+			before := testutil.ToFloat64(counter)
+
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(test.method, test.path, nil))
+
+			assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
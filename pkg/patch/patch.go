@@ -0,0 +1,219 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/security"
+)
+
+// ContentType is the media type of a JSON merge patch.
+const ContentType = "application/merge-patch+json"
+
+// AnyVersion is the version of an update without precondition.
+const AnyVersion int64 = -1
+
+// Errors.
+var (
+	ErrInvalidPatch    = response.NewError(response.Invalid, "invalid merge patch")
+	ErrUnsupportedType = response.NewError(response.Unsupported, "unsupported media type, use "+ContentType)
+)
+
+// FieldError is a patch changing a field it can't, or with a bad value.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s %s", e.Field, e.Reason)
+}
+
+// Kind returns the kind of the error, a bad patch is Invalid.
+func (e *FieldError) Kind() response.Kind {
+	return response.Invalid
+}
+
+// Merge returns doc with the JSON merge patch p (RFC 7396) applied, doc
+// and p are decoded JSON values.
+func Merge(doc, p interface{}) interface{} {
+	pm, ok := p.(map[string]interface{})
+	if !ok {
+		return p
+	}
+
+	dm, ok := doc.(map[string]interface{})
+	if !ok {
+		dm = make(map[string]interface{}, len(pm))
+	}
+
+	for k, v := range pm {
+		if v == nil {
+			delete(dm, k)
+			continue
+		}
+
+		dm[k] = Merge(dm[k], v)
+	}
+
+	return dm
+}
+
+// Apply apply the JSON merge patch p to the struct pointed by v. Only the
+// fields named by their JSON names can be changed, a null resets a field.
+// The other fields of v are kept as they are, even the hidden from JSON.
+func Apply(v interface{}, p []byte, fields ...string) error {
+	var changes map[string]interface{}
+	if err := json.Unmarshal(p, &changes); err != nil || changes == nil {
+		return ErrInvalidPatch
+	}
+
+	allowed := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		allowed[f] = true
+	}
+
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		if !allowed[name] {
+			return &FieldError{Field: name, Reason: "cannot be changed"}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rv := reflect.ValueOf(v).Elem()
+	patched := reflect.New(rv.Type()).Elem()
+	patched.Set(rv)
+
+	for _, name := range names {
+		fv, ok := field(patched, name)
+		if !ok {
+			return &FieldError{Field: name, Reason: "cannot be changed"}
+		}
+
+		if changes[name] == nil {
+			fv.Set(reflect.Zero(fv.Type()))
+			continue
+		}
+
+		if err := set(fv, changes[name]); err != nil {
+			return &FieldError{Field: name, Reason: "is invalid"}
+		}
+	}
+
+	rv.Set(patched)
+	return nil
+}
+
+// set merge the change into the current value of fv.
+func set(fv reflect.Value, change interface{}) error {
+	b, err := json.Marshal(fv.Interface())
+	if err != nil {
+		return err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+
+	b, err = json.Marshal(Merge(doc, change))
+	if err != nil {
+		return err
+	}
+
+	nv := reflect.New(fv.Type())
+	if err := json.Unmarshal(b, nv.Interface()); err != nil {
+		return err
+	}
+
+	fv.Set(nv.Elem())
+	return nil
+}
+
+// field returns the field of the struct rv with the JSON name.
+func field(rv reflect.Value, name string) (reflect.Value, bool) {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tag := strings.Split(f.Tag.Get("json"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+
+		if tag == name {
+			return rv.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// Read returns the merge patch in the body of r, sent as
+// application/merge-patch+json or application/json. The size of the patch
+// is limited by security.LimitBody, a larger one fails with
+// security.ErrBodyTooLarge.
+func Read(r *http.Request) ([]byte, error) {
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mt != ContentType && mt != "application/json") {
+		return nil, ErrUnsupportedType
+	}
+
+	p, err := ioutil.ReadAll(r.Body)
+	if errors.Is(err, security.ErrBodyTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, ErrInvalidPatch
+	}
+
+	return p, nil
+}
+
+// ETag returns the entity tag of a version of a resource.
+func ETag(version int64) string {
+	if version < 0 {
+		version = 0
+	}
+
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// IfMatch returns the version required by the If-Match header of r,
+// AnyVersion without header or with *. A tag that isn't one of ours can't
+// match, response.ErrPreconditionFailed is returned.
+func IfMatch(r *http.Request) (int64, error) {
+	h := strings.TrimSpace(r.Header.Get("If-Match"))
+	if h == "" || h == "*" {
+		return AnyVersion, nil
+	}
+
+	s, err := strconv.Unquote(h)
+	if err != nil {
+		return 0, response.ErrPreconditionFailed
+	}
+
+	version, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || version < 0 {
+		return 0, response.ErrPreconditionFailed
+	}
+
+	return version, nil
+}
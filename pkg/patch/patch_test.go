@@ -0,0 +1,195 @@
+package patch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/security"
+)
+
+type inner struct {
+	A string `json:"a,omitempty"`
+	B string `json:"b,omitempty"`
+}
+
+type doc struct {
+	Name   string     `json:"name,omitempty"`
+	Bio    string     `json:"bio,omitempty"`
+	At     *time.Time `json:"at,omitempty"`
+	Inner  *inner     `json:"inner,omitempty"`
+	Tags   []string   `json:"tags,omitempty"`
+	Role   int        `json:"role,omitempty"`
+	Secret string     `json:"-"`
+}
+
+func TestApply(t *testing.T) {
+	at := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	fields := []string{"name", "bio", "at", "inner", "tags"}
+
+	tests := []struct {
+		name  string
+		patch string
+		want  doc
+		err   error
+	}{
+		{
+			name:  "only the supplied fields",
+			patch: `{"bio":"new"}`,
+			want:  doc{Name: "jane", Bio: "new", Inner: &inner{A: "a", B: "b"}, Tags: []string{"x"}, Role: 1, Secret: "s"},
+		},
+		{
+			name:  "null resets a field",
+			patch: `{"name":null,"inner":null}`,
+			want:  doc{Bio: "old", Tags: []string{"x"}, Role: 1, Secret: "s"},
+		},
+		{
+			name:  "objects are merged and arrays replaced",
+			patch: `{"inner":{"b":null},"tags":["y","z"],"at":"2021-01-02T03:04:05Z"}`,
+			want:  doc{Name: "jane", Bio: "old", At: &at, Inner: &inner{A: "a"}, Tags: []string{"y", "z"}, Role: 1, Secret: "s"},
+		},
+		{
+			name:  "field not allowed",
+			patch: `{"bio":"new","role":2}`,
+			err:   &FieldError{Field: "role", Reason: "cannot be changed"},
+		},
+		{
+			name:  "hidden field",
+			patch: `{"Secret":"x"}`,
+			err:   &FieldError{Field: "Secret", Reason: "cannot be changed"},
+		},
+		{
+			name:  "bad value",
+			patch: `{"bio":"new","at":"yesterday"}`,
+			err:   &FieldError{Field: "at", Reason: "is invalid"},
+		},
+		{
+			name:  "not an object",
+			patch: `["bio"]`,
+			err:   ErrInvalidPatch,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := doc{Name: "jane", Bio: "old", Inner: &inner{A: "a", B: "b"}, Tags: []string{"x"}, Role: 1, Secret: "s"}
+			orig := d
+
+			err := Apply(&d, []byte(test.patch), fields...)
+			assert.Equal(t, test.err, err)
+			if err != nil {
+				// A failed patch leaves the value as it was.
+				assert.Equal(t, orig, d)
+				return
+			}
+
+			assert.Equal(t, test.want, d)
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version int64
+		err     error
+	}{
+		{
+			name:    "without header",
+			version: AnyVersion,
+		},
+		{
+			name:    "any",
+			header:  "*",
+			version: AnyVersion,
+		},
+		{
+			name:    "version",
+			header:  ETag(3),
+			version: 3,
+		},
+		{
+			name:   "weak tag",
+			header: `W/"3"`,
+			err:    response.ErrPreconditionFailed,
+		},
+		{
+			name:   "not a version",
+			header: `"abc"`,
+			err:    response.ErrPreconditionFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("PATCH", "/", nil)
+			if test.header != "" {
+				r.Header.Set("If-Match", test.header)
+			}
+
+			version, err := IfMatch(r)
+			assert.Equal(t, test.err, err)
+			if err == nil {
+				assert.Equal(t, test.version, version)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		err         error
+	}{
+		{
+			name:        "merge patch",
+			contentType: ContentType,
+			body:        `{"name":"a"}`,
+		},
+		{
+			name:        "json",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"name":"a"}`,
+		},
+		{
+			name:        "failure unsupported type",
+			contentType: "text/plain",
+			body:        `{"name":"a"}`,
+			err:         ErrUnsupportedType,
+		},
+		{
+			// The limit of the server is kept, not turned into a bad patch.
+			name:        "failure too large",
+			contentType: ContentType,
+			body:        `{"name":"` + strings.Repeat("a", 64) + `"}`,
+			err:         security.ErrBodyTooLarge,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var p []byte
+			var err error
+			h := security.LimitBody(32)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				p, err = Read(r)
+			}))
+
+			r := httptest.NewRequest("PATCH", "/", strings.NewReader(test.body))
+			r.Header.Set("Content-Type", test.contentType)
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			assert.Equal(t, test.err, err)
+			if err == nil {
+				assert.Equal(t, test.body, string(p))
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package picture
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status of the picture processing.
+type Status string
+
+// Processing statuses.
+const (
+	Pending Status = "pending"
+	Ready   Status = "ready"
+	Failed  Status = "failed"
+)
+
+// Formats of the generated variants.
+const (
+	JPEG = "jpeg"
+	WebP = "webp"
+)
+
+// Size is a responsive size generated for every picture.
+type Size struct {
+	Name     string
+	MaxWidth int
+}
+
+// Sizes generated for every uploaded picture.
+var Sizes = []Size{
+	{Name: "thumbnail", MaxWidth: 150},
+	{Name: "feed", MaxWidth: 640},
+	{Name: "full", MaxWidth: 1280},
+}
+
+// ErrTooLarge is the error of the pictures over their Limits.
+var ErrTooLarge = errors.New("picture too large")
+
+// Limits are the largest pictures accepted, MaxBytes limits the upload and
+// the others the picture once decoded, a small file can declare a picture
+// that takes all the memory.
+type Limits struct {
+	MaxBytes  int64 `yaml:"max_bytes"`
+	MaxWidth  int   `yaml:"max_width"`
+	MaxHeight int   `yaml:"max_height"`
+	MaxPixels int64 `yaml:"max_pixels"`
+}
+
+// Check returns ErrTooLarge for a picture of width x height over l.
+func (l Limits) Check(width, height int) error {
+	if width > l.MaxWidth || height > l.MaxHeight || int64(width)*int64(height) > l.MaxPixels {
+		return ErrTooLarge
+	}
+
+	return nil
+}
+
+// Variant is a resized rendition of a picture.
+type Variant struct {
+	Name   string `json:"name" bson:"name"`
+	Format string `json:"format" bson:"format"`
+	URL    string `json:"url" bson:"url"`
+	Key    string `json:"-" bson:"key"`
+	Width  int    `json:"width" bson:"width"`
+	Height int    `json:"height" bson:"height"`
+}
+
+// Picture is an uploaded image with its generated variants.
+type Picture struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	URL       string             `json:"url,omitempty" bson:"url,omitempty"`
+	Key       string             `json:"-" bson:"key,omitempty"`
+	Width     int                `json:"width,omitempty" bson:"width,omitempty"`
+	Height    int                `json:"height,omitempty" bson:"height,omitempty"`
+	BlurHash  string             `json:"blurhash,omitempty" bson:"blurhash,omitempty"`
+	Status    Status             `json:"status,omitempty" bson:"status,omitempty"`
+	Variants  []Variant          `json:"variants,omitempty" bson:"variants,omitempty"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// Job is a picture waiting to be processed.
+type Job struct {
+	PostID  primitive.ObjectID
+	Picture Picture
+}
@@ -0,0 +1,186 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	// Decoders for the supported upload formats.
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/buckket/go-blurhash"
+	"github.com/disintegration/imaging"
+
+	"github.com/Zucke/social_prove/pkg/picture"
+)
+
+const (
+	jpegQuality = 85
+	webpQuality = 80
+
+	blurHashSize = 32
+	blurHashX    = 4
+	blurHashY    = 3
+)
+
+// Processor generates the responsive variants of a picture.
+type Processor struct {
+	storage picture.Storage
+	limits  picture.Limits
+	cwebp   string
+}
+
+// Process decode the original picture and stores every variant, the
+// pictures over the limits fail with picture.ErrTooLarge before they are
+// decoded.
+func (p *Processor) Process(ctx context.Context, pic picture.Picture) (picture.Picture, error) {
+	rc, err := p.storage.Open(ctx, pic.Key)
+	if err != nil {
+		return pic, err
+	}
+	defer rc.Close()
+
+	// The header read for the size is decoded again with the rest.
+	var head bytes.Buffer
+	cfg, _, err := image.DecodeConfig(io.TeeReader(rc, &head))
+	if err != nil {
+		return pic, err
+	}
+	if err := p.limits.Check(cfg.Width, cfg.Height); err != nil {
+		return pic, err
+	}
+
+	img, _, err := image.Decode(io.MultiReader(&head, rc))
+	if err != nil {
+		return pic, err
+	}
+
+	bounds := img.Bounds()
+	pic.Width = bounds.Dx()
+	pic.Height = bounds.Dy()
+
+	pic.BlurHash, err = blurhash.Encode(blurHashX, blurHashY, imaging.Fit(img, blurHashSize, blurHashSize, imaging.Box))
+	if err != nil {
+		return pic, err
+	}
+
+	pic.Variants = make([]picture.Variant, 0, len(picture.Sizes)*2)
+	for _, size := range picture.Sizes {
+		resized := img
+		if pic.Width > size.MaxWidth {
+			resized = imaging.Resize(img, size.MaxWidth, 0, imaging.Lanczos)
+		}
+
+		v, err := p.saveJPEG(ctx, pic, size, resized)
+		if err != nil {
+			return pic, err
+		}
+		pic.Variants = append(pic.Variants, v)
+
+		if p.cwebp == "" {
+			continue
+		}
+
+		v, err = p.saveWebP(ctx, pic, size, resized)
+		if err != nil {
+			return pic, err
+		}
+		pic.Variants = append(pic.Variants, v)
+	}
+
+	pic.Status = picture.Ready
+
+	return pic, nil
+}
+
+// WebP reports whether the WebP variants are generated, that is whether
+// the cwebp binary was found.
+func (p *Processor) WebP() bool {
+	return p.cwebp != ""
+}
+
+// saveJPEG store a JPEG variant, JPEG has no alpha channel so the
+// transparent pixels of the picture lose their transparency.
+func (p *Processor) saveJPEG(ctx context.Context, pic picture.Picture, size picture.Size, img image.Image) (picture.Variant, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return picture.Variant{}, err
+	}
+
+	return p.save(ctx, pic, size, picture.JPEG, img, &buf)
+}
+
+func (p *Processor) saveWebP(ctx context.Context, pic picture.Picture, size picture.Size, img image.Image) (picture.Variant, error) {
+	in, err := ioutil.TempFile("", "picture-*.png")
+	if err != nil {
+		return picture.Variant{}, err
+	}
+	defer os.Remove(in.Name())
+
+	err = imaging.Encode(in, img, imaging.PNG)
+	if cerr := in.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return picture.Variant{}, err
+	}
+
+	out := in.Name() + ".webp"
+	defer os.Remove(out)
+
+	cmd := exec.CommandContext(ctx, p.cwebp, "-quiet", "-q", fmt.Sprint(webpQuality), in.Name(), "-o", out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return picture.Variant{}, fmt.Errorf("cwebp: %v: %s", err, output)
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		return picture.Variant{}, err
+	}
+	defer f.Close()
+
+	return p.save(ctx, pic, size, picture.WebP, img, f)
+}
+
+func (p *Processor) save(ctx context.Context, pic picture.Picture, size picture.Size, format string, img image.Image, r io.Reader) (picture.Variant, error) {
+	base := strings.TrimSuffix(pic.Key, path.Ext(pic.Key))
+	key := fmt.Sprintf("%s_%s.%s", base, size.Name, format)
+	url, err := p.storage.Save(ctx, key, r)
+	if err != nil {
+		return picture.Variant{}, err
+	}
+
+	bounds := img.Bounds()
+	return picture.Variant{
+		Name:   size.Name,
+		Format: format,
+		URL:    url,
+		Key:    key,
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}, nil
+}
+
+// New create a new Processor of the pictures within limits, WebP variants
+// are only generated when the cwebp binary is in the PATH, otherwise the
+// pictures only get JPEG variants.
+func New(storage picture.Storage, limits picture.Limits) *Processor {
+	cwebp, _ := exec.LookPath("cwebp")
+
+	return &Processor{
+		storage: storage,
+		limits:  limits,
+		cwebp:   cwebp,
+	}
+}
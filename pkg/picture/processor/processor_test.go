@@ -0,0 +1,234 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/picture/storage"
+)
+
+// limits are the limits of the processors of the tests.
+var limits = picture.Limits{MaxWidth: 4000, MaxHeight: 4000, MaxPixels: 4000000}
+
+// savePNG stores a 2000x1000 picture under key.
+func savePNG(t *testing.T, s picture.Storage, key string) {
+	img := image.NewRGBA(image.Rect(0, 0, 2000, 1000))
+	for x := 0; x < 2000; x++ {
+		for y := 0; y < 1000; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+
+	_, err := s.Save(context.Background(), key, &buf)
+	assert.NoError(t, err)
+}
+
+// fakeCWebP writes a cwebp that runs script with the input file as $4 and
+// the output file as $6.
+func fakeCWebP(t *testing.T, dir, script string) string {
+	name := filepath.Join(dir, "cwebp")
+	assert.NoError(t, ioutil.WriteFile(name, []byte("#!/bin/sh\n"+script+"\n"), 0700))
+
+	return name
+}
+
+func TestProcessor_Process(t *testing.T) {
+	dir, err := ioutil.TempDir("", "processor")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := storage.Local(dir, "http://localhost/media")
+	ctx := context.Background()
+	savePNG(t, s, "posts/1/2.png")
+
+	// Without cwebp only the JPEG variants are generated.
+	p := Processor{storage: s, limits: limits}
+	assert.False(t, p.WebP())
+
+	pic, err := p.Process(ctx, picture.Picture{Key: "posts/1/2.png", Status: picture.Pending})
+	assert.NoError(t, err)
+	assert.Equal(t, picture.Ready, pic.Status)
+	assert.Equal(t, 2000, pic.Width)
+	assert.Equal(t, 1000, pic.Height)
+	assert.NotEmpty(t, pic.BlurHash)
+	assert.Len(t, pic.Variants, len(picture.Sizes))
+
+	for i, size := range picture.Sizes {
+		v := pic.Variants[i]
+		assert.Equal(t, size.Name, v.Name)
+		assert.Equal(t, picture.JPEG, v.Format)
+		assert.Equal(t, size.MaxWidth, v.Width)
+		assert.Equal(t, size.MaxWidth/2, v.Height)
+		assert.Equal(t, "http://localhost/media/"+v.Key, v.URL)
+	}
+}
+
+func TestProcessor_ProcessInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "processor")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := storage.Local(dir, "http://localhost/media")
+	ctx := context.Background()
+
+	_, err = s.Save(ctx, "posts/1/2.png", bytes.NewReader([]byte("not a picture")))
+	assert.NoError(t, err)
+
+	p := Processor{storage: s, limits: limits}
+
+	_, err = p.Process(ctx, picture.Picture{Key: "posts/1/2.png", Status: picture.Pending})
+	assert.Error(t, err)
+}
+
+// bombPNG returns a PNG of a single pixel that declares width x height in
+// its header.
+func bombPNG(t *testing.T, width, height uint32) []byte {
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))))
+
+	b := buf.Bytes()
+	// The IHDR chunk follows the 8 bytes of the signature, its data starts
+	// with the width and the height and its CRC covers its type and data.
+	binary.BigEndian.PutUint32(b[16:], width)
+	binary.BigEndian.PutUint32(b[20:], height)
+	binary.BigEndian.PutUint32(b[29:], crc32.ChecksumIEEE(b[12:29]))
+
+	return b
+}
+
+func TestProcessor_ProcessTooLarge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "processor")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := storage.Local(dir, "http://localhost/media")
+	ctx := context.Background()
+
+	_, err = s.Save(ctx, "posts/1/bomb.png", bytes.NewReader(bombPNG(t, 50000, 50000)))
+	assert.NoError(t, err)
+	savePNG(t, s, "posts/1/2.png")
+
+	tests := []struct {
+		name   string
+		key    string
+		limits picture.Limits
+		err    error
+	}{
+		{
+			name:   "Declared size over the limits",
+			key:    "posts/1/bomb.png",
+			limits: limits,
+			err:    picture.ErrTooLarge,
+		},
+		{
+			name:   "Over the width",
+			key:    "posts/1/2.png",
+			limits: picture.Limits{MaxWidth: 1999, MaxHeight: 4000, MaxPixels: 4000000},
+			err:    picture.ErrTooLarge,
+		},
+		{
+			name:   "Over the pixels",
+			key:    "posts/1/2.png",
+			limits: picture.Limits{MaxWidth: 4000, MaxHeight: 4000, MaxPixels: 1999999},
+			err:    picture.ErrTooLarge,
+		},
+		{
+			name:   "At the limits",
+			key:    "posts/1/2.png",
+			limits: picture.Limits{MaxWidth: 2000, MaxHeight: 1000, MaxPixels: 2000000},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := Processor{storage: s, limits: test.limits}
+
+			pic, err := p.Process(ctx, picture.Picture{Key: test.key, Status: picture.Pending})
+			assert.Equal(t, test.err, err)
+			if test.err != nil {
+				assert.Equal(t, picture.Pending, pic.Status)
+				assert.Empty(t, pic.Variants)
+				return
+			}
+			assert.Equal(t, picture.Ready, pic.Status)
+		})
+	}
+}
+
+func TestProcessor_ProcessWebP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "processor")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := storage.Local(filepath.Join(dir, "media"), "http://localhost/media")
+	ctx := context.Background()
+	savePNG(t, s, "posts/1/2.png")
+
+	tests := []struct {
+		name   string
+		script string
+		err    bool
+	}{
+		{
+			name:   "Success",
+			script: `[ "$1 $2 $3 $5" = "-quiet -q 80 -o" ] && cp "$4" "$6"`,
+		},
+		{
+			name:   "Failure cwebp",
+			script: "echo unsupported >&2; exit 1",
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := Processor{storage: s, limits: limits, cwebp: fakeCWebP(t, dir, test.script)}
+			assert.True(t, p.WebP())
+
+			pic, err := p.Process(ctx, picture.Picture{Key: "posts/1/2.png", Status: picture.Pending})
+			if test.err {
+				assert.Error(t, err)
+				assert.Equal(t, picture.Pending, pic.Status)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, picture.Ready, pic.Status)
+			assert.Len(t, pic.Variants, len(picture.Sizes)*2)
+
+			for i, size := range picture.Sizes {
+				jpg, webp := pic.Variants[2*i], pic.Variants[2*i+1]
+				assert.Equal(t, picture.JPEG, jpg.Format)
+				assert.Equal(t, picture.WebP, webp.Format)
+				assert.Equal(t, size.Name, webp.Name)
+				assert.Equal(t, size.MaxWidth, webp.Width)
+				assert.Equal(t, "posts/1/2_"+size.Name+".webp", webp.Key)
+				assert.Equal(t, "http://localhost/media/"+webp.Key, webp.URL)
+
+				// The fake cwebp copies its PNG input.
+				rc, err := s.Open(ctx, webp.Key)
+				assert.NoError(t, err)
+				img, format, err := image.Decode(rc)
+				rc.Close()
+				assert.NoError(t, err)
+				assert.Equal(t, "png", format)
+				assert.Equal(t, size.MaxWidth, img.Bounds().Dx())
+			}
+		})
+	}
+}
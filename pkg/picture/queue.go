@@ -0,0 +1,6 @@
+package picture
+
+// Queue receives the pictures to be processed asynchronously.
+type Queue interface {
+	Enqueue(job Job) error
+}
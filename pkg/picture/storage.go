@@ -0,0 +1,13 @@
+package picture
+
+import (
+	"context"
+	"io"
+)
+
+// Storage keeps the original pictures and their variants.
+type Storage interface {
+	Save(ctx context.Context, key string, r io.Reader) (url string, err error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
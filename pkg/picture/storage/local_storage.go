@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Zucke/social_prove/pkg/picture"
+)
+
+// Errors.
+var (
+	ErrInvalidKey = errors.New("invalid storage key")
+)
+
+// LocalStorage stores the pictures on the local file system.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// Save write the content of r under key and returns its public URL.
+func (s *LocalStorage) Save(ctx context.Context, key string, r io.Reader) (string, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(p)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return "", err
+	}
+
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	return s.baseURL + "/" + key, nil
+}
+
+// Open returns the content stored under key.
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(p)
+}
+
+// Delete remove the content stored under key.
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// ServeHTTP serves the stored files, without listing the directories.
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	http.FileServer(files{http.Dir(s.dir)}).ServeHTTP(w, r)
+}
+
+// files is a file system without directories, their listings would expose
+// the keys of every picture, even the pending ones.
+type files struct {
+	http.FileSystem
+}
+
+func (fs files) Open(name string) (http.File, error) {
+	f, err := fs.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if fi.IsDir() {
+		_ = f.Close()
+		return nil, os.ErrNotExist
+	}
+
+	return f, nil
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
+
+// Local create a new storage on dir, served from baseURL.
+func Local(dir, baseURL string) picture.Storage {
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage_ServeHTTP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := Local(dir, "http://localhost/media")
+	_, err = s.Save(context.Background(), "posts/1/2.png", strings.NewReader("picture"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		body   string
+	}{
+		{
+			name:   "File",
+			path:   "/posts/1/2.png",
+			status: http.StatusOK,
+			body:   "picture",
+		},
+		{
+			name:   "Directory",
+			path:   "/posts/1/",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "Root",
+			path:   "/",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "Missing",
+			path:   "/posts/1/3.png",
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.(http.Handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			assert.Equal(t, test.status, w.Code)
+			if test.body != "" {
+				assert.Equal(t, test.body, w.Body.String())
+			}
+			assert.NotContains(t, w.Body.String(), "2.png")
+		})
+	}
+}
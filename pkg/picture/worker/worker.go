@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/picture/processor"
+	"github.com/Zucke/social_prove/pkg/post"
+	"github.com/Zucke/social_prove/pkg/queue"
+)
+
+const (
+	queueSize = 100
+	waitTime  = 60
+)
+
+// Worker process the uploaded pictures in background.
+type Worker struct {
+	queue      *queue.Queue
+	processor  *processor.Processor
+	repository post.Repository
+	log        logger.Logger
+}
+
+// Enqueue add a picture to be processed.
+func (w *Worker) Enqueue(job picture.Job) error {
+	return w.queue.Enqueue(job)
+}
+
+// Start launch the workers and enqueue the pictures left pending.
+func (w *Worker) Start(ctx context.Context) error {
+	if !w.processor.WebP() {
+		w.log.Warn("cwebp not found in the PATH, the pictures get no WebP variants")
+	}
+
+	if err := w.queue.Start(ctx); err != nil {
+		return err
+	}
+
+	posts, err := w.repository.GetWithPendingPictures(ctx)
+	if err != nil {
+		w.log.Errorf("cannot get pending pictures: %v", err)
+		return err
+	}
+
+	for _, p := range posts {
+		for _, pic := range p.Pictures {
+			if pic.Status != picture.Pending {
+				continue
+			}
+
+			if err := w.Enqueue(picture.Job{PostID: p.ID, Picture: pic}); err != nil {
+				w.log.Warnf("cannot enqueue picture %s: %v", pic.ID.Hex(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close stop receiving jobs and wait for the workers to finish.
+func (w *Worker) Close(ctx context.Context) error {
+	return w.queue.Close(ctx)
+}
+
+func (w *Worker) process(job picture.Job) {
+	ctx, cancel := context.WithTimeout(context.Background(), waitTime*time.Second)
+	defer cancel()
+
+	pic, err := w.processor.Process(ctx, job.Picture)
+	if err != nil {
+		w.log.Errorf("cannot process picture %s: %v", job.Picture.ID.Hex(), err)
+		pic = job.Picture
+		pic.Status = picture.Failed
+	}
+
+	if err := w.repository.UpdatePicture(ctx, job.PostID, pic); err != nil {
+		w.log.Errorf("cannot update picture %s: %v", pic.ID.Hex(), err)
+	}
+}
+
+// New create a new Worker with n concurrent workers, the pictures over
+// limits fail.
+func New(log logger.Logger, storage picture.Storage, limits picture.Limits, repository post.Repository, n int) *Worker {
+	w := &Worker{
+		processor:  processor.New(storage, limits),
+		repository: repository,
+		log:        log,
+	}
+	w.queue = queue.New(queueSize, n, func(job interface{}) {
+		w.process(job.(picture.Job))
+	})
+
+	return w
+}
@@ -1,29 +1,56 @@
 package handler
 
 import (
+	"bufio"
 	"context"
-	"encoding/json"
-	"fmt"
-
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/Zucke/social_prove/pkg/audit"
 	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/badge"
 	"github.com/Zucke/social_prove/pkg/logger"
 	"github.com/Zucke/social_prove/pkg/pagination"
+	"github.com/Zucke/social_prove/pkg/patch"
+	"github.com/Zucke/social_prove/pkg/picture"
 	"github.com/Zucke/social_prove/pkg/post"
 	"github.com/Zucke/social_prove/pkg/post/service"
+	"github.com/Zucke/social_prove/pkg/reaction"
 	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/security"
 	"github.com/Zucke/social_prove/pkg/user"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+const (
+	defaultReactionsLimit = 20
+	defaultRevisionsLimit = 20
+)
+
+// Errors.
+var (
+	ErrInvalidPicture = response.NewError(response.Invalid, "invalid picture")
 )
 
+// pictureExtensions supported upload content types.
+var pictureExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
 // Handler is the router of the post.
 type Handler struct {
-	service post.Service
-	log     logger.Logger
+	service         post.Service
+	maxPictureBytes int64
+	log             logger.Logger
 }
 
 // GetAllHandler response all the posts.
@@ -35,21 +62,24 @@ func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
 	)
 	page, limit, ok := pagination.GetPagination(r)
 
+	// The viewer is optional, it's only used to mark the liked posts.
+	lID, _ := auth.GetID(r)
+
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
-		posts, err = h.service.GetAll(ctx)
+		posts, err = h.service.GetAll(ctx, lID)
 
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, response.ErrorNotFound.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -71,6 +101,7 @@ func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
 // GetOneHandler response one post by id.
 func (h *Handler) GetOneHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
+	lID, _ := auth.GetID(r)
 
 	var (
 		p   post.Post
@@ -82,29 +113,45 @@ func (h *Handler) GetOneHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
-		p, err = h.service.GetByID(ctx, id)
+		p, err = h.service.GetByID(ctx, id, lID)
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, response.ErrorNotFound.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", patch.ETag(p.Version))
 	_ = response.JSON(w, http.StatusOK, render.M{"post": p})
 }
 
 // CreateHandler create a new post.
 func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
-	var p post.Post
+	var req post.Request
 
-	err := json.NewDecoder(r.Body).Decode(&p)
+	err := validation.Decode(r.Body, &req)
 	if err != nil {
-		fmt.Println(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorParsingUser.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+	p := req.Post()
+
+	// The author is always the authenticated user.
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+	p.UserID, err = primitive.ObjectIDFromHex(lID)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrInvalidID)
 		return
 	}
 
@@ -113,42 +160,53 @@ func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		err = h.service.Create(ctx, &p)
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, err.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 	_ = response.JSON(w, http.StatusCreated, render.M{"post": p})
 }
 
-// UpdateHandler update a stored post by id.
+// UpdateHandler replace a post, If-Match makes it conditional to the ETag
+// of the post.
 func (h *Handler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
-	var p, updatedPost post.Post
-	err := json.NewDecoder(r.Body).Decode(&p)
+	var (
+		req         post.Request
+		updatedPost post.Post
+	)
+	err := validation.Decode(r.Body, &req)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorParsingUser.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
+	p := req.Post()
 
 	id := chi.URLParam(r, "id")
 
 	lID, err := auth.GetID(r)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
 		return
 	}
 	role, err := auth.GetRole(r)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	version, err := patch.IfMatch(r)
+	if err != nil {
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -157,19 +215,72 @@ func (h *Handler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
-		updatedPost, err = h.service.Update(ctx, id, lID, role, &p)
+		updatedPost, err = h.service.Update(ctx, id, lID, role, &p, version)
 	}
 
+	h.updateResponse(w, r, updatedPost, err)
+}
+
+// PatchHandler apply a JSON merge patch to a post, If-Match makes it
+// conditional to the ETag of the post.
+func (h *Handler) PatchHandler(w http.ResponseWriter, r *http.Request) {
+	var updatedPost post.Post
+
+	p, err := patch.Read(r)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, err.Error())
+		_ = response.Error(w, err)
 		return
 	}
 
-	render.JSON(w, r, render.M{"post": updatedPost})
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+	role, err := auth.GetRole(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	version, err := patch.IfMatch(r)
+	if err != nil {
+		_ = response.Error(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		updatedPost, err = h.service.Patch(ctx, id, lID, role, p, version)
+	}
+
+	h.updateResponse(w, r, updatedPost, err)
+}
+
+// updateResponse response the updated post with its ETag, or the error
+// of the update.
+func (h *Handler) updateResponse(w http.ResponseWriter, r *http.Request, p post.Post, err error) {
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", patch.ETag(p.Version))
+	render.JSON(w, r, render.M{"post": p})
 }
 
 // DeleteHandler Remove a user by ID.
@@ -177,8 +288,8 @@ func (h *Handler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	lID, err := auth.GetID(r)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
 		return
 	}
 
@@ -187,149 +298,453 @@ func (h *Handler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 
 	role, err := auth.GetRole(r)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
 		return
 	}
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		err = h.service.Delete(ctx, id, lID, role)
 	}
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, err.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
 	render.JSON(w, r, render.M{})
 }
 
+// RestoreHandler undo the delete of a post by ID.
+func (h *Handler) RestoreHandler(w http.ResponseWriter, r *http.Request) {
+	var p post.Post
+	id := chi.URLParam(r, "id")
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		p, err = h.service.Restore(ctx, id)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{"post": p})
+}
+
 // AddLikeHandler add like to post.
 func (h *Handler) AddLikeHandler(w http.ResponseWriter, r *http.Request) {
+	h.react(w, r, reaction.Like)
+}
+
+// ReactHandler set the reaction of the user to a post.
+func (h *Handler) ReactHandler(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Type reaction.Type `json:"type"`
+	}
+
+	err := validation.Decode(r.Body, &body)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	h.react(w, r, body.Type)
+}
+
+func (h *Handler) react(w http.ResponseWriter, r *http.Request, t reaction.Type) {
 	var p post.Post
 	id := chi.URLParam(r, "id")
 
 	lID, err := auth.GetID(r)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
 		return
 	}
 
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		p, err = h.service.React(ctx, lID, id, t)
+	}
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
+	render.JSON(w, r, render.M{"post": p})
+}
+
+// UnreactHandler remove the reaction of the user to a post.
+func (h *Handler) UnreactHandler(w http.ResponseWriter, r *http.Request) {
+	var p post.Post
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
 	select {
 	case <-ctx.Done():
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
-		p, err = h.service.AddLike(ctx, lID, id)
+		p, err = h.service.Unreact(ctx, lID, id)
 	}
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, err.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
 	render.JSON(w, r, render.M{"post": p})
 }
 
-// DeleteLikeHandler add like to post.
-func (h *Handler) DeleteLikeHandler(w http.ResponseWriter, r *http.Request) {
+// RepostHandler repost a post, with an optional quote.
+func (h *Handler) RepostHandler(w http.ResponseWriter, r *http.Request) {
 	var p post.Post
+	var body struct {
+		Description string `json:"description"`
+	}
 	id := chi.URLParam(r, "id")
 
 	lID, err := auth.GetID(r)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	err = validation.DecodeOptional(r.Body, &body)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		p, err = h.service.Repost(ctx, lID, id, body.Description)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusCreated, render.M{"post": p})
+}
+
+// UnrepostHandler remove the repost of the user to a post.
+func (h *Handler) UnrepostHandler(w http.ResponseWriter, r *http.Request) {
+	var p post.Post
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
 		return
 	}
 
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
 	select {
 	case <-ctx.Done():
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
-		p, err = h.service.DeleteLike(ctx, lID, id)
+		p, err = h.service.Unrepost(ctx, lID, id)
 	}
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, err.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
 	render.JSON(w, r, render.M{"post": p})
 }
 
+// GetLikesHandler response a page of the users who liked a post.
+func (h *Handler) GetLikesHandler(w http.ResponseWriter, r *http.Request) {
+	h.getReactions(w, r, reaction.Like)
+}
+
+// GetReactionsHandler response a page of the users who reacted to a post,
+// optionally filtered by the reaction type.
+func (h *Handler) GetReactionsHandler(w http.ResponseWriter, r *http.Request) {
+	h.getReactions(w, r, reaction.Type(r.URL.Query().Get("type")))
+}
+
+func (h *Handler) getReactions(w http.ResponseWriter, r *http.Request, t reaction.Type) {
+	var (
+		users []user.User
+		total int
+		err   error
+	)
+	id := chi.URLParam(r, "id")
+	lID, _ := auth.GetID(r)
+
+	page, limit, ok := pagination.GetPagination(r)
+	if !ok {
+		page, limit = 1, defaultReactionsLimit
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		users, total, err = h.service.GetReactions(ctx, id, lID, t, page, limit)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{
+		"users": users,
+		"total": total,
+	})
+}
+
+// GetRevisionsHandler response a page of the edits of a post.
+func (h *Handler) GetRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		revisions []post.Revision
+		total     int
+	)
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+	role, err := auth.GetRole(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	page, limit, ok := pagination.GetPagination(r)
+	if !ok {
+		page, limit = 1, defaultRevisionsLimit
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		revisions, total, err = h.service.GetRevisions(ctx, id, lID, role, page, limit)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, render.M{
+		"revisions": revisions,
+		"total":     total,
+	})
+}
+
+// AddPictureHandler upload a picture to a post.
+func (h *Handler) AddPictureHandler(w http.ResponseWriter, r *http.Request) {
+	var p post.Post
+	id := chi.URLParam(r, "id")
+
+	lID, err := auth.GetID(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+	role, err := auth.GetRole(r)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("picture")
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		if errors.Is(err, security.ErrBodyTooLarge) {
+			_ = response.Error(w, security.ErrBodyTooLarge)
+			return
+		}
+		_ = response.Error(w, ErrInvalidPicture)
+		return
+	}
+	defer file.Close()
+
+	br := bufio.NewReader(file)
+	head, _ := br.Peek(512)
+	ext, ok := pictureExtensions[http.DetectContentType(head)]
+	if !ok {
+		_ = response.Error(w, response.WithKind(response.Unsupported, ErrInvalidPicture))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		p, err = h.service.AddPicture(ctx, id, lID, role, ext, br)
+	}
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusAccepted, render.M{"post": p})
+}
+
 //Routes configure and return routes for users
-func (h *Handler) Routes() http.Handler {
+func (h *Handler) Routes(a *auth.Authenticator) http.Handler {
 	r := chi.NewRouter()
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client, user.Admin, user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
 		Get("/", h.GetAllHandler)
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
 		Post("/", h.CreateHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client, user.Admin, user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
 		Get("/{id}", h.GetOneHandler)
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client, user.Admin, user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
 		Put("/{id}", h.UpdateHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Patch("/{id}", h.PatchHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
 		Post("/{id}/like", h.AddLikeHandler)
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client)).
-		Delete("/{id}/like", h.DeleteLikeHandler)
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Delete("/{id}/like", h.UnreactHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Get("/{id}/likes", h.GetLikesHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Put("/{id}/reaction", h.ReactHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Delete("/{id}/reaction", h.UnreactHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Get("/{id}/reactions", h.GetReactionsHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client, user.Admin, user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Post("/{id}/repost", h.RepostHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
+		Delete("/{id}/repost", h.UnrepostHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		With(security.LimitBody(h.maxPictureBytes)).
+		Post("/{id}/pictures", h.AddPictureHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Get("/{id}/revisions", h.GetRevisionsHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
 		Delete("/{id}", h.DeleteHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client, user.Admin, user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Post("/{id}/restore", h.RestoreHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
 		Put("/{id}", h.UpdateHandler)
 	return r
 
 }
 
 // NewPostHandler create and configure a new Handler.
-func New(coll *mongo.Collection, log logger.Logger) *Handler {
+func New(coll, reactionColl, revisionColl *mongo.Collection, log logger.Logger, timeout time.Duration, storage picture.Storage, queue picture.Queue, limits picture.Limits, badges badge.Service, auditor audit.Recorder) *Handler {
 	return &Handler{
-		log:     log,
-		service: service.New(coll, log),
+		log:             log,
+		maxPictureBytes: limits.MaxBytes,
+		service:         service.New(coll, reactionColl, revisionColl, log, timeout, storage, queue, badges, auditor),
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -12,9 +13,12 @@ import (
 
 	"github.com/Zucke/social_prove/pkg/auth"
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/patch"
 	"github.com/Zucke/social_prove/pkg/post"
 	mock "github.com/Zucke/social_prove/pkg/post/mock"
+	"github.com/Zucke/social_prove/pkg/reaction"
 	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/security"
 	"github.com/Zucke/social_prove/pkg/user"
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
@@ -56,13 +60,27 @@ func TestHandler_GetOneHandler(t *testing.T) {
 			err:   response.ErrorNotFound,
 			times: 1,
 		},
+		{
+			name:  "Failure invalid id",
+			post:  p,
+			code:  http.StatusBadRequest,
+			err:   response.ErrInvalidID,
+			times: 1,
+		},
+		{
+			name:  "Failure internal error",
+			post:  p,
+			code:  http.StatusInternalServerError,
+			err:   response.ErrorInternalServerError,
+			times: 1,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				GetByID(gomock.Any(), id.Hex()).
+				GetByID(gomock.Any(), id.Hex(), "").
 				Return(test.post, test.err).
 				Times(test.times)
 
@@ -111,13 +129,20 @@ func TestHandler_GetAllHandler(t *testing.T) {
 			err:   response.ErrorNotFound,
 			times: 1,
 		},
+		{
+			name:  "Failure internal error",
+			posts: []post.Post{},
+			code:  http.StatusInternalServerError,
+			err:   response.ErrorInternalServerError,
+			times: 1,
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				GetAll(gomock.Any()).
+				GetAll(gomock.Any(), "").
 				Return(test.posts, test.err).
 				Times(test.times)
 
@@ -145,6 +170,7 @@ func TestHandler_Create(t *testing.T) {
 	m := mock.NewMockService(ctrl)
 	l := logger.NewMock()
 
+	userID := primitive.NewObjectID()
 	p := post.Post{
 		Description: "conted, bla bla bla",
 	}
@@ -153,6 +179,7 @@ func TestHandler_Create(t *testing.T) {
 	if err != nil {
 		assert.NotNil(t, err)
 	}
+	p.UserID = userID
 
 	tests := []struct {
 		name  string
@@ -170,6 +197,14 @@ func TestHandler_Create(t *testing.T) {
 			err:   nil,
 			times: 1,
 		},
+		{
+			name:  "Success managed fields ignored",
+			post:  p,
+			body:  strings.NewReader(`{"description":"conted, bla bla bla","repost_of":"5f2b8a0e9d1e8c0001a1b2c3","reactions":{"like":1000},"pictures":[{"url":"http://example.com/forged.png","status":"ready"}],"version":7,"deleted_at":"2020-01-01T00:00:00Z","published_at":"2020-01-01T00:00:00Z"}`),
+			code:  http.StatusCreated,
+			err:   nil,
+			times: 1,
+		},
 		{
 			name:  "Failure bad request ",
 			post:  p,
@@ -196,6 +231,7 @@ func TestHandler_Create(t *testing.T) {
 			w := httptest.NewRecorder()
 
 			r := httptest.NewRequest(http.MethodPost, "/post/", test.body)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, userID))
 
 			mux := chi.NewRouter()
 			mux.Post("/post/", h.CreateHandler)
@@ -250,10 +286,34 @@ func TestHandler_Update(t *testing.T) {
 			times: 0,
 		},
 		{
-			name:  "Failure internal error ",
+			name:  "Success managed fields ignored",
+			post:  p,
+			body:  strings.NewReader(`{"description":"conted, bla bla bla","repost_of":"5f2b8a0e9d1e8c0001a1b2c3","reactions":{"like":1000},"pictures":[{"url":"http://example.com/forged.png","status":"ready"}],"version":7,"deleted_at":"2020-01-01T00:00:00Z","published_at":"2020-01-01T00:00:00Z"}`),
+			code:  http.StatusOK,
+			err:   nil,
+			times: 1,
+		},
+		{
+			name:  "Failure not the author",
+			post:  p,
+			body:  bytes.NewReader(jPost),
+			code:  http.StatusForbidden,
+			err:   response.ErrorUnauthorized,
+			times: 1,
+		},
+		{
+			name:  "Failure not found",
 			post:  p,
 			body:  bytes.NewReader(jPost),
 			code:  http.StatusNotFound,
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+		{
+			name:  "Failure internal error ",
+			post:  p,
+			body:  bytes.NewReader(jPost),
+			code:  http.StatusInternalServerError,
 			err:   response.ErrorInternalServerError,
 			times: 1,
 		},
@@ -263,7 +323,7 @@ func TestHandler_Update(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				Update(gomock.Any(), id1.Hex(), id2.Hex(), role, &test.post).
+				Update(gomock.Any(), id1.Hex(), id2.Hex(), role, &test.post, patch.AnyVersion).
 				Return(test.post, test.err).
 				Times(test.times)
 
@@ -310,8 +370,8 @@ func TestHandler_Delete(t *testing.T) {
 			times: 1,
 		},
 		{
-			name:  "Failure internal error",
-			code:  http.StatusNotFound,
+			name:  "Failure bad request",
+			code:  http.StatusBadRequest,
 			err:   response.ErrorBadRequest,
 			times: 1,
 		},
@@ -345,6 +405,57 @@ func TestHandler_Delete(t *testing.T) {
 	}
 }
 
+func TestHandler_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name string
+		code int
+		err  error
+	}{
+		{
+			name: "Success",
+			code: http.StatusOK,
+			err:  nil,
+		},
+		{
+			name: "Failure not deleted",
+			code: http.StatusNotFound,
+			err:  response.ErrorNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Restore(gomock.Any(), id.Hex()).
+				Return(post.Post{ID: id}, test.err).
+				Times(1)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/post/"+id.Hex()+"/restore", nil)
+
+			mux := chi.NewRouter()
+			mux.Post("/post/{id}/restore", h.RestoreHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
 func TestHandler_AddLike(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -375,7 +486,7 @@ func TestHandler_AddLike(t *testing.T) {
 		{
 			name:  "Failure internal error ",
 			post:  p,
-			code:  http.StatusNotFound,
+			code:  http.StatusInternalServerError,
 			err:   response.ErrorInternalServerError,
 			times: 1,
 		},
@@ -385,7 +496,7 @@ func TestHandler_AddLike(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				AddLike(gomock.Any(), id2.Hex(), id1.Hex()).
+				React(gomock.Any(), id2.Hex(), id1.Hex(), reaction.Like).
 				Return(test.post, test.err).
 				Times(test.times)
 
@@ -438,7 +549,7 @@ func TestHandler_DeleteLike(t *testing.T) {
 		{
 			name:  "Failure internal error ",
 			post:  p,
-			code:  http.StatusNotFound,
+			code:  http.StatusInternalServerError,
 			err:   response.ErrorInternalServerError,
 			times: 1,
 		},
@@ -448,7 +559,7 @@ func TestHandler_DeleteLike(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				DeleteLike(gomock.Any(), id2.Hex(), id1.Hex()).
+				Unreact(gomock.Any(), id2.Hex(), id1.Hex()).
 				Return(test.post, test.err).
 				Times(test.times)
 
@@ -463,7 +574,419 @@ func TestHandler_DeleteLike(t *testing.T) {
 			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id2))
 
 			mux := chi.NewRouter()
-			mux.Delete("/post/{id}/like", h.DeleteLikeHandler)
+			mux.Delete("/post/{id}/like", h.UnreactHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
+func TestHandler_React(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+
+	p := post.Post{
+		Description: "conted, bla bla bla",
+		MyReaction:  reaction.Love,
+	}
+
+	tests := []struct {
+		name     string
+		body     io.Reader
+		reaction reaction.Type
+		code     int
+		err      error
+		times    int
+	}{
+		{
+			name:     "Success",
+			body:     strings.NewReader(`{"type":"love"}`),
+			reaction: reaction.Love,
+			code:     http.StatusOK,
+			err:      nil,
+			times:    1,
+		},
+		{
+			name:  "Failure bad request",
+			body:  strings.NewReader(``),
+			code:  http.StatusBadRequest,
+			err:   nil,
+			times: 0,
+		},
+		{
+			name:     "Failure invalid type",
+			body:     strings.NewReader(`{"type":"meh"}`),
+			reaction: reaction.Type("meh"),
+			code:     http.StatusBadRequest,
+			err:      reaction.ErrInvalidType,
+			times:    1,
+		},
+		{
+			name:     "Failure not found",
+			body:     strings.NewReader(`{"type":"love"}`),
+			reaction: reaction.Love,
+			code:     http.StatusNotFound,
+			err:      response.ErrorNotFound,
+			times:    1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				React(gomock.Any(), id2.Hex(), id1.Hex(), test.reaction).
+				Return(p, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPut, "/post/"+id1.Hex()+"/reaction", test.body)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			mux.Put("/post/{id}/reaction", h.ReactHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
+func TestHandler_Repost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+
+	p := post.Post{
+		ID:       primitive.NewObjectID(),
+		RepostOf: id1,
+	}
+
+	tests := []struct {
+		name  string
+		body  io.Reader
+		quote string
+		code  int
+		err   error
+		times int
+	}{
+		{
+			name:  "Success",
+			body:  strings.NewReader(``),
+			code:  http.StatusCreated,
+			err:   nil,
+			times: 1,
+		},
+		{
+			name:  "Success quote",
+			body:  strings.NewReader(`{"description":"look at this"}`),
+			quote: "look at this",
+			code:  http.StatusCreated,
+			err:   nil,
+			times: 1,
+		},
+		{
+			name:  "Failure bad request",
+			body:  strings.NewReader(`{"description":`),
+			code:  http.StatusBadRequest,
+			err:   nil,
+			times: 0,
+		},
+		{
+			name:  "Failure already reposted",
+			body:  strings.NewReader(``),
+			code:  http.StatusConflict,
+			err:   post.ErrAlreadyReposted,
+			times: 1,
+		},
+		{
+			name:  "Failure not found",
+			body:  strings.NewReader(``),
+			code:  http.StatusNotFound,
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Repost(gomock.Any(), id2.Hex(), id1.Hex(), test.quote).
+				Return(p, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/post/"+id1.Hex()+"/repost", test.body)
+			r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			mux.Post("/post/{id}/repost", h.RepostHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetLikes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name  string
+		query string
+		page  int
+		limit int
+		code  int
+		err   error
+	}{
+		{
+			name:  "Success",
+			query: "?page=2&limit=5",
+			page:  2,
+			limit: 5,
+			code:  http.StatusOK,
+			err:   nil,
+		},
+		{
+			name:  "Success default pagination",
+			query: "",
+			page:  1,
+			limit: defaultReactionsLimit,
+			code:  http.StatusOK,
+			err:   nil,
+		},
+		{
+			name:  "Failure invalid id",
+			query: "",
+			page:  1,
+			limit: defaultReactionsLimit,
+			code:  http.StatusBadRequest,
+			err:   response.ErrInvalidID,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetReactions(gomock.Any(), id.Hex(), gomock.Any(), reaction.Like, test.page, test.limit).
+				Return([]user.User{}, 0, test.err).
+				Times(1)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/post/"+id.Hex()+"/likes"+test.query, nil)
+
+			mux := chi.NewRouter()
+			mux.Get("/post/{id}/likes", h.GetLikesHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+	role := user.Client
+
+	tests := []struct {
+		name  string
+		query string
+		page  int
+		limit int
+		code  int
+		err   error
+	}{
+		{
+			name:  "Success",
+			query: "?page=2&limit=5",
+			page:  2,
+			limit: 5,
+			code:  http.StatusOK,
+			err:   nil,
+		},
+		{
+			name:  "Success default pagination",
+			query: "",
+			page:  1,
+			limit: defaultRevisionsLimit,
+			code:  http.StatusOK,
+			err:   nil,
+		},
+		{
+			name:  "Failure not the author",
+			query: "",
+			page:  1,
+			limit: defaultRevisionsLimit,
+			code:  http.StatusForbidden,
+			err:   response.ErrorUnauthorized,
+		},
+		{
+			name:  "Failure invalid id",
+			query: "",
+			page:  1,
+			limit: defaultRevisionsLimit,
+			code:  http.StatusBadRequest,
+			err:   response.ErrInvalidID,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetRevisions(gomock.Any(), id1.Hex(), id2.Hex(), role, test.page, test.limit).
+				Return([]post.Revision{}, 0, test.err).
+				Times(1)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodGet, "/post/"+id1.Hex()+"/revisions"+test.query, nil)
+			ctx := context.WithValue(r.Context(), auth.RoleKey, role)
+			r = r.WithContext(context.WithValue(ctx, auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			mux.Get("/post/{id}/revisions", h.GetRevisionsHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
+func TestHandler_AddPicture(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+	role := user.Client
+
+	p := post.Post{
+		Description: "conted, bla bla bla",
+	}
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+	tests := []struct {
+		name    string
+		content []byte
+		limit   int64
+		code    int
+		err     error
+		times   int
+	}{
+		{
+			name:    "Success",
+			content: png,
+			code:    http.StatusAccepted,
+			err:     nil,
+			times:   1,
+		},
+		{
+			name:    "Failure too large",
+			content: bytes.Repeat(png, 64),
+			limit:   256,
+			code:    http.StatusRequestEntityTooLarge,
+			err:     nil,
+			times:   0,
+		},
+		{
+			name:    "Failure unsupported media type",
+			content: []byte("plain text"),
+			code:    http.StatusUnsupportedMediaType,
+			err:     nil,
+			times:   0,
+		},
+		{
+			name:    "Failure internal error",
+			content: png,
+			code:    http.StatusInternalServerError,
+			err:     response.ErrorInternalServerError,
+			times:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				AddPicture(gomock.Any(), id1.Hex(), id2.Hex(), role, ".png", gomock.Any()).
+				Return(p, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			body := &bytes.Buffer{}
+			mw := multipart.NewWriter(body)
+			fw, err := mw.CreateFormFile("picture", "picture.png")
+			assert.NoError(t, err)
+			_, err = fw.Write(test.content)
+			assert.NoError(t, err)
+			assert.NoError(t, mw.Close())
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/post/"+id1.Hex()+"/pictures", body)
+			r.Header.Set("Content-Type", mw.FormDataContentType())
+			ctx := context.WithValue(r.Context(), auth.RoleKey, role)
+			r = r.WithContext(context.WithValue(ctx, auth.IDKey, id2))
+
+			mux := chi.NewRouter()
+			if test.limit > 0 {
+				mux.Use(security.LimitBody(test.limit))
+			}
+			mux.Post("/post/{id}/pictures", h.AddPictureHandler)
 			mux.ServeHTTP(w, r)
 
 			assert.Equal(t, test.code, w.Code)
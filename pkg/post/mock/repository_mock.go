@@ -6,10 +6,13 @@ package mock_post
 
 import (
 	context "context"
+	picture "github.com/Zucke/social_prove/pkg/picture"
 	post "github.com/Zucke/social_prove/pkg/post"
+	reaction "github.com/Zucke/social_prove/pkg/reaction"
 	gomock "github.com/golang/mock/gomock"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 	reflect "reflect"
+	time "time"
 )
 
 // MockRepository is a mock of Repository interface
@@ -35,18 +38,18 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
-// AddLike mocks base method
-func (m *MockRepository) AddLike(arg0 context.Context, arg1, arg2 primitive.ObjectID) error {
+// AddPicture mocks base method
+func (m *MockRepository) AddPicture(arg0 context.Context, arg1 primitive.ObjectID, arg2 picture.Picture) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddLike", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AddPicture", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddLike indicates an expected call of AddLike
-func (mr *MockRepositoryMockRecorder) AddLike(arg0, arg1, arg2 interface{}) *gomock.Call {
+// AddPicture indicates an expected call of AddPicture
+func (mr *MockRepositoryMockRecorder) AddPicture(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLike", reflect.TypeOf((*MockRepository)(nil).AddLike), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPicture", reflect.TypeOf((*MockRepository)(nil).AddPicture), arg0, arg1, arg2)
 }
 
 // Create mocks base method
@@ -64,61 +67,76 @@ func (mr *MockRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Cal
 }
 
 // Delete mocks base method
-func (m *MockRepository) Delete(arg0 context.Context, arg1 primitive.ObjectID) error {
+func (m *MockRepository) Delete(arg0 context.Context, arg1 primitive.ObjectID, arg2 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete
-func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0, arg1, arg2)
 }
 
-// DeleteLike mocks base method
-func (m *MockRepository) DeleteLike(arg0 context.Context, arg1, arg2 primitive.ObjectID) error {
+// DeleteAllForUser mocks base method
+func (m *MockRepository) DeleteAllForUser(arg0 context.Context, arg1 primitive.ObjectID, arg2 time.Time) ([]primitive.ObjectID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLike", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "DeleteAllForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAllForUser indicates an expected call of DeleteAllForUser
+func (mr *MockRepositoryMockRecorder) DeleteAllForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllForUser", reflect.TypeOf((*MockRepository)(nil).DeleteAllForUser), arg0, arg1, arg2)
+}
+
+// DeleteReposts mocks base method
+func (m *MockRepository) DeleteReposts(arg0 context.Context, arg1 primitive.ObjectID, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReposts", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteLike indicates an expected call of DeleteLike
-func (mr *MockRepositoryMockRecorder) DeleteLike(arg0, arg1, arg2 interface{}) *gomock.Call {
+// DeleteReposts indicates an expected call of DeleteReposts
+func (mr *MockRepositoryMockRecorder) DeleteReposts(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLike", reflect.TypeOf((*MockRepository)(nil).DeleteLike), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReposts", reflect.TypeOf((*MockRepository)(nil).DeleteReposts), arg0, arg1, arg2)
 }
 
 // GetAll mocks base method
-func (m *MockRepository) GetAll(arg0 context.Context) ([]post.Post, error) {
+func (m *MockRepository) GetAll(arg0 context.Context, arg1 primitive.ObjectID) ([]post.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", arg0)
+	ret := m.ctrl.Call(m, "GetAll", arg0, arg1)
 	ret0, _ := ret[0].([]post.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll
-func (mr *MockRepositoryMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetAll(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRepository)(nil).GetAll), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRepository)(nil).GetAll), arg0, arg1)
 }
 
 // GetAllForUser mocks base method
-func (m *MockRepository) GetAllForUser(arg0 context.Context, arg1 primitive.ObjectID) ([]post.Post, error) {
+func (m *MockRepository) GetAllForUser(arg0 context.Context, arg1, arg2 primitive.ObjectID) ([]post.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllForUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetAllForUser", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]post.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllForUser indicates an expected call of GetAllForUser
-func (mr *MockRepositoryMockRecorder) GetAllForUser(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetAllForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllForUser", reflect.TypeOf((*MockRepository)(nil).GetAllForUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllForUser", reflect.TypeOf((*MockRepository)(nil).GetAllForUser), arg0, arg1, arg2)
 }
 
 // GetByID mocks base method
@@ -136,16 +154,207 @@ func (mr *MockRepositoryMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepository)(nil).GetByID), arg0, arg1)
 }
 
+// GetByIDs mocks base method
+func (m *MockRepository) GetByIDs(arg0 context.Context, arg1 []primitive.ObjectID, arg2 primitive.ObjectID) ([]post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDs indicates an expected call of GetByIDs
+func (mr *MockRepositoryMockRecorder) GetByIDs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockRepository)(nil).GetByIDs), arg0, arg1, arg2)
+}
+
+// GetIDsForUser mocks base method
+func (m *MockRepository) GetIDsForUser(arg0 context.Context, arg1 primitive.ObjectID) ([]primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIDsForUser", arg0, arg1)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIDsForUser indicates an expected call of GetIDsForUser
+func (mr *MockRepositoryMockRecorder) GetIDsForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIDsForUser", reflect.TypeOf((*MockRepository)(nil).GetIDsForUser), arg0, arg1)
+}
+
+// GetPurgeable mocks base method
+func (m *MockRepository) GetPurgeable(arg0 context.Context, arg1 time.Time) ([]primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurgeable", arg0, arg1)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurgeable indicates an expected call of GetPurgeable
+func (mr *MockRepositoryMockRecorder) GetPurgeable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurgeable", reflect.TypeOf((*MockRepository)(nil).GetPurgeable), arg0, arg1)
+}
+
+// GetRepost mocks base method
+func (m *MockRepository) GetRepost(arg0 context.Context, arg1, arg2 primitive.ObjectID) (post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepost", arg0, arg1, arg2)
+	ret0, _ := ret[0].(post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRepost indicates an expected call of GetRepost
+func (mr *MockRepositoryMockRecorder) GetRepost(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepost", reflect.TypeOf((*MockRepository)(nil).GetRepost), arg0, arg1, arg2)
+}
+
+// GetWithPendingPictures mocks base method
+func (m *MockRepository) GetWithPendingPictures(arg0 context.Context) ([]post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWithPendingPictures", arg0)
+	ret0, _ := ret[0].([]post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWithPendingPictures indicates an expected call of GetWithPendingPictures
+func (mr *MockRepositoryMockRecorder) GetWithPendingPictures(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWithPendingPictures", reflect.TypeOf((*MockRepository)(nil).GetWithPendingPictures), arg0)
+}
+
+// IncReactions mocks base method
+func (m *MockRepository) IncReactions(arg0 context.Context, arg1 primitive.ObjectID, arg2 map[reaction.Type]int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncReactions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncReactions indicates an expected call of IncReactions
+func (mr *MockRepositoryMockRecorder) IncReactions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncReactions", reflect.TypeOf((*MockRepository)(nil).IncReactions), arg0, arg1, arg2)
+}
+
+// IncReposts mocks base method
+func (m *MockRepository) IncReposts(arg0 context.Context, arg1 primitive.ObjectID, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncReposts", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncReposts indicates an expected call of IncReposts
+func (mr *MockRepositoryMockRecorder) IncReposts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncReposts", reflect.TypeOf((*MockRepository)(nil).IncReposts), arg0, arg1, arg2)
+}
+
+// PublishDue mocks base method
+func (m *MockRepository) PublishDue(arg0 context.Context, arg1 time.Time) ([]primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDue", arg0, arg1)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishDue indicates an expected call of PublishDue
+func (mr *MockRepositoryMockRecorder) PublishDue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDue", reflect.TypeOf((*MockRepository)(nil).PublishDue), arg0, arg1)
+}
+
+// Purge mocks base method
+func (m *MockRepository) Purge(arg0 context.Context, arg1 primitive.ObjectID) (post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge
+func (mr *MockRepositoryMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRepository)(nil).Purge), arg0, arg1)
+}
+
+// Restore mocks base method
+func (m *MockRepository) Restore(arg0 context.Context, arg1 primitive.ObjectID) (post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockRepositoryMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), arg0, arg1)
+}
+
+// RestoreAllForUser mocks base method
+func (m *MockRepository) RestoreAllForUser(arg0 context.Context, arg1 primitive.ObjectID, arg2 time.Time) ([]primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAllForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreAllForUser indicates an expected call of RestoreAllForUser
+func (mr *MockRepositoryMockRecorder) RestoreAllForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAllForUser", reflect.TypeOf((*MockRepository)(nil).RestoreAllForUser), arg0, arg1, arg2)
+}
+
+// RestoreReposts mocks base method
+func (m *MockRepository) RestoreReposts(arg0 context.Context, arg1 primitive.ObjectID, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreReposts", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreReposts indicates an expected call of RestoreReposts
+func (mr *MockRepositoryMockRecorder) RestoreReposts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreReposts", reflect.TypeOf((*MockRepository)(nil).RestoreReposts), arg0, arg1, arg2)
+}
+
 // Update mocks base method
-func (m *MockRepository) Update(arg0 context.Context, arg1 primitive.ObjectID, arg2 *post.Post) error {
+func (m *MockRepository) Update(arg0 context.Context, arg1 primitive.ObjectID, arg2 *post.Post, arg3 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update
-func (mr *MockRepositoryMockRecorder) Update(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), arg0, arg1, arg2, arg3)
+}
+
+// UpdatePicture mocks base method
+func (m *MockRepository) UpdatePicture(arg0 context.Context, arg1 primitive.ObjectID, arg2 picture.Picture) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePicture", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePicture indicates an expected call of UpdatePicture
+func (mr *MockRepositoryMockRecorder) UpdatePicture(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePicture", reflect.TypeOf((*MockRepository)(nil).UpdatePicture), arg0, arg1, arg2)
 }
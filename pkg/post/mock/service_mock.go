@@ -7,8 +7,10 @@ package mock_post
 import (
 	context "context"
 	post "github.com/Zucke/social_prove/pkg/post"
+	reaction "github.com/Zucke/social_prove/pkg/reaction"
 	user "github.com/Zucke/social_prove/pkg/user"
 	gomock "github.com/golang/mock/gomock"
+	io "io"
 	reflect "reflect"
 )
 
@@ -35,19 +37,19 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
-// AddLike mocks base method
-func (m *MockService) AddLike(arg0 context.Context, arg1, arg2 string) (post.Post, error) {
+// AddPicture mocks base method
+func (m *MockService) AddPicture(arg0 context.Context, arg1, arg2 string, arg3 user.Role, arg4 string, arg5 io.Reader) (post.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddLike", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "AddPicture", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(post.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// AddLike indicates an expected call of AddLike
-func (mr *MockServiceMockRecorder) AddLike(arg0, arg1, arg2 interface{}) *gomock.Call {
+// AddPicture indicates an expected call of AddPicture
+func (mr *MockServiceMockRecorder) AddPicture(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLike", reflect.TypeOf((*MockService)(nil).AddLike), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPicture", reflect.TypeOf((*MockService)(nil).AddPicture), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // Create mocks base method
@@ -78,79 +80,186 @@ func (mr *MockServiceMockRecorder) Delete(arg0, arg1, arg2, arg3 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), arg0, arg1, arg2, arg3)
 }
 
-// DeleteLike mocks base method
-func (m *MockService) DeleteLike(arg0 context.Context, arg1, arg2 string) (post.Post, error) {
+// GetAll mocks base method
+func (m *MockService) GetAll(arg0 context.Context, arg1 string) ([]post.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteLike", arg0, arg1, arg2)
-	ret0, _ := ret[0].(post.Post)
+	ret := m.ctrl.Call(m, "GetAll", arg0, arg1)
+	ret0, _ := ret[0].([]post.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// DeleteLike indicates an expected call of DeleteLike
-func (mr *MockServiceMockRecorder) DeleteLike(arg0, arg1, arg2 interface{}) *gomock.Call {
+// GetAll indicates an expected call of GetAll
+func (mr *MockServiceMockRecorder) GetAll(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLike", reflect.TypeOf((*MockService)(nil).DeleteLike), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockService)(nil).GetAll), arg0, arg1)
 }
 
-// GetAll mocks base method
-func (m *MockService) GetAll(arg0 context.Context) ([]post.Post, error) {
+// GetAllForUser mocks base method
+func (m *MockService) GetAllForUser(arg0 context.Context, arg1, arg2 string) ([]post.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", arg0)
+	ret := m.ctrl.Call(m, "GetAllForUser", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]post.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll
-func (mr *MockServiceMockRecorder) GetAll(arg0 interface{}) *gomock.Call {
+// GetAllForUser indicates an expected call of GetAllForUser
+func (mr *MockServiceMockRecorder) GetAllForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockService)(nil).GetAll), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllForUser", reflect.TypeOf((*MockService)(nil).GetAllForUser), arg0, arg1, arg2)
 }
 
-// GetAllForUser mocks base method
-func (m *MockService) GetAllForUser(arg0 context.Context, arg1 string) ([]post.Post, error) {
+// GetByID mocks base method
+func (m *MockService) GetByID(arg0 context.Context, arg1, arg2 string) (post.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllForUser", arg0, arg1)
-	ret0, _ := ret[0].([]post.Post)
+	ret := m.ctrl.Call(m, "GetByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(post.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllForUser indicates an expected call of GetAllForUser
-func (mr *MockServiceMockRecorder) GetAllForUser(arg0, arg1 interface{}) *gomock.Call {
+// GetByID indicates an expected call of GetByID
+func (mr *MockServiceMockRecorder) GetByID(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllForUser", reflect.TypeOf((*MockService)(nil).GetAllForUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), arg0, arg1, arg2)
 }
 
-// GetByID mocks base method
-func (m *MockService) GetByID(arg0 context.Context, arg1 string) (post.Post, error) {
+// GetReactions mocks base method
+func (m *MockService) GetReactions(arg0 context.Context, arg1, arg2 string, arg3 reaction.Type, arg4, arg5 int) ([]user.User, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReactions", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]user.User)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetReactions indicates an expected call of GetReactions
+func (mr *MockServiceMockRecorder) GetReactions(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReactions", reflect.TypeOf((*MockService)(nil).GetReactions), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// GetRevisions mocks base method
+func (m *MockService) GetRevisions(arg0 context.Context, arg1, arg2 string, arg3 user.Role, arg4, arg5 int) ([]post.Revision, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]post.Revision)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetRevisions indicates an expected call of GetRevisions
+func (mr *MockServiceMockRecorder) GetRevisions(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockService)(nil).GetRevisions), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// Patch mocks base method
+func (m *MockService) Patch(arg0 context.Context, arg1, arg2 string, arg3 user.Role, arg4 []byte, arg5 int64) (post.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", arg0, arg1)
+	ret := m.ctrl.Call(m, "Patch", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(post.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetByID indicates an expected call of GetByID
-func (mr *MockServiceMockRecorder) GetByID(arg0, arg1 interface{}) *gomock.Call {
+// Patch indicates an expected call of Patch
+func (mr *MockServiceMockRecorder) Patch(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockService)(nil).Patch), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// React mocks base method
+func (m *MockService) React(arg0 context.Context, arg1, arg2 string, arg3 reaction.Type) (post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "React", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// React indicates an expected call of React
+func (mr *MockServiceMockRecorder) React(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "React", reflect.TypeOf((*MockService)(nil).React), arg0, arg1, arg2, arg3)
+}
+
+// Repost mocks base method
+func (m *MockService) Repost(arg0 context.Context, arg1, arg2, arg3 string) (post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Repost", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Repost indicates an expected call of Repost
+func (mr *MockServiceMockRecorder) Repost(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repost", reflect.TypeOf((*MockService)(nil).Repost), arg0, arg1, arg2, arg3)
+}
+
+// Restore mocks base method
+func (m *MockService) Restore(arg0 context.Context, arg1 string) (post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1)
+	ret0, _ := ret[0].(post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockServiceMockRecorder) Restore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), arg0, arg1)
+}
+
+// Unreact mocks base method
+func (m *MockService) Unreact(arg0 context.Context, arg1, arg2 string) (post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unreact", arg0, arg1, arg2)
+	ret0, _ := ret[0].(post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unreact indicates an expected call of Unreact
+func (mr *MockServiceMockRecorder) Unreact(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unreact", reflect.TypeOf((*MockService)(nil).Unreact), arg0, arg1, arg2)
+}
+
+// Unrepost mocks base method
+func (m *MockService) Unrepost(arg0 context.Context, arg1, arg2 string) (post.Post, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unrepost", arg0, arg1, arg2)
+	ret0, _ := ret[0].(post.Post)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unrepost indicates an expected call of Unrepost
+func (mr *MockServiceMockRecorder) Unrepost(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unrepost", reflect.TypeOf((*MockService)(nil).Unrepost), arg0, arg1, arg2)
 }
 
 // Update mocks base method
-func (m *MockService) Update(arg0 context.Context, arg1, arg2 string, arg3 user.Role, arg4 *post.Post) (post.Post, error) {
+func (m *MockService) Update(arg0 context.Context, arg1, arg2 string, arg3 user.Role, arg4 *post.Post, arg5 int64) (post.Post, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(post.Post)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockServiceMockRecorder) Update(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // WithPagination mocks base method
@@ -1,21 +1,142 @@
 package post
 
 import (
-	"os/user"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/badge"
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+	"github.com/Zucke/social_prove/pkg/validation"
 )
 
-//Post is the post model
+// MaxDescriptionLength is the limit of the description of a post.
+const MaxDescriptionLength = 2000
+
+// Post is the post model, a post with RepostOf is a repost of another
+// post or a quote-post when it has its own description.
 type Post struct {
-	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	UserID      primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
-	User        *user.User         `json:"user,omitempty" bson:"user,omitempty"`
-	Description string             `json:"description,omitempty" bson:"description,omitempty"`
-	Badge       string             `json:"badge,omitempty" bson:"badge,omitempty"`
-	Pictures    []string           `json:"pictures,omitempty" bson:"pictures,omitempty"`
-	Likes       []string           `json:"likes,omitempty" bson:"likes,omitempty"`
-	CreatedAt   time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
-	UpdatedAt   time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	ID                  primitive.ObjectID      `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID              primitive.ObjectID      `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	User                *user.User              `json:"user,omitempty" bson:"user,omitempty"`
+	Description         string                  `json:"description,omitempty" bson:"description,omitempty"`
+	BadgeID             primitive.ObjectID      `json:"badge_id,omitempty" bson:"badge_id,omitempty"`
+	Badge               *badge.Badge            `json:"badge,omitempty" bson:"badge,omitempty"`
+	Pictures            []picture.Picture       `json:"pictures,omitempty" bson:"pictures,omitempty"`
+	Reactions           map[reaction.Type]int64 `json:"reactions,omitempty" bson:"reactions,omitempty"`
+	MyReaction          reaction.Type           `json:"my_reaction,omitempty" bson:"-"`
+	RepostOf            primitive.ObjectID      `json:"repost_of,omitempty" bson:"repost_of,omitempty"`
+	Original            *Post                   `json:"original,omitempty" bson:"original,omitempty"`
+	OriginalUnavailable bool                    `json:"original_unavailable,omitempty" bson:"-"`
+	RepostsCount        int64                   `json:"reposts_count" bson:"reposts_count"`
+	Status              Status                  `json:"status,omitempty" bson:"status,omitempty"`
+	PublishAt           *time.Time              `json:"publish_at,omitempty" bson:"publish_at,omitempty"`
+	PublishedAt         *time.Time              `json:"published_at,omitempty" bson:"published_at,omitempty"`
+	EditedAt            *time.Time              `json:"edited_at,omitempty" bson:"edited_at,omitempty"`
+	DeletedAt           *time.Time              `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	CreatedAt           time.Time               `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt           time.Time               `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	Version             int64                   `json:"version,omitempty" bson:"version,omitempty"`
+}
+
+// Request is the body of the requests that create or replace a post, the
+// other fields, like the pictures or the original of a repost, are managed
+// by the API.
+type Request struct {
+	Description string             `json:"description"`
+	BadgeID     primitive.ObjectID `json:"badge_id"`
+	Status      Status             `json:"status"`
+	PublishAt   *time.Time         `json:"publish_at"`
+}
+
+// Post returns the post of the request.
+func (r Request) Post() Post {
+	return Post{
+		Description: r.Description,
+		BadgeID:     r.BadgeID,
+		Status:      r.Status,
+		PublishAt:   r.PublishAt,
+	}
+}
+
+// Editable are the JSON names of the fields a patch can change.
+var Editable = []string{"description", "badge_id", "status", "publish_at"}
+
+// Validate check the fields of a post sent by a user.
+func (p Post) Validate() error {
+	var v validation.Validator
+	v.Length("description", p.Description, 0, MaxDescriptionLength)
+
+	return v.Err()
+}
+
+// IsRepost reports whether the post is a repost without quote.
+func (p Post) IsRepost() bool {
+	return !p.RepostOf.IsZero() && p.Description == "" && len(p.Pictures) == 0
+}
+
+// Errors.
+var (
+	ErrAlreadyReposted = response.NewError(response.Conflict, "post already reposted")
+	ErrInvalidStatus   = response.NewError(response.Invalid, "invalid post status")
+	ErrInvalidSchedule = response.NewError(response.Invalid, "invalid post schedule")
+)
+
+// Status of the publication of a post.
+type Status string
+
+// Post statuses, only the author sees the posts not published.
+const (
+	Draft     Status = "draft"
+	Scheduled Status = "scheduled"
+	Published Status = "published"
+)
+
+// IsPublished reports whether the post is visible to every user.
+func (p Post) IsPublished() bool {
+	return p.Status == "" || p.Status == Published
+}
+
+// VisibleTo reports whether the viewer can see the post.
+func (p Post) VisibleTo(viewerID primitive.ObjectID) bool {
+	return p.IsPublished() || (!viewerID.IsZero() && p.UserID == viewerID)
+}
+
+// Schedule check the status and publish_at of the post and resolve its
+// status at now. Without status the post is published, or scheduled when
+// publish_at is in the future.
+func (p *Post) Schedule(now time.Time) error {
+	switch p.Status {
+	case "":
+		p.Status = Published
+		if p.PublishAt != nil && p.PublishAt.After(now) {
+			p.Status = Scheduled
+		}
+	case Draft, Published:
+	case Scheduled:
+		if p.PublishAt == nil {
+			return ErrInvalidSchedule
+		}
+		if !p.PublishAt.After(now) {
+			p.Status = Published
+		}
+	default:
+		return ErrInvalidStatus
+	}
+
+	switch p.Status {
+	case Published:
+		p.PublishAt = nil
+		p.PublishedAt = &now
+	case Draft:
+		p.PublishAt = nil
+		p.PublishedAt = nil
+	case Scheduled:
+		p.PublishedAt = nil
+	}
+
+	return nil
 }
@@ -0,0 +1,100 @@
+package post
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/picture"
+)
+
+func TestPost_Schedule(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		post      Post
+		status    Status
+		publishAt *time.Time
+		err       error
+	}{
+		{
+			name:   "publish without status",
+			post:   Post{},
+			status: Published,
+		},
+		{
+			name:      "schedule without status",
+			post:      Post{PublishAt: &future},
+			status:    Scheduled,
+			publishAt: &future,
+		},
+		{
+			name:   "publish past schedule",
+			post:   Post{Status: Scheduled, PublishAt: &past},
+			status: Published,
+		},
+		{
+			name:   "draft ignores publish_at",
+			post:   Post{Status: Draft, PublishAt: &future},
+			status: Draft,
+		},
+		{
+			name: "scheduled without publish_at",
+			post: Post{Status: Scheduled},
+			err:  ErrInvalidSchedule,
+		},
+		{
+			name: "unknown status",
+			post: Post{Status: Status("hidden")},
+			err:  ErrInvalidStatus,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.post.Schedule(now)
+			assert.Equal(t, test.err, err)
+			if test.err != nil {
+				return
+			}
+
+			assert.Equal(t, test.status, test.post.Status)
+			assert.Equal(t, test.publishAt, test.post.PublishAt)
+			assert.Equal(t, test.status == Published, test.post.PublishedAt != nil)
+		})
+	}
+}
+
+func TestPost_VisibleTo(t *testing.T) {
+	author := primitive.NewObjectID()
+	other := primitive.NewObjectID()
+
+	assert.True(t, Post{UserID: author}.VisibleTo(other))
+	assert.True(t, Post{UserID: author, Status: Published}.VisibleTo(primitive.NilObjectID))
+	assert.True(t, Post{UserID: author, Status: Draft}.VisibleTo(author))
+	assert.False(t, Post{UserID: author, Status: Draft}.VisibleTo(other))
+	assert.False(t, Post{UserID: author, Status: Scheduled}.VisibleTo(primitive.NilObjectID))
+}
+
+func TestDiff(t *testing.T) {
+	badgeID := primitive.NewObjectID()
+	before := Post{Description: "first", Pictures: []picture.Picture{}}
+
+	changes := Diff(before, Post{Description: "first"})
+	assert.Empty(t, changes)
+
+	changes = Diff(before, Post{Description: "second", BadgeID: badgeID})
+	assert.Equal(t, map[string]Change{
+		"description": {From: "first", To: "second"},
+		"badge_id":    {From: nil, To: badgeID},
+	}, changes)
+
+	pics := []picture.Picture{{URL: "http://localhost/a.jpg"}}
+	changes = Diff(before, Post{Description: "first", Pictures: pics})
+	assert.Empty(t, changes)
+}
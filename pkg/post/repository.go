@@ -2,18 +2,44 @@ package post
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/picture"
+	"github.com/Zucke/social_prove/pkg/reaction"
 )
 
 //Repository the post repository
 type Repository interface {
-	GetAll(ctx context.Context) ([]Post, error)
-	GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]Post, error)
+	GetAll(ctx context.Context, viewerID primitive.ObjectID) ([]Post, error)
+	GetAllForUser(ctx context.Context, userID, viewerID primitive.ObjectID) ([]Post, error)
+	GetByIDs(ctx context.Context, ids []primitive.ObjectID, viewerID primitive.ObjectID) ([]Post, error)
 	Create(ctx context.Context, p *Post) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (Post, error)
-	Update(ctx context.Context, id primitive.ObjectID, p *Post) error
-	Delete(ctx context.Context, id primitive.ObjectID) error
-	AddLike(ctx context.Context, fanID, postID primitive.ObjectID) error
-	DeleteLike(ctx context.Context, fanID, postID primitive.ObjectID) error
+	Update(ctx context.Context, id primitive.ObjectID, p *Post, version int64) error
+	Delete(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	DeleteAllForUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]primitive.ObjectID, error)
+	Restore(ctx context.Context, id primitive.ObjectID) (Post, error)
+	RestoreAllForUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]primitive.ObjectID, error)
+	GetPurgeable(ctx context.Context, before time.Time) ([]primitive.ObjectID, error)
+	GetIDsForUser(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error)
+	Purge(ctx context.Context, id primitive.ObjectID) (Post, error)
+	PublishDue(ctx context.Context, now time.Time) ([]primitive.ObjectID, error)
+	IncReactions(ctx context.Context, postID primitive.ObjectID, counts map[reaction.Type]int) error
+	IncReposts(ctx context.Context, postID primitive.ObjectID, n int) error
+	GetRepost(ctx context.Context, userID, originalID primitive.ObjectID) (Post, error)
+	DeleteReposts(ctx context.Context, originalID primitive.ObjectID, at time.Time) error
+	RestoreReposts(ctx context.Context, originalID primitive.ObjectID, at time.Time) error
+	AddPicture(ctx context.Context, postID primitive.ObjectID, pic picture.Picture) error
+	UpdatePicture(ctx context.Context, postID primitive.ObjectID, pic picture.Picture) error
+	GetWithPendingPictures(ctx context.Context) ([]Post, error)
+}
+
+// RevisionRepository the post revisions repository.
+type RevisionRepository interface {
+	Create(ctx context.Context, r *Revision) error
+	GetAll(ctx context.Context, postID primitive.ObjectID, skip, limit int64) ([]Revision, int64, error)
+	DeleteAll(ctx context.Context, postID primitive.ObjectID) error
+	AnonymizeUser(ctx context.Context, userID primitive.ObjectID) error
 }
@@ -8,9 +8,13 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/patch"
+	"github.com/Zucke/social_prove/pkg/picture"
 	"github.com/Zucke/social_prove/pkg/post"
+	"github.com/Zucke/social_prove/pkg/reaction"
 	"github.com/Zucke/social_prove/pkg/response"
 )
 
@@ -20,13 +24,16 @@ type Repository struct {
 	log  logger.Logger
 }
 
-var pipeLineColl = "users"
+var (
+	pipeLineColl = "users"
+	badgeColl    = "badges"
+)
 
 // Create create a new post.
 func (r *Repository) Create(ctx context.Context, u *post.Post) error {
 	_, err := r.coll.InsertOne(ctx, u)
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return err
 	}
 
@@ -35,57 +42,202 @@ func (r *Repository) Create(ctx context.Context, u *post.Post) error {
 
 // GetByID returns a post by ID.
 func (r *Repository) GetByID(ctx context.Context, objectID primitive.ObjectID) (post.Post, error) {
-	p := post.Post{}
-	pipeline := mongo.Pipeline{
-		{{"$match", bson.M{
-			"_id": objectID,
-		}}},
-
-		{{"$lookup", bson.D{
-			{"from", pipeLineColl},
-			{"localField", "user_id"},
-			{"foreignField", "_id"},
-			{"as", "user"},
-		}}},
+	posts, err := r.aggregate(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return post.Post{}, err
 	}
-	cursor, err := r.coll.Aggregate(ctx, pipeline)
-	if errors.Is(err, mongo.ErrNoDocuments) {
+
+	if len(posts) == 0 {
 		return post.Post{}, response.ErrorNotFound
 	}
 
+	return posts[0], nil
+}
+
+// GetAll returns all stored posts visible to the viewer.
+func (r *Repository) GetAll(ctx context.Context, viewerID primitive.ObjectID) ([]post.Post, error) {
+	return r.aggregate(ctx, visibleTo(viewerID, bson.M{}))
+}
+
+// GetByIDs returns the stored posts with the given IDs visible to the viewer.
+func (r *Repository) GetByIDs(ctx context.Context, ids []primitive.ObjectID, viewerID primitive.ObjectID) ([]post.Post, error) {
+	return r.aggregate(ctx, visibleTo(viewerID, bson.M{"_id": bson.M{"$in": ids}}))
+}
+
+// GetAllForUser returns all store post for a user visible to the viewer.
+func (r *Repository) GetAllForUser(ctx context.Context, userID, viewerID primitive.ObjectID) ([]post.Post, error) {
+	return r.aggregate(ctx, visibleTo(viewerID, bson.M{"user_id": userID}))
+}
+
+// PublishDue publish the scheduled posts whose publish_at is before now,
+// it returns the authors of the published posts, once per post.
+func (r *Repository) PublishDue(ctx context.Context, now time.Time) ([]primitive.ObjectID, error) {
+	filter := notDeleted(bson.M{
+		"status":     post.Scheduled,
+		"publish_at": bson.M{"$lte": now},
+	})
+
+	opt := options.Find().SetProjection(bson.M{"_id": 1, "user_id": 1})
+	cursor, err := r.coll.Find(ctx, filter, opt)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
 	defer cursor.Close(ctx)
 
-	if cursor.Next(ctx) {
+	ids := make([]primitive.ObjectID, 0)
+	authors := make([]primitive.ObjectID, 0)
+	for cursor.Next(ctx) {
+		p := post.Post{}
 		if err := cursor.Decode(&p); err != nil {
-			r.log.Error(err)
-			return post.Post{}, response.ErrorInternalServerError
+			r.log.WithContext(ctx).Error(err)
+			continue
 		}
+		ids = append(ids, p.ID)
+		authors = append(authors, p.UserID)
 	}
-	return p, nil
+
+	if len(ids) == 0 {
+		return authors, nil
+	}
+
+	// Only the posts read are published, so their authors are the ones
+	// returned.
+	filter["_id"] = bson.M{"$in": ids}
+
+	update := mongo.Pipeline{
+		bson.D{{Key: "$set", Value: bson.M{
+			"status":       post.Published,
+			"published_at": "$publish_at",
+			"updated_at":   now,
+		}}},
+		bson.D{{Key: "$unset", Value: "publish_at"}},
+	}
+
+	_, err = r.coll.UpdateMany(ctx, filter, update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	return authors, nil
 }
 
-// GetAll returns all stored posts.
-func (r *Repository) GetAll(ctx context.Context) ([]post.Post, error) {
-	posts := make([]post.Post, 0)
+// setOrUnset add the time to set, or to unset when nil.
+func setOrUnset(set, unset bson.M, key string, t *time.Time) {
+	if t == nil {
+		unset[key] = ""
+		return
+	}
 
-	pipeline := mongo.Pipeline{
-		{{"$match", bson.M{}}},
+	set[key] = t
+}
 
-		{{"$lookup", bson.D{
-			{"from", pipeLineColl},
-			{"localField", "user_id"},
-			{"foreignField", "_id"},
-			{"as", "user"},
+// visibleTo add to the filter the condition to get only the published
+// posts, or any post of the viewer.
+func visibleTo(viewerID primitive.ObjectID, filter bson.M) bson.M {
+	or := bson.A{
+		bson.M{"status": bson.M{"$exists": false}},
+		bson.M{"status": post.Published},
+	}
+	if !viewerID.IsZero() {
+		or = append(or, bson.M{"user_id": viewerID})
+	}
+
+	filter["$or"] = or
+
+	return filter
+}
+
+// notDeleted add to the filter the condition to skip the deleted posts.
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+
+	return filter
+}
+
+// withVersion add the version required by an update to filter, the posts
+// never updated have no version.
+func withVersion(filter bson.M, version int64) bson.M {
+	switch version {
+	case patch.AnyVersion:
+	case 0:
+		filter["version"] = bson.M{"$exists": false}
+	default:
+		filter["version"] = version
+	}
+
+	return filter
+}
+
+// lookupUser stages to embed the user of the post.
+func lookupUser() []bson.D {
+	return []bson.D{
+		{{Key: "$lookup", Value: bson.M{
+			"from":         pipeLineColl,
+			"localField":   "user_id",
+			"foreignField": "_id",
+			"as":           "user",
+		}}},
+		{{Key: "$unwind", Value: bson.M{
+			"path":                       "$user",
+			"preserveNullAndEmptyArrays": true,
 		}}},
+		{{Key: "$project", Value: bson.M{"user.password": 0}}},
 	}
+}
 
-	cursor, err := r.coll.Aggregate(ctx, pipeline)
-	if errors.Is(err, mongo.ErrNoDocuments) {
-		return posts, response.ErrorNotFound
+// aggregate returns the posts matching the filter and not deleted with
+// their user and the original post of the reposts. Reposts without quote are hidden when
+// their original post is not available anymore.
+func (r *Repository) aggregate(ctx context.Context, match bson.M) ([]post.Post, error) {
+	posts := make([]post.Post, 0)
+
+	// Only the published originals are shown, even to their author.
+	originalPipeline := bson.A{
+		bson.M{"$match": notDeleted(visibleTo(primitive.NilObjectID, bson.M{"$expr": bson.M{"$eq": bson.A{"$_id", "$$repost_of"}}}))},
+	}
+	for _, stage := range lookupUser() {
+		originalPipeline = append(originalPipeline, stage)
 	}
 
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: notDeleted(match)}},
+	}
+	pipeline = append(pipeline, lookupUser()...)
+	pipeline = append(pipeline,
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         badgeColl,
+			"localField":   "badge_id",
+			"foreignField": "_id",
+			"as":           "badge",
+		}}},
+		bson.D{{Key: "$unwind", Value: bson.M{
+			"path":                       "$badge",
+			"preserveNullAndEmptyArrays": true,
+		}}},
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":     r.coll.Name(),
+			"let":      bson.M{"repost_of": "$repost_of"},
+			"pipeline": originalPipeline,
+			"as":       "original",
+		}}},
+		bson.D{{Key: "$unwind", Value: bson.M{
+			"path":                       "$original",
+			"preserveNullAndEmptyArrays": true,
+		}}},
+		bson.D{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"repost_of": bson.M{"$exists": false}},
+			bson.M{"original": bson.M{"$exists": true}},
+			bson.M{"description": bson.M{"$nin": bson.A{nil, ""}}},
+			bson.M{"pictures.0": bson.M{"$exists": true}},
+		}}}},
+	)
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return nil, response.ErrorInternalServerError
 	}
 
@@ -94,39 +246,154 @@ func (r *Repository) GetAll(ctx context.Context) ([]post.Post, error) {
 	for cursor.Next(ctx) {
 		p := post.Post{}
 		if err := cursor.Decode(&p); err != nil {
-			r.log.Error(err)
+			r.log.WithContext(ctx).Error(err)
 			continue
 		}
+		p.OriginalUnavailable = !p.RepostOf.IsZero() && p.Original == nil
 		posts = append(posts, p)
 	}
 
 	return posts, nil
 }
 
-// GetAllForUser returns all store post for a user.
-func (r *Repository) GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]post.Post, error) {
-	posts := make([]post.Post, 0)
+// IncReactions add the counts to the reaction counters of a post.
+func (r *Repository) IncReactions(ctx context.Context, postID primitive.ObjectID, counts map[reaction.Type]int) error {
+	inc := bson.M{}
+	for t, n := range counts {
+		inc["reactions."+string(t)] = n
+	}
 
-	pipeline := mongo.Pipeline{
-		{{"$match", bson.M{
-			"user_id": userID,
-		}}},
+	result := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": postID}, bson.M{"$inc": inc})
+	if err := result.Err(); err != nil {
+		r.log.WithContext(ctx).Error(err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return response.ErrorNotFound
+		}
+		return response.ErrorInternalServerError
+	}
 
-		{{"$lookup", bson.D{
-			{"from", pipeLineColl},
-			{"localField", "user_id"},
-			{"foreignField", "_id"},
-			{"as", "user"},
-		}}},
+	return nil
+}
+
+// IncReposts add n to the reposts counter of a post.
+func (r *Repository) IncReposts(ctx context.Context, postID primitive.ObjectID, n int) error {
+	result := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": postID}, bson.M{"$inc": bson.M{"reposts_count": n}})
+	if err := result.Err(); err != nil {
+		r.log.WithContext(ctx).Error(err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return response.ErrorNotFound
+		}
+		return response.ErrorInternalServerError
 	}
 
-	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	return nil
+}
+
+// GetRepost returns the repost without quote of a post made by a user.
+func (r *Repository) GetRepost(ctx context.Context, userID, originalID primitive.ObjectID) (post.Post, error) {
+	filter := notDeleted(repostsOf(originalID))
+	filter["user_id"] = userID
+
+	p := post.Post{}
+	err := r.coll.FindOne(ctx, filter).Decode(&p)
 	if errors.Is(err, mongo.ErrNoDocuments) {
-		return posts, response.ErrorNotFound
+		return post.Post{}, response.ErrorNotFound
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrorInternalServerError
+	}
+
+	return p, nil
+}
+
+// repostsOf returns the filter of the reposts without quote of a post.
+func repostsOf(originalID primitive.ObjectID) bson.M {
+	return bson.M{
+		"repost_of":   originalID,
+		"description": bson.M{"$in": bson.A{nil, ""}},
+		"pictures.0":  bson.M{"$exists": false},
+	}
+}
+
+// DeleteReposts mark as deleted the reposts without quote of a post.
+func (r *Repository) DeleteReposts(ctx context.Context, originalID primitive.ObjectID, at time.Time) error {
+	update := bson.M{
+		"$set": bson.M{"deleted_at": at},
+	}
+
+	_, err := r.coll.UpdateMany(ctx, notDeleted(repostsOf(originalID)), update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// RestoreReposts undo the delete of the reposts without quote of a post
+// deleted with it.
+func (r *Repository) RestoreReposts(ctx context.Context, originalID primitive.ObjectID, at time.Time) error {
+	filter := repostsOf(originalID)
+	filter["deleted_at"] = at
+
+	_, err := r.coll.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"deleted_at": ""}})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// AddPicture append a picture to a post by ID.
+func (r *Repository) AddPicture(ctx context.Context, postID primitive.ObjectID, pic picture.Picture) error {
+	update := bson.M{
+		"$push": bson.M{"pictures": pic},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	result := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": postID}, update)
+	if err := result.Err(); err != nil {
+		r.log.WithContext(ctx).Error(err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return response.ErrorNotFound
+		}
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// UpdatePicture replace a stored picture of a post.
+func (r *Repository) UpdatePicture(ctx context.Context, postID primitive.ObjectID, pic picture.Picture) error {
+	filter := bson.M{
+		"_id":          postID,
+		"pictures._id": pic.ID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{"pictures.$": pic},
+	}
+	result := r.coll.FindOneAndUpdate(ctx, filter, update)
+	if err := result.Err(); err != nil {
+		r.log.WithContext(ctx).Error(err)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return response.ErrorNotFound
+		}
+		return response.ErrorInternalServerError
 	}
 
+	return nil
+}
+
+// GetWithPendingPictures returns the posts with pictures waiting to be processed.
+func (r *Repository) GetWithPendingPictures(ctx context.Context) ([]post.Post, error) {
+	posts := make([]post.Post, 0)
+
+	cursor, err := r.coll.Find(ctx, bson.M{"pictures.status": picture.Pending})
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return nil, response.ErrorInternalServerError
 	}
 
@@ -135,7 +402,7 @@ func (r *Repository) GetAllForUser(ctx context.Context, userID primitive.ObjectI
 	for cursor.Next(ctx) {
 		p := post.Post{}
 		if err := cursor.Decode(&p); err != nil {
-			r.log.Error(err)
+			r.log.WithContext(ctx).Error(err)
 			continue
 		}
 		posts = append(posts, p)
@@ -144,67 +411,253 @@ func (r *Repository) GetAllForUser(ctx context.Context, userID primitive.ObjectI
 	return posts, nil
 }
 
-// AddLike add a like to a user by ID.
-func (r *Repository) AddLike(ctx context.Context, fanID, postID primitive.ObjectID) error {
+// Update post by ID. Without patch.AnyVersion only the given version is
+// updated, the version is increased on every update. The pictures are left
+// to AddPicture and UpdatePicture.
+func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, p *post.Post, version int64) error {
+	set := bson.M{
+		"description": p.Description,
+		"updated_at":  time.Now(),
+	}
+	unset := bson.M{}
+	if p.BadgeID.IsZero() {
+		unset["badge_id"] = ""
+	} else {
+		set["badge_id"] = p.BadgeID
+	}
+
+	if p.EditedAt != nil {
+		set["edited_at"] = p.EditedAt
+	}
+
+	if p.Status != "" {
+		set["status"] = p.Status
+		setOrUnset(set, unset, "publish_at", p.PublishAt)
+		setOrUnset(set, unset, "published_at", p.PublishedAt)
+	}
+
 	update := bson.M{
-		"$addToSet": bson.M{"likes": fanID},
+		"$set": set,
+		"$inc": bson.M{"version": 1},
 	}
-	result := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": postID}, update)
-	if result.Err() != nil {
-		r.log.Error(result.Err())
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	filter := withVersion(notDeleted(bson.M{"_id": id}), version)
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
 		return response.ErrorInternalServerError
 	}
 
+	if result.MatchedCount == 0 {
+		return r.missed(ctx, notDeleted(bson.M{"_id": id}), version)
+	}
+
 	return nil
 }
 
-// DeleteLike delete a like to a user by ID.
-func (r *Repository) DeleteLike(ctx context.Context, fanID, postID primitive.ObjectID) error {
+// missed tells why a conditional update matched nothing, the post is gone
+// or it's in another version.
+func (r *Repository) missed(ctx context.Context, filter bson.M, version int64) error {
+	if version == patch.AnyVersion {
+		return response.ErrorNotFound
+	}
+
+	n, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if n == 0 {
+		return response.ErrorNotFound
+	}
+
+	return response.ErrPreconditionFailed
+}
+
+// Delete mark a post as deleted at the given time, the post is kept until
+// it's purged.
+func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID, at time.Time) error {
 	update := bson.M{
-		"$pull": bson.M{"likes": fanID},
+		"$set": bson.M{"deleted_at": at},
 	}
-	result := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": postID}, update)
-	if result.Err() != nil {
-		r.log.Error(result.Err())
+
+	result, err := r.coll.UpdateOne(ctx, notDeleted(bson.M{"_id": id}), update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
 		return response.ErrorInternalServerError
 	}
 
+	if result.MatchedCount == 0 {
+		return response.ErrorNotFound
+	}
+
 	return nil
 }
 
-// Update post by ID.
-func (r *Repository) Update(ctx context.Context, id primitive.ObjectID, p *post.Post) error {
+// DeleteAllForUser mark as deleted every post of a user and returns the
+// originals of the reposts deleted, so their counters can be updated.
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]primitive.ObjectID, error) {
+	update := bson.M{
+		"$set": bson.M{"deleted_at": at},
+	}
+
+	_, err := r.coll.UpdateMany(ctx, notDeleted(bson.M{"user_id": userID}), update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	return r.originalsOf(ctx, userID, at)
+}
+
+// Restore undo the delete of a post and returns it as it was deleted.
+func (r *Repository) Restore(ctx context.Context, id primitive.ObjectID) (post.Post, error) {
 	filter := bson.M{
-		"_id": id,
+		"_id":        id,
+		"deleted_at": bson.M{"$exists": true},
 	}
 
-	update := bson.M{
-		"description": p.Description,
-		"badge":       p.Badge,
-		"pictures":    p.Pictures,
-		"updated_at":  time.Now(),
+	p := post.Post{}
+	err := r.coll.FindOneAndUpdate(ctx, filter, bson.M{"$unset": bson.M{"deleted_at": ""}}).Decode(&p)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return post.Post{}, response.ErrorNotFound
 	}
 
-	sr := r.coll.FindOneAndUpdate(ctx, filter, bson.M{"$set": update})
-	if err := sr.Err(); err != nil {
-		r.log.Error(err)
-		return err
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrorInternalServerError
 	}
 
-	return nil
+	return p, nil
 }
 
-// Delete remove a user by ID.
-func (r *Repository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	filter := bson.M{"_id": id}
+// RestoreAllForUser undo the delete of the posts of a user deleted with it
+// and returns the originals of the reposts restored.
+func (r *Repository) RestoreAllForUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]primitive.ObjectID, error) {
+	originals, err := r.originalsOf(ctx, userID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"user_id":    userID,
+		"deleted_at": at,
+	}
 
-	_, err := r.coll.DeleteOne(ctx, filter)
+	_, err = r.coll.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"deleted_at": ""}})
 	if err != nil {
-		r.log.Error(err)
-		return response.ErrorInternalServerError
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
 	}
 
-	return nil
+	return originals, nil
+}
+
+// originalsOf returns the originals of the reposts of a user deleted at the
+// given time.
+func (r *Repository) originalsOf(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]primitive.ObjectID, error) {
+	filter := bson.M{
+		"user_id":    userID,
+		"deleted_at": at,
+		"repost_of":  bson.M{"$exists": true},
+	}
+	opt := options.Find().SetProjection(bson.M{"repost_of": 1})
+	originals := make([]primitive.ObjectID, 0)
+
+	cursor, err := r.coll.Find(ctx, filter, opt)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		p := post.Post{}
+		if err := cursor.Decode(&p); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		originals = append(originals, p.RepostOf)
+	}
+
+	return originals, nil
+}
+
+// GetPurgeable returns the IDs of the posts deleted before the given time.
+func (r *Repository) GetPurgeable(ctx context.Context, before time.Time) ([]primitive.ObjectID, error) {
+	opt := options.Find().SetProjection(bson.M{"_id": 1})
+	ids := make([]primitive.ObjectID, 0)
+
+	cursor, err := r.coll.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}}, opt)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		p := post.Post{}
+		if err := cursor.Decode(&p); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		ids = append(ids, p.ID)
+	}
+
+	return ids, nil
+}
+
+// GetIDsForUser returns the IDs of every post of a user, deleted or not.
+func (r *Repository) GetIDsForUser(ctx context.Context, userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	opt := options.Find().SetProjection(bson.M{"_id": 1})
+	ids := make([]primitive.ObjectID, 0)
+
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID}, opt)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		p := post.Post{}
+		if err := cursor.Decode(&p); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		ids = append(ids, p.ID)
+	}
+
+	return ids, nil
+}
+
+// Purge remove for good a deleted post by ID and returns it, so its
+// pictures can be removed as well.
+func (r *Repository) Purge(ctx context.Context, id primitive.ObjectID) (post.Post, error) {
+	filter := bson.M{
+		"_id":        id,
+		"deleted_at": bson.M{"$exists": true},
+	}
+
+	p := post.Post{}
+	err := r.coll.FindOneAndDelete(ctx, filter).Decode(&p)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return post.Post{}, response.ErrorNotFound
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrorInternalServerError
+	}
+
+	return p, nil
 }
 
 // Mongo create a new Repository.
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/post"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// RevisionRepository storage to the post revisions, it's append only.
+type RevisionRepository struct {
+	coll *mongo.Collection
+	log  logger.Logger
+}
+
+// Create store a new revision.
+func (r *RevisionRepository) Create(ctx context.Context, rev *post.Revision) error {
+	_, err := r.coll.InsertOne(ctx, rev)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrCouldNotInsert
+	}
+
+	return nil
+}
+
+// GetAll returns a page of the revisions of a post, the newest first, with
+// their editor.
+func (r *RevisionRepository) GetAll(ctx context.Context, postID primitive.ObjectID, skip, limit int64) ([]post.Revision, int64, error) {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.M{"post_id": postID}}},
+		bson.D{{Key: "$sort", Value: bson.M{"created_at": -1}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"total": bson.A{
+				bson.M{"$count": "count"},
+			},
+			"revisions": bson.A{
+				bson.M{"$skip": skip},
+				bson.M{"$limit": limit},
+				bson.M{"$lookup": bson.M{
+					"from":         pipeLineColl,
+					"localField":   "user_id",
+					"foreignField": "_id",
+					"as":           "user",
+				}},
+				bson.M{"$unwind": bson.M{
+					"path":                       "$user",
+					"preserveNullAndEmptyArrays": true,
+				}},
+				bson.M{"$project": bson.M{"user.password": 0}},
+			},
+		}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, 0, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	result := struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Revisions []post.Revision `bson:"revisions"`
+	}{}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			return nil, 0, response.ErrorInternalServerError
+		}
+	}
+
+	revisions := result.Revisions
+	if revisions == nil {
+		revisions = make([]post.Revision, 0)
+	}
+
+	var total int64
+	if len(result.Total) > 0 {
+		total = result.Total[0].Count
+	}
+
+	return revisions, total, nil
+}
+
+// DeleteAll remove the revisions of a post.
+func (r *RevisionRepository) DeleteAll(ctx context.Context, postID primitive.ObjectID) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"post_id": postID})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// AnonymizeUser unset a user as the editor of the revisions it made.
+func (r *RevisionRepository) AnonymizeUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.coll.UpdateMany(ctx, bson.M{"user_id": userID}, bson.M{
+		"$unset": bson.M{"user_id": ""},
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// Revisions create a new RevisionRepository.
+func Revisions(coll *mongo.Collection, log logger.Logger) post.RevisionRepository {
+	return &RevisionRepository{
+		coll: coll,
+		log:  log,
+	}
+}
@@ -0,0 +1,50 @@
+package post
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+// Change is the value of a field before and after an edit.
+type Change struct {
+	From interface{} `json:"from,omitempty" bson:"from,omitempty"`
+	To   interface{} `json:"to,omitempty" bson:"to,omitempty"`
+}
+
+// Revision is an edit of a post, with the editor and the changed fields.
+type Revision struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	PostID    primitive.ObjectID `json:"post_id,omitempty" bson:"post_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	User      *user.User         `json:"user,omitempty" bson:"user,omitempty"`
+	Changes   map[string]Change  `json:"changes,omitempty" bson:"changes,omitempty"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// Diff returns the editable fields that differ between two versions of a
+// post, keyed by their json name. The pictures are left out, they are
+// managed by the server and their uploads aren't edits.
+func Diff(before, after Post) map[string]Change {
+	changes := make(map[string]Change)
+
+	if before.Description != after.Description {
+		changes["description"] = Change{From: before.Description, To: after.Description}
+	}
+
+	if before.BadgeID != after.BadgeID {
+		changes["badge_id"] = Change{From: objectIDOrNil(before.BadgeID), To: objectIDOrNil(after.BadgeID)}
+	}
+
+	return changes
+}
+
+func objectIDOrNil(id primitive.ObjectID) interface{} {
+	if id.IsZero() {
+		return nil
+	}
+
+	return id
+}
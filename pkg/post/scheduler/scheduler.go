@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/badge"
+	"github.com/Zucke/social_prove/pkg/lease"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/post"
+)
+
+const (
+	// leaseName identifies the lease shared by the schedulers of every replica.
+	leaseName = "post-scheduler"
+	waitTime  = 30
+)
+
+// Scheduler publish the scheduled posts when their time comes, and award
+// the achievements their authors earn like a post published right away.
+// Only the replica holding the lease publishes, the others stay on standby.
+type Scheduler struct {
+	*lease.Runner
+	repository post.Repository
+	badges     badge.Service
+	log        logger.Logger
+}
+
+// publish publish the due posts and evaluate the achievements of their
+// authors, failed evaluations are only logged since they are evaluated
+// again later.
+func (s *Scheduler) publish(ctx context.Context) {
+	authors, err := s.repository.PublishDue(ctx, time.Now())
+	if err != nil {
+		s.log.Errorf("cannot publish the scheduled posts: %v", err)
+		return
+	}
+
+	if len(authors) == 0 {
+		return
+	}
+	s.log.Infof("%d scheduled posts published", len(authors))
+
+	evaluated := make(map[primitive.ObjectID]bool, len(authors))
+	for _, id := range authors {
+		if evaluated[id] {
+			continue
+		}
+		evaluated[id] = true
+
+		if _, err := s.badges.Evaluate(ctx, id.Hex()); err != nil {
+			s.log.Warnf("achievements of %s not evaluated: %v", id.Hex(), err)
+		}
+	}
+}
+
+// New create a new Scheduler checking the scheduled posts every interval.
+func New(log logger.Logger, repository post.Repository, badges badge.Service, locker lease.Locker, interval time.Duration) *Scheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	s := &Scheduler{
+		repository: repository,
+		badges:     badges,
+		log:        log,
+	}
+	s.Runner = lease.NewRunner(log, locker, leaseName, interval, waitTime*time.Second, s.publish)
+
+	return s
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	badgemock "github.com/Zucke/social_prove/pkg/badge/mock"
+	lmock "github.com/Zucke/social_prove/pkg/lease/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	mock "github.com/Zucke/social_prove/pkg/post/mock"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+func TestScheduler_Publish(t *testing.T) {
+	author1 := primitive.NewObjectID()
+	author2 := primitive.NewObjectID()
+
+	tests := []struct {
+		name          string
+		authors       []primitive.ObjectID
+		publishErr    error
+		evaluateErr   error
+		timesEvaluate int
+	}{
+		{
+			name:          "due posts published",
+			authors:       []primitive.ObjectID{author1, author2, author1},
+			timesEvaluate: 1,
+		},
+		{
+			name:          "published when the evaluation fails",
+			authors:       []primitive.ObjectID{author1, author2},
+			evaluateErr:   response.ErrorInternalServerError,
+			timesEvaluate: 1,
+		},
+		{
+			name:    "nothing due",
+			authors: []primitive.ObjectID{},
+		},
+		{
+			name:       "publish failure",
+			publishErr: response.ErrorInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			m := mock.NewMockRepository(ctrl)
+			bm := badgemock.NewMockService(ctrl)
+			lm := lmock.NewMockLocker(ctrl)
+
+			m.
+				EXPECT().
+				PublishDue(gomock.Any(), gomock.Any()).
+				Return(test.authors, test.publishErr).
+				Times(1)
+			bm.
+				EXPECT().
+				Evaluate(gomock.Any(), author1.Hex()).
+				Return(nil, test.evaluateErr).
+				Times(test.timesEvaluate)
+			bm.
+				EXPECT().
+				Evaluate(gomock.Any(), author2.Hex()).
+				Return(nil, test.evaluateErr).
+				Times(test.timesEvaluate)
+
+			s := New(logger.NewMock(), m, bm, lm, time.Second)
+			s.publish(context.Background())
+		})
+	}
+}
@@ -2,19 +2,28 @@ package post
 
 import (
 	"context"
+	"io"
 
+	"github.com/Zucke/social_prove/pkg/reaction"
 	"github.com/Zucke/social_prove/pkg/user"
 )
 
 //Service the post service
 type Service interface {
 	Create(ctx context.Context, p *Post) error
-	GetByID(ctx context.Context, id string) (Post, error)
-	GetAll(ctx context.Context) ([]Post, error)
-	GetAllForUser(ctx context.Context, userID string) ([]Post, error)
-	Update(ctx context.Context, toUpdateid string, currendUserID string, role user.Role, p *Post) (Post, error)
+	GetByID(ctx context.Context, id string, viewerID string) (Post, error)
+	GetAll(ctx context.Context, viewerID string) ([]Post, error)
+	GetAllForUser(ctx context.Context, userID string, viewerID string) ([]Post, error)
+	Update(ctx context.Context, toUpdateid string, currendUserID string, role user.Role, p *Post, version int64) (Post, error)
+	Patch(ctx context.Context, toUpdateid string, currendUserID string, role user.Role, p []byte, version int64) (Post, error)
 	Delete(ctx context.Context, toDeleteID string, currendUserID string, role user.Role) error
-	AddLike(ctx context.Context, fanID, postID string) (Post, error)
-	DeleteLike(ctx context.Context, fanID, postID string) (Post, error)
+	Restore(ctx context.Context, id string) (Post, error)
+	React(ctx context.Context, userID, postID string, t reaction.Type) (Post, error)
+	Unreact(ctx context.Context, userID, postID string) (Post, error)
+	GetReactions(ctx context.Context, postID string, viewerID string, t reaction.Type, page int, limit int) ([]user.User, int, error)
+	GetRevisions(ctx context.Context, postID string, currendUserID string, role user.Role, page int, limit int) ([]Revision, int, error)
+	Repost(ctx context.Context, userID, postID string, quote string) (Post, error)
+	Unrepost(ctx context.Context, userID, postID string) (Post, error)
+	AddPicture(ctx context.Context, postID string, currendUserID string, role user.Role, ext string, r io.Reader) (Post, error)
 	WithPagination(p []Post, page int, limit int) ([]Post, int)
 }
@@ -2,78 +2,203 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"time"
 
+	"github.com/Zucke/social_prove/pkg/audit"
+	"github.com/Zucke/social_prove/pkg/badge"
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/metrics"
+	"github.com/Zucke/social_prove/pkg/patch"
+	"github.com/Zucke/social_prove/pkg/picture"
 	"github.com/Zucke/social_prove/pkg/post"
 	"github.com/Zucke/social_prove/pkg/post/repository"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	reactionrepository "github.com/Zucke/social_prove/pkg/reaction/repository"
 	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/tracing"
 	"github.com/Zucke/social_prove/pkg/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-const waitTime = 10
-
 // PostService the post service.
 type PostService struct {
 	repository post.Repository
+	reactions  reaction.Repository
+	revisions  post.RevisionRepository
+	badges     badge.Service
+	storage    picture.Storage
+	queue      picture.Queue
+	auditor    audit.Recorder
+	timeout    time.Duration
 	log        logger.Logger
 }
 
 // Create create a new post.
 func (ps *PostService) Create(ctx context.Context, p *post.Post) error {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "PostService.Create")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
 	defer cancel()
 
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
 	if p.ID.IsZero() {
 		p.ID = primitive.NewObjectID()
 	}
 
+	if err := p.Schedule(time.Now()); err != nil {
+		return err
+	}
+
+	if err := ps.validateBadge(ctx, p); err != nil {
+		return err
+	}
+
+	// The pictures are uploaded once the post exists and the reactions
+	// counted as they're added.
+	p.Pictures = nil
+	p.Reactions = nil
+	p.Original = nil
+	p.RepostsCount = 0
 	p.CreatedAt = time.Now()
 	p.UpdatedAt = time.Now()
 
 	if err := ps.repository.Create(ctx, p); err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return response.ErrCouldNotInsert
 	}
+	metrics.PostsCreated.Inc()
+
+	if p.IsPublished() {
+		ps.evaluate(ctx, p.UserID)
+	}
+	return nil
+}
+
+// reschedule resolve the new status of a stored post, a published post
+// can't go back to draft or scheduled.
+func reschedule(stored post.Post, p *post.Post) error {
+	if stored.IsPublished() {
+		if p.Status != post.Published {
+			return post.ErrInvalidStatus
+		}
+
+		// Already published, keep its publication time.
+		p.Status = ""
+		return nil
+	}
+
+	return p.Schedule(time.Now())
+}
+
+// viewer returns the ObjectID of the viewer, NilObjectID for anonymous
+// or invalid viewers.
+func viewer(viewerID string) primitive.ObjectID {
+	objectID, err := primitive.ObjectIDFromHex(viewerID)
+	if err != nil {
+		return primitive.NilObjectID
+	}
+
+	return objectID
+}
+
+// validateBadge check the post references a post badge of the catalog.
+func (ps *PostService) validateBadge(ctx context.Context, p *post.Post) error {
+	p.Badge = nil
+	if p.BadgeID.IsZero() {
+		return nil
+	}
+
+	b, err := ps.badges.GetByID(ctx, p.BadgeID.Hex())
+	if errors.Is(err, response.ErrorNotFound) {
+		return badge.ErrInvalidBadge
+	}
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	if b.Kind != badge.PostBadge {
+		return badge.ErrInvalidBadge
+	}
+
 	return nil
 }
 
+// evaluate award the achievements earned by a user, failures are only
+// logged since they are evaluated again later.
+func (ps *PostService) evaluate(ctx context.Context, userID primitive.ObjectID) {
+	if ps.badges == nil || userID.IsZero() {
+		return
+	}
+
+	if _, err := ps.badges.Evaluate(ctx, userID.Hex()); err != nil {
+		ps.log.WithContext(ctx).Warnf("achievements of %s not evaluated: %v", userID.Hex(), err)
+	}
+}
+
 // GetByID returns a post by ID.
-func (ps *PostService) GetByID(ctx context.Context, id string) (post.Post, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+func (ps *PostService) GetByID(ctx context.Context, id string, viewerID string) (post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetByID")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, response.ErrInvalidID
 	}
 
 	p, err := ps.repository.GetByID(ctx, objectID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, err
 	}
 
-	return p, nil
+	if !p.VisibleTo(viewer(viewerID)) {
+		return post.Post{}, response.ErrorNotFound
+	}
+
+	posts := []post.Post{p}
+	if err := ps.markReactions(ctx, viewerID, posts); err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	return posts[0], nil
 }
 
 // GetAllForUser return all post of a user.
-func (ps *PostService) GetAllForUser(ctx context.Context, userID string) ([]post.Post, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+func (ps *PostService) GetAllForUser(ctx context.Context, userID string, viewerID string) ([]post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetAllForUser")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
 	defer cancel()
 
 	objectUserID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return nil, response.ErrInvalidID
 	}
 
-	posts, err := ps.repository.GetAllForUser(ctx, objectUserID)
+	posts, err := ps.repository.GetAllForUser(ctx, objectUserID, viewer(viewerID))
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	if err := ps.markReactions(ctx, viewerID, posts); err != nil {
+		ps.log.WithContext(ctx).Error(err)
 		return nil, err
 	}
 
@@ -81,148 +206,692 @@ func (ps *PostService) GetAllForUser(ctx context.Context, userID string) ([]post
 }
 
 // GetAll returns all stored posts.
-func (ps *PostService) GetAll(ctx context.Context) ([]post.Post, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+func (ps *PostService) GetAll(ctx context.Context, viewerID string) ([]post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetAll")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
 	defer cancel()
 
-	posts, err := ps.repository.GetAll(ctx)
+	posts, err := ps.repository.GetAll(ctx, viewer(viewerID))
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	if err := ps.markReactions(ctx, viewerID, posts); err != nil {
+		ps.log.WithContext(ctx).Error(err)
 		return nil, err
 	}
 
 	return posts, nil
 }
 
-// Update post by ID.
-func (ps *PostService) Update(ctx context.Context, toUpdateID string, currendUserID string, role user.Role, p *post.Post) (post.Post, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+// Update replace a post by ID, the fields left out are emptied. Without
+// patch.AnyVersion the post must be in the given version.
+func (ps *PostService) Update(ctx context.Context, toUpdateID string, currendUserID string, role user.Role, p *post.Post, version int64) (post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Update")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(toUpdateID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, response.ErrInvalidID
 	}
 
-	if role == user.Client {
-		vPost, err := ps.GetByID(ctx, toUpdateID)
-		if err != nil {
-			ps.log.Error(err)
+	vPost, err := ps.repository.GetByID(ctx, objectID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+	if role == user.Client && currendUserID != vPost.UserID.Hex() {
+		return post.Post{}, response.ErrorUnauthorized
+	}
+
+	if err := p.Validate(); err != nil {
+		return post.Post{}, err
+	}
+
+	// The pictures are only changed by AddPicture and the worker.
+	p.Pictures = vPost.Pictures
+
+	if version != patch.AnyVersion && version != vPost.Version {
+		return post.Post{}, response.ErrPreconditionFailed
+	}
+
+	if p.Status != "" {
+		if err := reschedule(vPost, p); err != nil {
 			return post.Post{}, err
 		}
-		if currendUserID != vPost.UserID.Hex() {
-			return post.Post{}, response.ErrorUnauthorized
-		}
 	}
 
-	err = ps.repository.Update(ctx, objectID, p)
+	if err := ps.validateBadge(ctx, p); err != nil {
+		return post.Post{}, err
+	}
+
+	// Only the edits of published posts are shown as edited.
+	changes := post.Diff(vPost, *p)
+	now := time.Now()
+	if len(changes) > 0 && vPost.IsPublished() {
+		p.EditedAt = &now
+	}
+
+	err = ps.repository.Update(ctx, objectID, p, version)
+	if errors.Is(err, response.ErrorNotFound) || errors.Is(err, response.ErrPreconditionFailed) {
+		return post.Post{}, err
+	}
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, response.ErrorInternalServerError
 	}
-	updatedPost, err := ps.GetByID(ctx, toUpdateID)
+
+	if len(changes) > 0 {
+		rev := post.Revision{
+			ID:        primitive.NewObjectID(),
+			PostID:    objectID,
+			UserID:    viewer(currendUserID),
+			Changes:   changes,
+			CreatedAt: now,
+		}
+		if err := ps.revisions.Create(ctx, &rev); err != nil {
+			ps.log.WithContext(ctx).Error(err)
+			return post.Post{}, err
+		}
+	}
+
+	// Not GetByID, admins are allowed to update posts they can't see.
+	updatedPost, err := ps.repository.GetByID(ctx, objectID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, err
 	}
 
-	return updatedPost, nil
+	if role != user.Client {
+		ps.auditAction(ctx, audit.UpdatePost, objectID, &vPost, &updatedPost)
+	}
+
+	posts := []post.Post{updatedPost}
+	if err := ps.markReactions(ctx, currendUserID, posts); err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
 
+	return posts[0], nil
+
+}
+
+// Patch apply a JSON merge patch to a post by ID, only the fields in the
+// patch are changed. Without patch.AnyVersion the post must be in the
+// given version.
+func (ps *PostService) Patch(ctx context.Context, toUpdateID string, currendUserID string, role user.Role, p []byte, version int64) (post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Patch")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(toUpdateID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrInvalidID
+	}
+
+	vPost, err := ps.repository.GetByID(ctx, objectID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+	if role == user.Client && currendUserID != vPost.UserID.Hex() {
+		return post.Post{}, response.ErrorUnauthorized
+	}
+
+	if err := patch.Apply(&vPost, p, post.Editable...); err != nil {
+		return post.Post{}, err
+	}
+
+	return ps.Update(ctx, toUpdateID, currendUserID, role, &vPost, version)
 }
 
-// Delete remove a post by ID.
+// Delete mark a post and the reposts without quote of it as deleted, they
+// can be restored until they're purged.
 func (ps *PostService) Delete(ctx context.Context, toDeleteID string, currendUserID string, role user.Role) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	ctx, span := tracing.Start(ctx, "PostService.Delete")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(toDeleteID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return response.ErrInvalidID
 	}
 
-	if role == user.Client {
-		vPost, err := ps.GetByID(ctx, toDeleteID)
-		if err != nil {
-			ps.log.Error(err)
+	vPost, err := ps.repository.GetByID(ctx, objectID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return err
+	}
+	if role == user.Client && currendUserID != vPost.UserID.Hex() {
+		return response.ErrorUnauthorized
+	}
+
+	now := time.Now()
+	err = ps.repository.Delete(ctx, objectID, now)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	if !vPost.RepostOf.IsZero() {
+		err = ps.repository.IncReposts(ctx, vPost.RepostOf, -1)
+		if err != nil && !errors.Is(err, response.ErrorNotFound) {
+			ps.log.WithContext(ctx).Error(err)
 			return err
 		}
-		if currendUserID != vPost.UserID.Hex() {
-			return response.ErrorUnauthorized
-		}
 	}
 
-	err = ps.repository.Delete(ctx, objectID)
+	err = ps.repository.DeleteReposts(ctx, objectID, now)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return err
 	}
+
+	if role != user.Client {
+		ps.auditAction(ctx, audit.DeletePost, objectID, &vPost, nil)
+	}
+
 	return nil
 }
 
-// AddLike add a like to user.
-func (ps *PostService) AddLike(ctx context.Context, fanID, postID string) (post.Post, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+// Restore undo the delete of a post and of the reposts deleted with it.
+func (ps *PostService) Restore(ctx context.Context, id string) (post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Restore")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrInvalidID
+	}
+
+	deleted, err := ps.repository.Restore(ctx, objectID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	if !deleted.RepostOf.IsZero() {
+		err = ps.repository.IncReposts(ctx, deleted.RepostOf, 1)
+		if err != nil && !errors.Is(err, response.ErrorNotFound) {
+			ps.log.WithContext(ctx).Error(err)
+			return post.Post{}, err
+		}
+	}
+
+	err = ps.repository.RestoreReposts(ctx, objectID, *deleted.DeletedAt)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	p, err := ps.repository.GetByID(ctx, objectID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+	ps.auditAction(ctx, audit.RestorePost, objectID, nil, &p)
+
+	return p, nil
+}
+
+// Repost share a post in the feed of the user, quote is optional and turns
+// the repost into a quote-post. A repost of a repost points to the original.
+func (ps *PostService) Repost(ctx context.Context, userID, postID string, quote string) (post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Repost")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
 	defer cancel()
 
-	objectFanID, err := primitive.ObjectIDFromHex(fanID)
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, response.ErrInvalidID
 	}
 	objectPostID, err := primitive.ObjectIDFromHex(postID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, response.ErrInvalidID
 	}
 
-	err = ps.repository.AddLike(ctx, objectFanID, objectPostID)
+	if err := (post.Post{Description: quote}).Validate(); err != nil {
+		return post.Post{}, err
+	}
+
+	original, err := ps.repository.GetByID(ctx, objectPostID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, err
 	}
+	if !original.IsPublished() {
+		return post.Post{}, response.ErrorNotFound
+	}
+
+	if original.IsRepost() {
+		if original.Original == nil {
+			return post.Post{}, response.ErrorNotFound
+		}
+		original = *original.Original
+	}
 
-	updatedPost, err := ps.GetByID(ctx, postID)
+	if quote == "" {
+		_, err := ps.repository.GetRepost(ctx, objectUserID, original.ID)
+		if err == nil {
+			return post.Post{}, post.ErrAlreadyReposted
+		}
+		if !errors.Is(err, response.ErrorNotFound) {
+			ps.log.WithContext(ctx).Error(err)
+			return post.Post{}, err
+		}
+	}
+
+	repost := post.Post{
+		UserID:      objectUserID,
+		Description: quote,
+		RepostOf:    original.ID,
+	}
+	if err := ps.Create(ctx, &repost); err != nil {
+		return post.Post{}, err
+	}
+
+	if err := ps.repository.IncReposts(ctx, original.ID, 1); err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	created, err := ps.GetByID(ctx, repost.ID.Hex(), userID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	return created, nil
+}
+
+// Unrepost remove the repost without quote of a post made by the user and
+// returns the original post. A repost ID points to its original, like in
+// Repost.
+func (ps *PostService) Unrepost(ctx context.Context, userID, postID string) (post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Unrepost")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+	defer cancel()
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrInvalidID
+	}
+	objectPostID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrInvalidID
+	}
+
+	p, err := ps.repository.GetByID(ctx, objectPostID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+	if p.IsRepost() {
+		objectPostID = p.RepostOf
+	}
+
+	repost, err := ps.repository.GetRepost(ctx, objectUserID, objectPostID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	if err := ps.repository.Delete(ctx, repost.ID, time.Now()); err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	if err := ps.repository.IncReposts(ctx, objectPostID, -1); err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	original, err := ps.GetByID(ctx, objectPostID.Hex(), userID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	return original, nil
+}
+
+// React set the reaction of a user to a post, replacing the previous one.
+func (ps *PostService) React(ctx context.Context, userID, postID string, t reaction.Type) (post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.React")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+	defer cancel()
+
+	if !t.Valid() {
+		return post.Post{}, reaction.ErrInvalidType
+	}
+
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrInvalidID
+	}
+	objectPostID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrInvalidID
+	}
+
+	reacted, err := ps.repository.GetByID(ctx, objectPostID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+	if !reacted.VisibleTo(objectUserID) {
+		return post.Post{}, response.ErrorNotFound
+	}
+
+	now := time.Now()
+	re := reaction.Reaction{
+		ID:        primitive.NewObjectID(),
+		Target:    reaction.Post,
+		TargetID:  objectPostID,
+		UserID:    objectUserID,
+		Type:      t,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	previous, err := ps.reactions.Set(ctx, &re)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	if previous != t {
+		metrics.Reactions.WithLabelValues(string(t)).Inc()
+
+		counts := map[reaction.Type]int{t: 1}
+		if previous != "" {
+			counts[previous] = -1
+		}
+
+		if err := ps.repository.IncReactions(ctx, objectPostID, counts); err != nil {
+			ps.log.WithContext(ctx).Error(err)
+			return post.Post{}, err
+		}
+
+		if previous == "" {
+			ps.evaluate(ctx, reacted.UserID)
+		}
+	}
+
+	updatedPost, err := ps.GetByID(ctx, postID, userID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, err
 	}
 
 	return updatedPost, nil
 }
 
-// DeleteLike delete a like from user.
-func (ps *PostService) DeleteLike(ctx context.Context, fanID, postID string) (post.Post, error) {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+// Unreact remove the reaction of a user to a post.
+func (ps *PostService) Unreact(ctx context.Context, userID, postID string) (post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.Unreact")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
 	defer cancel()
 
-	objectFanID, err := primitive.ObjectIDFromHex(fanID)
+	objectUserID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, response.ErrInvalidID
 	}
 	objectPostID, err := primitive.ObjectIDFromHex(postID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrInvalidID
+	}
+
+	previous, err := ps.reactions.Delete(ctx, reaction.Post, objectPostID, objectUserID)
+	switch {
+	case errors.Is(err, reaction.ErrNoReaction):
+	case err != nil:
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	default:
+		counts := map[reaction.Type]int{previous: -1}
+		if err := ps.repository.IncReactions(ctx, objectPostID, counts); err != nil {
+			ps.log.WithContext(ctx).Error(err)
+			return post.Post{}, err
+		}
+	}
+
+	updatedPost, err := ps.GetByID(ctx, postID, userID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, err
+	}
+
+	return updatedPost, nil
+}
+
+// GetReactions returns a page of the users who reacted to a post the
+// viewer can see, an empty type returns every reaction.
+func (ps *PostService) GetReactions(ctx context.Context, postID string, viewerID string, t reaction.Type, page int, limit int) ([]user.User, int, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetReactions")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+	defer cancel()
+
+	if t != "" && !t.Valid() {
+		return nil, 0, reaction.ErrInvalidType
+	}
+
+	objectPostID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return nil, 0, response.ErrInvalidID
+	}
+
+	posts, err := ps.repository.GetByIDs(ctx, []primitive.ObjectID{objectPostID}, viewer(viewerID))
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return nil, 0, err
+	}
+	if len(posts) == 0 {
+		return nil, 0, response.ErrorNotFound
+	}
+
+	if limit < 1 {
+		limit = 1
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	users, total, err := ps.reactions.GetUsers(ctx, reaction.Post, objectPostID, t, int64((page-1)*limit), int64(limit))
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return nil, 0, err
+	}
+
+	return users, int(total), nil
+}
+
+// GetRevisions returns a page of the edits of a post, only the author
+// and the admins can see them.
+func (ps *PostService) GetRevisions(ctx context.Context, postID string, currendUserID string, role user.Role, page int, limit int) ([]post.Revision, int, error) {
+	ctx, span := tracing.Start(ctx, "PostService.GetRevisions")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+	defer cancel()
+
+	objectPostID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return nil, 0, response.ErrInvalidID
+	}
+
+	vPost, err := ps.repository.GetByID(ctx, objectPostID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return nil, 0, err
+	}
+	if role == user.Client && currendUserID != vPost.UserID.Hex() {
+		return nil, 0, response.ErrorUnauthorized
+	}
+
+	if limit < 1 {
+		limit = 1
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	revisions, total, err := ps.revisions.GetAll(ctx, objectPostID, int64((page-1)*limit), int64(limit))
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return nil, 0, err
+	}
+
+	return revisions, int(total), nil
+}
+
+// markReactions set MyReaction on the posts the viewer reacted to.
+func (ps *PostService) markReactions(ctx context.Context, viewerID string, posts []post.Post) error {
+	if viewerID == "" || len(posts) == 0 {
+		return nil
+	}
+
+	objectViewerID, err := primitive.ObjectIDFromHex(viewerID)
+	if err != nil {
+		return response.ErrInvalidID
+	}
+
+	ids := make([]primitive.ObjectID, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+
+	reactions, err := ps.reactions.GetUserReactions(ctx, reaction.Post, objectViewerID, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		posts[i].MyReaction = reactions[posts[i].ID]
+	}
+
+	return nil
+}
+
+// AddPicture store an uploaded picture and enqueue it to generate its
+// variants, the post is checked before the picture is stored.
+func (ps *PostService) AddPicture(ctx context.Context, postID string, currendUserID string, role user.Role, ext string, r io.Reader) (post.Post, error) {
+	ctx, span := tracing.Start(ctx, "PostService.AddPicture")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, ps.timeout)
+	defer cancel()
+
+	objectPostID, err := primitive.ObjectIDFromHex(postID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, response.ErrInvalidID
 	}
 
-	err = ps.repository.DeleteLike(ctx, objectFanID, objectPostID)
+	vPost, err := ps.repository.GetByID(ctx, objectPostID)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, err
 	}
+	if role == user.Client && currendUserID != vPost.UserID.Hex() {
+		return post.Post{}, response.ErrorUnauthorized
+	}
 
-	updatedPost, err := ps.GetByID(ctx, postID)
+	pic := picture.Picture{
+		ID:        primitive.NewObjectID(),
+		Status:    picture.Pending,
+		CreatedAt: time.Now(),
+	}
+	pic.Key = fmt.Sprintf("posts/%s/%s%s", postID, pic.ID.Hex(), ext)
+
+	pic.URL, err = ps.storage.Save(ctx, pic.Key, r)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
+		return post.Post{}, response.ErrorInternalServerError
+	}
+
+	err = ps.repository.AddPicture(ctx, objectPostID, pic)
 	if err != nil {
-		ps.log.Error(err)
+		ps.log.WithContext(ctx).Error(err)
+		if err := ps.storage.Delete(ctx, pic.Key); err != nil {
+			ps.log.WithContext(ctx).Errorf("cannot delete the picture %s: %v", pic.Key, err)
+		}
+		return post.Post{}, err
+	}
+
+	err = ps.queue.Enqueue(picture.Job{PostID: objectPostID, Picture: pic})
+	if err != nil {
+		ps.log.WithContext(ctx).Warnf("picture %s left pending: %v", pic.ID.Hex(), err)
+	}
+
+	updatedPost, err := ps.GetByID(ctx, postID, currendUserID)
+	if err != nil {
+		ps.log.WithContext(ctx).Error(err)
 		return post.Post{}, err
 	}
 
 	return updatedPost, nil
 }
 
+// auditAction record an administrative action on a post, a failure is
+// only logged as the action is already done.
+func (ps *PostService) auditAction(ctx context.Context, action audit.Action, id primitive.ObjectID, before, after *post.Post) {
+	e := audit.Entry{
+		Action:     action,
+		TargetType: audit.PostTarget,
+		TargetID:   id,
+	}
+	if before != nil {
+		e.Before = audit.Snapshot(*before)
+	}
+	if after != nil {
+		e.After = audit.Snapshot(*after)
+	}
+
+	if err := ps.auditor.Record(ctx, e); err != nil {
+		ps.log.WithContext(ctx).Errorf("cannot audit %s of %s: %v", action, id.Hex(), err)
+	}
+}
+
 // WithPagination returns users with a pagination limit.
 func (ps *PostService) WithPagination(p []post.Post, page int, limit int) ([]post.Post, int) {
 	if limit < 0 {
@@ -254,9 +923,16 @@ func (ps *PostService) WithPagination(p []post.Post, page int, limit int) ([]pos
 }
 
 // New create and configure user services.
-func New(coll *mongo.Collection, log logger.Logger) post.Service {
+func New(coll, reactionColl, revisionColl *mongo.Collection, log logger.Logger, timeout time.Duration, storage picture.Storage, queue picture.Queue, badges badge.Service, auditor audit.Recorder) post.Service {
 	return &PostService{
 		repository: repository.Mongo(coll, log),
+		reactions:  reactionrepository.Mongo(reactionColl, log),
+		revisions:  repository.Revisions(revisionColl, log),
+		badges:     badges,
+		storage:    storage,
+		queue:      queue,
+		auditor:    auditor,
 		log:        log,
+		timeout:    timeout,
 	}
 }
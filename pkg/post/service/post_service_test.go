@@ -2,15 +2,26 @@ package service
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/Zucke/social_prove/pkg/audit"
+	amock "github.com/Zucke/social_prove/pkg/audit/mock"
+	"github.com/Zucke/social_prove/pkg/badge"
+	badgemock "github.com/Zucke/social_prove/pkg/badge/mock"
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/patch"
+	"github.com/Zucke/social_prove/pkg/picture"
+	pmock "github.com/Zucke/social_prove/pkg/picture/mock"
 	"github.com/Zucke/social_prove/pkg/post"
 	mock "github.com/Zucke/social_prove/pkg/post/mock"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	rmock "github.com/Zucke/social_prove/pkg/reaction/mock"
 	"github.com/Zucke/social_prove/pkg/response"
 	"github.com/Zucke/social_prove/pkg/user"
 )
@@ -24,6 +35,8 @@ func TestUserService_Create(t *testing.T) {
 
 	p := post.Post{
 		Description: "contend bla bla bla, bla",
+		Pictures:    []picture.Picture{{URL: "http://example.com/forged.png", Status: picture.Ready}},
+		Reactions:   map[reaction.Type]int64{reaction.Like: 1000},
 	}
 
 	ctx := context.Background()
@@ -63,10 +76,84 @@ func TestUserService_Create(t *testing.T) {
 
 			err := s.Create(ctx, &test.post)
 			assert.Equal(t, err, test.err)
+			assert.Nil(t, test.post.Pictures)
+			assert.Nil(t, test.post.Reactions)
 
 		})
 	}
 }
+func TestPostService_CreateBadge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	m := mock.NewMockRepository(ctrl)
+	bm := badgemock.NewMockService(ctrl)
+	userID := primitive.NewObjectID()
+	badgeID := primitive.NewObjectID()
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name        string
+		badge       badge.Badge
+		badgeErr    error
+		err         error
+		timesCreate int
+	}{
+		{
+			name:        "succes",
+			badge:       badge.Badge{ID: badgeID, Name: "Explorer", Kind: badge.PostBadge},
+			timesCreate: 1,
+		},
+		{
+			name:  "failure achievement badge",
+			badge: badge.Badge{ID: badgeID, Name: "Traveler", Kind: badge.Achievement},
+			err:   badge.ErrInvalidBadge,
+		},
+		{
+			name:     "failure unknown badge",
+			badgeErr: response.ErrorNotFound,
+			err:      badge.ErrInvalidBadge,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := post.Post{
+				UserID:      userID,
+				Description: "contend bla bla bla, bla",
+				BadgeID:     badgeID,
+			}
+
+			bm.
+				EXPECT().
+				GetByID(gomock.Any(), badgeID.Hex()).
+				Return(test.badge, test.badgeErr).
+				Times(1)
+			m.
+				EXPECT().
+				Create(gomock.Any(), &p).
+				Return(nil).
+				Times(test.timesCreate)
+			bm.
+				EXPECT().
+				Evaluate(gomock.Any(), userID.Hex()).
+				Return(nil, nil).
+				Times(test.timesCreate)
+
+			s := PostService{
+				repository: m,
+				badges:     bm,
+				log:        l,
+			}
+
+			err := s.Create(ctx, &p)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
 func TestUserService_GetByID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -127,7 +214,7 @@ func TestUserService_GetByID(t *testing.T) {
 				log:        l,
 			}
 
-			resultPost, err := s.GetByID(ctx, test.id)
+			resultPost, err := s.GetByID(ctx, test.id, "")
 			assert.Equal(t, err, test.err)
 			assert.Equal(t, resultPost, test.post)
 
@@ -177,7 +264,7 @@ func TestUserService_GetAll(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				GetAll(gomock.Any()).
+				GetAll(gomock.Any(), primitive.NilObjectID).
 				Return(test.posts, test.err).
 				Times(test.times)
 
@@ -186,7 +273,7 @@ func TestUserService_GetAll(t *testing.T) {
 				log:        l,
 			}
 
-			resultPosts, err := s.GetAll(ctx)
+			resultPosts, err := s.GetAll(ctx, "")
 			assert.Equal(t, err, test.err)
 			assert.Equal(t, resultPosts, test.posts)
 
@@ -251,7 +338,7 @@ func TestUserService_GetAllForUser(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				GetAllForUser(gomock.Any(), test.oID).
+				GetAllForUser(gomock.Any(), test.oID, primitive.NilObjectID).
 				Return(test.posts, test.err).
 				Times(test.times)
 
@@ -260,7 +347,7 @@ func TestUserService_GetAllForUser(t *testing.T) {
 				log:        l,
 			}
 
-			resultPosts, err := s.GetAllForUser(ctx, test.id)
+			resultPosts, err := s.GetAllForUser(ctx, test.id, "")
 			assert.Equal(t, err, test.err)
 			assert.Equal(t, resultPosts, test.posts)
 
@@ -273,6 +360,8 @@ func TestUserService_Update(t *testing.T) {
 
 	defer ctrl.Finish()
 	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	am := amock.NewMockRecorder(ctrl)
 	id1 := primitive.NewObjectID()
 	id2 := primitive.NewObjectID()
 	p := post.Post{
@@ -299,6 +388,7 @@ func TestUserService_Update(t *testing.T) {
 		times    int
 		timesID1 int
 		timesID2 int
+		timesAud int
 		role     user.Role
 	}{
 		{
@@ -344,15 +434,16 @@ func TestUserService_Update(t *testing.T) {
 			err:      nil,
 			id:       id1.Hex(),
 			oID:      id1,
-			timesID1: 0,
+			timesID1: 1,
 			times:    1,
 			timesID2: 1,
+			timesAud: 1,
 			role:     user.Admin,
 		},
 		{
 			name:     "failure internal error",
 			post:     post.Post{},
-			rpost:    p,
+			rpost:    post.Post{UserID: id2},
 			err:      response.ErrorInternalServerError,
 			id:       id1.Hex(),
 			oID:      id1,
@@ -366,7 +457,7 @@ func TestUserService_Update(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				Update(gomock.Any(), test.oID, &test.post).
+				Update(gomock.Any(), test.oID, &test.post, patch.AnyVersion).
 				Return(test.err).
 				Times(test.times)
 			m.
@@ -379,13 +470,32 @@ func TestUserService_Update(t *testing.T) {
 				GetByID(gomock.Any(), test.oID).
 				Return(test.post, nil).
 				Times(test.timesID2)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, id2, gomock.Any()).
+				Return(nil, nil).
+				Times(test.timesID2)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					// Only the changes of the admins are audited.
+					assert.Equal(t, audit.UpdatePost, e.Action)
+					assert.Equal(t, test.oID, e.TargetID)
+					assert.Equal(t, test.rpost.UserID.Hex(), e.Before["user_id"])
+					assert.Equal(t, test.post.UserID.Hex(), e.After["user_id"])
+					return nil
+				}).
+				Times(test.timesAud)
 
 			s := PostService{
 				repository: m,
+				reactions:  rm,
+				auditor:    am,
 				log:        l,
 			}
 
-			resultPosts, err := s.Update(ctx, test.id, id2.Hex(), test.role, &test.post)
+			resultPosts, err := s.Update(ctx, test.id, id2.Hex(), test.role, &test.post, patch.AnyVersion)
 			assert.Equal(t, err, test.err)
 			assert.Equal(t, resultPosts, test.post)
 
@@ -397,6 +507,7 @@ func TestUserService_Delete(t *testing.T) {
 
 	defer ctrl.Finish()
 	m := mock.NewMockRepository(ctrl)
+	am := amock.NewMockRecorder(ctrl)
 	id1 := primitive.NewObjectID()
 	id2 := primitive.NewObjectID()
 	p := post.Post{
@@ -409,6 +520,11 @@ func TestUserService_Delete(t *testing.T) {
 		UserID:      primitive.NewObjectID(),
 		Description: "contend bla bla bla, bla",
 	}
+	repost := post.Post{
+		ID:       primitive.NewObjectID(),
+		UserID:   id2,
+		RepostOf: primitive.NewObjectID(),
+	}
 
 	ctx := context.Background()
 	l := logger.NewMock()
@@ -421,6 +537,9 @@ func TestUserService_Delete(t *testing.T) {
 		oID      primitive.ObjectID
 		times    int
 		timesID1 int
+		timesInc int
+		timesDel int
+		timesAud int
 		role     user.Role
 	}{
 		{
@@ -431,6 +550,19 @@ func TestUserService_Delete(t *testing.T) {
 			oID:      id1,
 			timesID1: 1,
 			times:    1,
+			timesDel: 1,
+			role:     user.Client,
+		},
+		{
+			name:     "succes repost",
+			post:     repost,
+			err:      nil,
+			id:       id1.Hex(),
+			oID:      id1,
+			timesID1: 1,
+			times:    1,
+			timesInc: 1,
+			timesDel: 1,
 			role:     user.Client,
 		},
 		{
@@ -459,8 +591,10 @@ func TestUserService_Delete(t *testing.T) {
 			err:      nil,
 			id:       id1.Hex(),
 			oID:      id1,
-			timesID1: 0,
+			timesID1: 1,
 			times:    1,
+			timesDel: 1,
+			timesAud: 1,
 			role:     user.Admin,
 		},
 		{
@@ -478,7 +612,7 @@ func TestUserService_Delete(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				Delete(gomock.Any(), test.oID).
+				Delete(gomock.Any(), test.oID, gomock.Any()).
 				Return(test.err).
 				Times(test.times)
 			m.
@@ -486,9 +620,32 @@ func TestUserService_Delete(t *testing.T) {
 				GetByID(gomock.Any(), test.oID).
 				Return(test.post, nil).
 				Times(test.timesID1)
+			m.
+				EXPECT().
+				IncReposts(gomock.Any(), test.post.RepostOf, -1).
+				Return(nil).
+				Times(test.timesInc)
+			m.
+				EXPECT().
+				DeleteReposts(gomock.Any(), test.oID, gomock.Any()).
+				Return(nil).
+				Times(test.timesDel)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					// The deleted post is kept in the entry.
+					assert.Equal(t, audit.DeletePost, e.Action)
+					assert.Equal(t, test.oID, e.TargetID)
+					assert.Equal(t, test.post.Description, e.Before["description"])
+					assert.Nil(t, e.After)
+					return nil
+				}).
+				Times(test.timesAud)
 
 			s := PostService{
 				repository: m,
+				auditor:    am,
 				log:        l,
 			}
 
@@ -498,99 +655,186 @@ func TestUserService_Delete(t *testing.T) {
 		})
 	}
 }
-func TestUserService_AddLike(t *testing.T) {
-	ctrl := gomock.NewController(t)
-
-	defer ctrl.Finish()
-	m := mock.NewMockRepository(ctrl)
-	id1 := primitive.NewObjectID()
-	id2 := primitive.NewObjectID()
+func TestPostService_Restore(t *testing.T) {
+	deletedAt := time.Now()
 	p := post.Post{
 		ID:          primitive.NewObjectID(),
-		UserID:      id2,
+		UserID:      primitive.NewObjectID(),
 		Description: "contend bla bla bla, bla",
+		DeletedAt:   &deletedAt,
+	}
+	repost := post.Post{
+		ID:        primitive.NewObjectID(),
+		UserID:    p.UserID,
+		RepostOf:  primitive.NewObjectID(),
+		DeletedAt: &deletedAt,
 	}
 
 	ctx := context.Background()
 	l := logger.NewMock()
 
 	tests := []struct {
-		name     string
-		post     post.Post
-		err      error
-		id       string
-		oID      primitive.ObjectID
-		times    int
-		timesID1 int
-		role     user.Role
+		name       string
+		id         string
+		deleted    post.Post
+		restoreErr error
+		err        error
+		timesRes   int
+		timesInc   int
+		timesAfter int
 	}{
 		{
-			name:     "succes",
-			post:     p,
-			err:      nil,
-			id:       id1.Hex(),
-			oID:      id1,
-			timesID1: 1,
-			times:    1,
-			role:     user.Client,
+			name:       "succes",
+			id:         p.ID.Hex(),
+			deleted:    p,
+			timesRes:   1,
+			timesAfter: 1,
 		},
 		{
-			name:     "failure bad id",
-			post:     post.Post{},
-			err:      response.ErrInvalidID,
-			id:       "1234",
-			oID:      id1,
-			timesID1: 0,
-			times:    0,
-			role:     user.Client,
+			name:       "succes repost",
+			id:         repost.ID.Hex(),
+			deleted:    repost,
+			timesRes:   1,
+			timesInc:   1,
+			timesAfter: 1,
 		},
 		{
-			name:     "failure internal error",
-			post:     post.Post{},
-			err:      response.ErrorInternalServerError,
-			id:       id1.Hex(),
-			oID:      id1,
-			times:    1,
-			timesID1: 0,
-			role:     user.Client,
+			name:       "failure not deleted",
+			id:         p.ID.Hex(),
+			deleted:    p,
+			restoreErr: response.ErrorNotFound,
+			err:        response.ErrorNotFound,
+			timesRes:   1,
+		},
+		{
+			name:    "failure bad id",
+			id:      "1234",
+			deleted: p,
+			err:     response.ErrInvalidID,
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+			am := amock.NewMockRecorder(ctrl)
+
 			m.
 				EXPECT().
-				AddLike(gomock.Any(), test.oID, id2).
-				Return(test.err).
-				Times(test.times)
+				Restore(gomock.Any(), test.deleted.ID).
+				Return(test.deleted, test.restoreErr).
+				Times(test.timesRes)
 			m.
 				EXPECT().
-				GetByID(gomock.Any(), id2).
-				Return(test.post, nil).
-				Times(test.timesID1)
+				IncReposts(gomock.Any(), test.deleted.RepostOf, 1).
+				Return(nil).
+				Times(test.timesInc)
+			m.
+				EXPECT().
+				RestoreReposts(gomock.Any(), test.deleted.ID, deletedAt).
+				Return(nil).
+				Times(test.timesAfter)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), test.deleted.ID).
+				Return(test.deleted, nil).
+				Times(test.timesAfter)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					assert.Equal(t, audit.RestorePost, e.Action)
+					assert.Equal(t, test.deleted.ID, e.TargetID)
+					assert.Nil(t, e.Before)
+					assert.NotNil(t, e.After)
+					return nil
+				}).
+				Times(test.timesAfter)
 
 			s := PostService{
 				repository: m,
+				auditor:    am,
 				log:        l,
 			}
 
-			resultPosts, err := s.AddLike(ctx, test.id, id2.Hex())
-			assert.Equal(t, err, test.err)
-			assert.Equal(t, resultPosts, test.post)
+			_, err := s.Restore(ctx, test.id)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestPostService_GetByIDMyReaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	pID := primitive.NewObjectID()
+	viewerID := primitive.NewObjectID()
+
+	p := post.Post{
+		ID:          pID,
+		Description: "contend bla bla bla, bla",
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name      string
+		reactions map[primitive.ObjectID]reaction.Type
+		want      reaction.Type
+	}{
+		{
+			name:      "reacted",
+			reactions: map[primitive.ObjectID]reaction.Type{pID: reaction.Love},
+			want:      reaction.Love,
+		},
+		{
+			name:      "not reacted",
+			reactions: map[primitive.ObjectID]reaction.Type{},
+			want:      "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), pID).
+				Return(p, nil).
+				Times(1)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, viewerID, []primitive.ObjectID{pID}).
+				Return(test.reactions, nil).
+				Times(1)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				log:        l,
+			}
 
+			resultPost, err := s.GetByID(ctx, pID.Hex(), viewerID.Hex())
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, resultPost.MyReaction)
 		})
 	}
 }
-func TestUserService_DeleteLike(t *testing.T) {
+
+func TestPostService_GetByIDDraft(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	defer ctrl.Finish()
 	m := mock.NewMockRepository(ctrl)
-	id1 := primitive.NewObjectID()
-	id2 := primitive.NewObjectID()
+	rm := rmock.NewMockRepository(ctrl)
+	authorID := primitive.NewObjectID()
 	p := post.Post{
 		ID:          primitive.NewObjectID(),
-		UserID:      id2,
+		UserID:      authorID,
 		Description: "contend bla bla bla, bla",
+		Status:      post.Draft,
 	}
 
 	ctx := context.Background()
@@ -598,67 +842,1171 @@ func TestUserService_DeleteLike(t *testing.T) {
 
 	tests := []struct {
 		name     string
-		post     post.Post
+		viewerID string
 		err      error
-		id       string
-		oID      primitive.ObjectID
 		times    int
-		timesID1 int
-		role     user.Role
 	}{
 		{
-			name:     "succes",
-			post:     p,
-			err:      nil,
-			id:       id1.Hex(),
-			oID:      id1,
-			timesID1: 1,
+			name:     "succes author",
+			viewerID: authorID.Hex(),
 			times:    1,
-			role:     user.Client,
 		},
 		{
-			name:     "failure bad id",
-			post:     post.Post{},
-			err:      response.ErrInvalidID,
-			id:       "1234",
-			oID:      id1,
-			timesID1: 0,
-			times:    0,
-			role:     user.Client,
+			name:     "failure other user",
+			viewerID: primitive.NewObjectID().Hex(),
+			err:      response.ErrorNotFound,
 		},
 		{
-			name:     "failure internal error",
-			post:     post.Post{},
-			err:      response.ErrorInternalServerError,
-			id:       id1.Hex(),
-			oID:      id1,
-			times:    1,
-			timesID1: 0,
-			role:     user.Client,
+			name:     "failure anonymous",
+			viewerID: "",
+			err:      response.ErrorNotFound,
 		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				DeleteLike(gomock.Any(), test.oID, id2).
-				Return(test.err).
+				GetByID(gomock.Any(), p.ID).
+				Return(p, nil).
+				Times(1)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, authorID, []primitive.ObjectID{p.ID}).
+				Return(map[primitive.ObjectID]reaction.Type{}, nil).
 				Times(test.times)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				log:        l,
+			}
+
+			_, err := s.GetByID(ctx, p.ID.Hex(), test.viewerID)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestPostService_UpdateStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	am := amock.NewMockRecorder(ctrl)
+	authorID := primitive.NewObjectID()
+	draft := post.Post{
+		ID:     primitive.NewObjectID(),
+		UserID: authorID,
+		Status: post.Draft,
+	}
+	published := post.Post{
+		ID:     draft.ID,
+		UserID: authorID,
+		Status: post.Published,
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name   string
+		stored post.Post
+		status post.Status
+		saved  post.Status
+		err    error
+		times  int
+	}{
+		{
+			name:   "succes publish draft",
+			stored: draft,
+			status: post.Published,
+			saved:  post.Published,
+			times:  1,
+		},
+		{
+			name:   "succes keep published",
+			stored: published,
+			status: post.Published,
+			saved:  "",
+			times:  1,
+		},
+		{
+			name:   "failure unpublish",
+			stored: published,
+			status: post.Draft,
+			err:    post.ErrInvalidStatus,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				GetByID(gomock.Any(), id2).
-				Return(test.post, nil).
-				Times(test.timesID1)
+				GetByID(gomock.Any(), draft.ID).
+				Return(test.stored, nil).
+				Times(1 + test.times)
+			m.
+				EXPECT().
+				Update(gomock.Any(), draft.ID, gomock.Any(), patch.AnyVersion).
+				DoAndReturn(func(_ context.Context, _ primitive.ObjectID, p *post.Post, _ int64) error {
+					assert.Equal(t, test.saved, p.Status)
+					return nil
+				}).
+				Times(test.times)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, authorID, []primitive.ObjectID{draft.ID}).
+				Return(map[primitive.ObjectID]reaction.Type{}, nil).
+				Times(test.times)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				Return(nil).
+				Times(test.times)
 
 			s := PostService{
 				repository: m,
+				reactions:  rm,
+				auditor:    am,
 				log:        l,
 			}
 
-			resultPosts, err := s.DeleteLike(ctx, test.id, id2.Hex())
-			assert.Equal(t, err, test.err)
-			assert.Equal(t, resultPosts, test.post)
+			_, err := s.Update(ctx, draft.ID.Hex(), authorID.Hex(), user.Admin, &post.Post{Status: test.status}, patch.AnyVersion)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestPostService_React(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	userID := primitive.NewObjectID()
+	postID := primitive.NewObjectID()
+	p := post.Post{
+		ID:          postID,
+		UserID:      primitive.NewObjectID(),
+		Description: "contend bla bla bla, bla",
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name        string
+		userID      string
+		reaction    reaction.Type
+		previous    reaction.Type
+		counts      map[reaction.Type]int
+		getErr      error
+		setErr      error
+		err         error
+		timesGet    int
+		timesSet    int
+		timesInc    int
+		timesResult int
+	}{
+		{
+			name:        "succes new reaction",
+			userID:      userID.Hex(),
+			reaction:    reaction.Like,
+			counts:      map[reaction.Type]int{reaction.Like: 1},
+			timesGet:    1,
+			timesSet:    1,
+			timesInc:    1,
+			timesResult: 1,
+		},
+		{
+			name:        "succes change reaction",
+			userID:      userID.Hex(),
+			reaction:    reaction.Love,
+			previous:    reaction.Like,
+			counts:      map[reaction.Type]int{reaction.Love: 1, reaction.Like: -1},
+			timesGet:    1,
+			timesSet:    1,
+			timesInc:    1,
+			timesResult: 1,
+		},
+		{
+			name:        "succes same reaction",
+			userID:      userID.Hex(),
+			reaction:    reaction.Wow,
+			previous:    reaction.Wow,
+			timesGet:    1,
+			timesSet:    1,
+			timesResult: 1,
+		},
+		{
+			name:     "failure invalid type",
+			userID:   userID.Hex(),
+			reaction: reaction.Type("meh"),
+			err:      reaction.ErrInvalidType,
+		},
+		{
+			name:     "failure bad id",
+			userID:   "1234",
+			reaction: reaction.Like,
+			err:      response.ErrInvalidID,
+		},
+		{
+			name:     "failure not found",
+			userID:   userID.Hex(),
+			reaction: reaction.Like,
+			getErr:   response.ErrorNotFound,
+			err:      response.ErrorNotFound,
+			timesGet: 1,
+		},
+		{
+			name:     "failure internal error",
+			userID:   userID.Hex(),
+			reaction: reaction.Like,
+			setErr:   response.ErrorInternalServerError,
+			err:      response.ErrorInternalServerError,
+			timesGet: 1,
+			timesSet: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), postID).
+				Return(p, test.getErr).
+				Times(test.timesGet)
+			rm.
+				EXPECT().
+				Set(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, re *reaction.Reaction) (reaction.Type, error) {
+					assert.Equal(t, reaction.Post, re.Target)
+					assert.Equal(t, postID, re.TargetID)
+					assert.Equal(t, userID, re.UserID)
+					assert.Equal(t, test.reaction, re.Type)
+					return test.previous, test.setErr
+				}).
+				Times(test.timesSet)
+			m.
+				EXPECT().
+				IncReactions(gomock.Any(), postID, test.counts).
+				Return(nil).
+				Times(test.timesInc)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), postID).
+				Return(p, nil).
+				Times(test.timesResult)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, userID, []primitive.ObjectID{postID}).
+				Return(map[primitive.ObjectID]reaction.Type{postID: test.reaction}, nil).
+				Times(test.timesResult)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				log:        l,
+			}
+
+			resultPost, err := s.React(ctx, test.userID, postID.Hex(), test.reaction)
+			assert.Equal(t, test.err, err)
+			if test.err == nil {
+				assert.Equal(t, test.reaction, resultPost.MyReaction)
+			}
+		})
+	}
+}
+
+func TestPostService_Unreact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	userID := primitive.NewObjectID()
+	postID := primitive.NewObjectID()
+	p := post.Post{
+		ID:          postID,
+		UserID:      primitive.NewObjectID(),
+		Description: "contend bla bla bla, bla",
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name        string
+		userID      string
+		post        post.Post
+		previous    reaction.Type
+		deleteErr   error
+		err         error
+		timesDelete int
+		timesInc    int
+		timesResult int
+	}{
+		{
+			name:        "succes",
+			userID:      userID.Hex(),
+			post:        p,
+			previous:    reaction.Sad,
+			timesDelete: 1,
+			timesInc:    1,
+			timesResult: 1,
+		},
+		{
+			name:        "succes without reaction",
+			userID:      userID.Hex(),
+			post:        p,
+			deleteErr:   reaction.ErrNoReaction,
+			timesDelete: 1,
+			timesResult: 1,
+		},
+		{
+			name:   "failure bad id",
+			userID: "1234",
+			post:   post.Post{},
+			err:    response.ErrInvalidID,
+		},
+		{
+			name:        "failure internal error",
+			userID:      userID.Hex(),
+			post:        post.Post{},
+			deleteErr:   response.ErrorInternalServerError,
+			err:         response.ErrorInternalServerError,
+			timesDelete: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rm.
+				EXPECT().
+				Delete(gomock.Any(), reaction.Post, postID, userID).
+				Return(test.previous, test.deleteErr).
+				Times(test.timesDelete)
+			m.
+				EXPECT().
+				IncReactions(gomock.Any(), postID, map[reaction.Type]int{test.previous: -1}).
+				Return(nil).
+				Times(test.timesInc)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), postID).
+				Return(p, nil).
+				Times(test.timesResult)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, userID, []primitive.ObjectID{postID}).
+				Return(map[primitive.ObjectID]reaction.Type{}, nil).
+				Times(test.timesResult)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				log:        l,
+			}
+
+			resultPost, err := s.Unreact(ctx, test.userID, postID.Hex())
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.post, resultPost)
+		})
+	}
+}
+
+func TestPostService_Repost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	userID := primitive.NewObjectID()
+	original := post.Post{
+		ID:          primitive.NewObjectID(),
+		UserID:      primitive.NewObjectID(),
+		Description: "contend bla bla bla, bla",
+	}
+	otherRepost := post.Post{
+		ID:       primitive.NewObjectID(),
+		UserID:   primitive.NewObjectID(),
+		RepostOf: original.ID,
+		Original: &original,
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name        string
+		userID      string
+		post        post.Post
+		quote       string
+		repostErr   error
+		err         error
+		timesGet    int
+		timesRepost int
+		timesCreate int
+		timesResult int
+	}{
+		{
+			name:        "succes",
+			userID:      userID.Hex(),
+			post:        original,
+			repostErr:   response.ErrorNotFound,
+			timesGet:    1,
+			timesRepost: 1,
+			timesCreate: 1,
+			timesResult: 1,
+		},
+		{
+			name:        "succes quote",
+			userID:      userID.Hex(),
+			post:        original,
+			quote:       "look at this",
+			timesGet:    1,
+			timesCreate: 1,
+			timesResult: 1,
+		},
+		{
+			name:        "succes repost of a repost",
+			userID:      userID.Hex(),
+			post:        otherRepost,
+			repostErr:   response.ErrorNotFound,
+			timesGet:    1,
+			timesRepost: 1,
+			timesCreate: 1,
+			timesResult: 1,
+		},
+		{
+			name:        "failure already reposted",
+			userID:      userID.Hex(),
+			post:        original,
+			err:         post.ErrAlreadyReposted,
+			timesGet:    1,
+			timesRepost: 1,
+		},
+		{
+			name:   "failure bad id",
+			userID: "1234",
+			post:   original,
+			err:    response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), test.post.ID).
+				Return(test.post, nil).
+				Times(test.timesGet)
+			m.
+				EXPECT().
+				GetRepost(gomock.Any(), userID, original.ID).
+				Return(post.Post{}, test.repostErr).
+				Times(test.timesRepost)
+			m.
+				EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				Return(nil).
+				Times(test.timesCreate)
+			m.
+				EXPECT().
+				IncReposts(gomock.Any(), original.ID, 1).
+				Return(nil).
+				Times(test.timesCreate)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), gomock.Not(test.post.ID)).
+				DoAndReturn(func(_ context.Context, id primitive.ObjectID) (post.Post, error) {
+					return post.Post{
+						ID:          id,
+						UserID:      userID,
+						Description: test.quote,
+						RepostOf:    original.ID,
+						Original:    &original,
+					}, nil
+				}).
+				Times(test.timesResult)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, userID, gomock.Any()).
+				Return(map[primitive.ObjectID]reaction.Type{}, nil).
+				Times(test.timesResult)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				log:        l,
+			}
+
+			resultPost, err := s.Repost(ctx, test.userID, test.post.ID.Hex(), test.quote)
+			assert.Equal(t, test.err, err)
+			if test.err == nil {
+				assert.Equal(t, original.ID, resultPost.RepostOf)
+				assert.Equal(t, test.quote, resultPost.Description)
+			}
+		})
+	}
+}
+
+func TestPostService_Unrepost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	userID := primitive.NewObjectID()
+	postID := primitive.NewObjectID()
+	p := post.Post{
+		ID:           postID,
+		UserID:       primitive.NewObjectID(),
+		Description:  "contend bla bla bla, bla",
+		RepostsCount: 1,
+	}
+	repost := post.Post{
+		ID:       primitive.NewObjectID(),
+		UserID:   userID,
+		RepostOf: postID,
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name         string
+		userID       string
+		postID       primitive.ObjectID
+		post         post.Post
+		repostErr    error
+		err          error
+		timesGet     int
+		timesGetRepo int
+		timesRepost  int
+		timesDelete  int
+	}{
+		{
+			name:        "succes",
+			userID:      userID.Hex(),
+			postID:      postID,
+			post:        p,
+			timesGet:    1,
+			timesRepost: 1,
+			timesDelete: 1,
+		},
+		{
+			name:         "succes repost id",
+			userID:       userID.Hex(),
+			postID:       repost.ID,
+			post:         p,
+			timesGetRepo: 1,
+			timesRepost:  1,
+			timesDelete:  1,
+		},
+		{
+			name:        "failure not reposted",
+			userID:      userID.Hex(),
+			postID:      postID,
+			post:        post.Post{},
+			repostErr:   response.ErrorNotFound,
+			err:         response.ErrorNotFound,
+			timesGet:    1,
+			timesRepost: 1,
+		},
+		{
+			name:   "failure bad id",
+			userID: "1234",
+			postID: postID,
+			post:   post.Post{},
+			err:    response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), postID).
+				Return(p, nil).
+				Times(test.timesGet)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), repost.ID).
+				Return(repost, nil).
+				Times(test.timesGetRepo)
+			m.
+				EXPECT().
+				GetRepost(gomock.Any(), userID, postID).
+				Return(repost, test.repostErr).
+				Times(test.timesRepost)
+			m.
+				EXPECT().
+				Delete(gomock.Any(), repost.ID, gomock.Any()).
+				Return(nil).
+				Times(test.timesDelete)
+			m.
+				EXPECT().
+				IncReposts(gomock.Any(), postID, -1).
+				Return(nil).
+				Times(test.timesDelete)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), postID).
+				Return(p, nil).
+				Times(test.timesDelete)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, userID, []primitive.ObjectID{postID}).
+				Return(map[primitive.ObjectID]reaction.Type{}, nil).
+				Times(test.timesDelete)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				log:        l,
+			}
+
+			resultPost, err := s.Unrepost(ctx, test.userID, test.postID.Hex())
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.post, resultPost)
+		})
+	}
+}
+
+func TestPostService_GetReactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	postID := primitive.NewObjectID()
+	viewerID := primitive.NewObjectID()
+	users := []user.User{
+		{ID: primitive.NewObjectID(), FirstName: "user"},
+	}
+	posts := []post.Post{{ID: postID}}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name       string
+		id         string
+		reaction   reaction.Type
+		page       int
+		limit      int
+		skip       int64
+		posts      []post.Post
+		err        error
+		timesPosts int
+		times      int
+	}{
+		{
+			name:       "succes",
+			id:         postID.Hex(),
+			reaction:   reaction.Like,
+			page:       3,
+			limit:      10,
+			skip:       20,
+			posts:      posts,
+			timesPosts: 1,
+			times:      1,
+		},
+		{
+			name:       "succes every type and invalid page",
+			id:         postID.Hex(),
+			page:       0,
+			limit:      10,
+			skip:       0,
+			posts:      posts,
+			timesPosts: 1,
+			times:      1,
+		},
+		{
+			name:       "failure post not visible",
+			id:         postID.Hex(),
+			reaction:   reaction.Like,
+			page:       1,
+			limit:      10,
+			posts:      []post.Post{},
+			err:        response.ErrorNotFound,
+			timesPosts: 1,
+		},
+		{
+			name:     "failure invalid type",
+			id:       postID.Hex(),
+			reaction: reaction.Type("meh"),
+			err:      reaction.ErrInvalidType,
+		},
+		{
+			name: "failure bad id",
+			id:   "1234",
+			err:  response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByIDs(gomock.Any(), []primitive.ObjectID{postID}, viewerID).
+				Return(test.posts, nil).
+				Times(test.timesPosts)
+			rm.
+				EXPECT().
+				GetUsers(gomock.Any(), reaction.Post, postID, test.reaction, test.skip, int64(test.limit)).
+				Return(users, int64(21), nil).
+				Times(test.times)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				log:        l,
+			}
+
+			resultUsers, total, err := s.GetReactions(ctx, test.id, viewerID.Hex(), test.reaction, test.page, test.limit)
+			assert.Equal(t, test.err, err)
+			if test.err == nil {
+				assert.Equal(t, users, resultUsers)
+				assert.Equal(t, 21, total)
+			}
+		})
+	}
+}
+
+func TestPostService_UpdateRevision(t *testing.T) {
+	authorID := primitive.NewObjectID()
+	stored := post.Post{
+		ID:          primitive.NewObjectID(),
+		UserID:      authorID,
+		Description: "before",
+		Pictures:    []picture.Picture{{ID: primitive.NewObjectID(), Key: "posts/1/2.png", Status: picture.Pending}},
+	}
+	draft := stored
+	draft.Status = post.Draft
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name        string
+		stored      post.Post
+		description string
+		edited      bool
+		times       int
+	}{
+		{
+			name:        "succes edited",
+			stored:      stored,
+			description: "after",
+			edited:      true,
+			times:       1,
+		},
+		{
+			name:        "succes draft not marked as edited",
+			stored:      draft,
+			description: "after",
+			times:       1,
+		},
+		{
+			name:        "succes without changes",
+			stored:      stored,
+			description: "before",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+			rm := rmock.NewMockRepository(ctrl)
+			vm := mock.NewMockRevisionRepository(ctrl)
+
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), stored.ID).
+				Return(test.stored, nil).
+				Times(2)
+			m.
+				EXPECT().
+				Update(gomock.Any(), stored.ID, gomock.Any(), patch.AnyVersion).
+				DoAndReturn(func(_ context.Context, _ primitive.ObjectID, p *post.Post, _ int64) error {
+					assert.Equal(t, test.edited, p.EditedAt != nil)
+					assert.Equal(t, stored.Pictures, p.Pictures)
+					return nil
+				})
+			vm.
+				EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, rev *post.Revision) error {
+					assert.Equal(t, stored.ID, rev.PostID)
+					assert.Equal(t, authorID, rev.UserID)
+					assert.Equal(t, map[string]post.Change{"description": {From: "before", To: test.description}}, rev.Changes)
+					return nil
+				}).
+				Times(test.times)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, authorID, gomock.Any()).
+				Return(nil, nil)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				revisions:  vm,
+				log:        l,
+			}
+
+			// The pictures sent are ignored.
+			forged := []picture.Picture{{URL: "http://example.com/forged.png", Status: picture.Ready}}
+			_, err := s.Update(ctx, stored.ID.Hex(), authorID.Hex(), user.Client, &post.Post{Description: test.description, Pictures: forged}, patch.AnyVersion)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestPostService_Patch(t *testing.T) {
+	authorID := primitive.NewObjectID()
+	stored := post.Post{
+		ID:          primitive.NewObjectID(),
+		UserID:      authorID,
+		Description: "before",
+		Pictures:    []picture.Picture{{ID: primitive.NewObjectID(), Key: "posts/1/2.png"}},
+		Status:      post.Published,
+		Version:     3,
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name        string
+		patch       string
+		version     int64
+		err         error
+		timesGet    int
+		timesUpdate int
+	}{
+		{
+			name:        "succes",
+			patch:       `{"description":"after"}`,
+			version:     3,
+			timesGet:    3,
+			timesUpdate: 1,
+		},
+		{
+			name:     "failure stale version",
+			patch:    `{"description":"after"}`,
+			version:  2,
+			err:      response.ErrPreconditionFailed,
+			timesGet: 2,
+		},
+		{
+			name:     "failure field not editable",
+			patch:    `{"user_id":"5f2b8a0e9d1e8c0001a1b2c3"}`,
+			version:  patch.AnyVersion,
+			err:      &patch.FieldError{Field: "user_id", Reason: "cannot be changed"},
+			timesGet: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+			rm := rmock.NewMockRepository(ctrl)
+			vm := mock.NewMockRevisionRepository(ctrl)
+
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), stored.ID).
+				Return(stored, nil).
+				Times(test.timesGet)
+			m.
+				EXPECT().
+				Update(gomock.Any(), stored.ID, gomock.Any(), test.version).
+				DoAndReturn(func(_ context.Context, _ primitive.ObjectID, p *post.Post, _ int64) error {
+					// The fields out of the patch are kept, even the hidden.
+					assert.Equal(t, "after", p.Description)
+					assert.Equal(t, stored.Pictures, p.Pictures)
+					return nil
+				}).
+				Times(test.timesUpdate)
+			vm.
+				EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, rev *post.Revision) error {
+					assert.Len(t, rev.Changes, 1)
+					return nil
+				}).
+				Times(test.timesUpdate)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, authorID, gomock.Any()).
+				Return(nil, nil).
+				Times(test.timesUpdate)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				revisions:  vm,
+				log:        l,
+			}
+
+			_, err := s.Patch(ctx, stored.ID.Hex(), authorID.Hex(), user.Client, []byte(test.patch), test.version)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestPostService_GetRevisions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	vm := mock.NewMockRevisionRepository(ctrl)
+	authorID := primitive.NewObjectID()
+	p := post.Post{
+		ID:     primitive.NewObjectID(),
+		UserID: authorID,
+	}
+	revisions := []post.Revision{
+		{ID: primitive.NewObjectID(), PostID: p.ID, UserID: authorID},
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name    string
+		id      string
+		userID  string
+		role    user.Role
+		page    int
+		limit   int
+		skip    int64
+		err     error
+		timesID int
+		times   int
+	}{
+		{
+			name:    "succes author",
+			id:      p.ID.Hex(),
+			userID:  authorID.Hex(),
+			role:    user.Client,
+			page:    2,
+			limit:   10,
+			skip:    10,
+			timesID: 1,
+			times:   1,
+		},
+		{
+			name:    "succes admin",
+			id:      p.ID.Hex(),
+			userID:  primitive.NewObjectID().Hex(),
+			role:    user.Admin,
+			page:    0,
+			limit:   10,
+			skip:    0,
+			timesID: 1,
+			times:   1,
+		},
+		{
+			name:    "failure unauthorized",
+			id:      p.ID.Hex(),
+			userID:  primitive.NewObjectID().Hex(),
+			role:    user.Client,
+			err:     response.ErrorUnauthorized,
+			timesID: 1,
+		},
+		{
+			name: "failure bad id",
+			id:   "1234",
+			err:  response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), p.ID).
+				Return(p, nil).
+				Times(test.timesID)
+			vm.
+				EXPECT().
+				GetAll(gomock.Any(), p.ID, test.skip, int64(test.limit)).
+				Return(revisions, int64(11), nil).
+				Times(test.times)
+
+			s := PostService{
+				repository: m,
+				revisions:  vm,
+				log:        l,
+			}
+
+			result, total, err := s.GetRevisions(ctx, test.id, test.userID, test.role, test.page, test.limit)
+			assert.Equal(t, test.err, err)
+			if test.err == nil {
+				assert.Equal(t, revisions, result)
+				assert.Equal(t, 11, total)
+			}
+		})
+	}
+}
+
+func TestPostService_AddPicture(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+	m := mock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	sm := pmock.NewMockStorage(ctrl)
+	qm := pmock.NewMockQueue(ctrl)
+	id1 := primitive.NewObjectID()
+	id2 := primitive.NewObjectID()
+	p := post.Post{
+		ID:          id1,
+		UserID:      id2,
+		Description: "contend bla bla bla, bla",
+	}
+	otherUserPost := post.Post{
+		ID:          id1,
+		UserID:      primitive.NewObjectID(),
+		Description: "contend bla bla bla, bla",
+	}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name       string
+		post       post.Post
+		rpost      post.Post
+		err        error
+		id         string
+		role       user.Role
+		getErr     error
+		timesID1   int
+		timesSave  int
+		times      int
+		timesDel   int
+		timesQueue int
+		timesID2   int
+	}{
+		{
+			name:       "succes",
+			post:       p,
+			rpost:      p,
+			err:        nil,
+			id:         id1.Hex(),
+			role:       user.Client,
+			timesID1:   1,
+			timesSave:  1,
+			times:      1,
+			timesQueue: 1,
+			timesID2:   1,
+		},
+		{
+			name:  "failure bad id",
+			post:  post.Post{},
+			rpost: p,
+			err:   response.ErrInvalidID,
+			id:    "1234",
+			role:  user.Client,
+		},
+		{
+			name:     "failure unauthorized",
+			post:     post.Post{},
+			rpost:    otherUserPost,
+			err:      response.ErrorUnauthorized,
+			id:       id1.Hex(),
+			role:     user.Client,
+			timesID1: 1,
+		},
+		{
+			name:       "succes deferend userID with admin",
+			post:       otherUserPost,
+			rpost:      otherUserPost,
+			err:        nil,
+			id:         id1.Hex(),
+			role:       user.Admin,
+			timesID1:   1,
+			timesSave:  1,
+			times:      1,
+			timesQueue: 1,
+			timesID2:   1,
+		},
+		{
+			name:      "failure not found",
+			post:      post.Post{},
+			rpost:     p,
+			err:       response.ErrorNotFound,
+			id:        id1.Hex(),
+			role:      user.Client,
+			timesID1:  1,
+			timesSave: 1,
+			times:     1,
+			timesDel:  1,
+		},
+		{
+			name:     "failure not found with admin",
+			post:     post.Post{},
+			getErr:   response.ErrorNotFound,
+			err:      response.ErrorNotFound,
+			id:       id1.Hex(),
+			role:     user.Admin,
+			timesID1: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), id1).
+				Return(test.rpost, test.getErr).
+				Times(test.timesID1)
+			sm.
+				EXPECT().
+				Save(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return("http://localhost/media/picture.png", nil).
+				Times(test.timesSave)
+			sm.
+				EXPECT().
+				Delete(gomock.Any(), gomock.Any()).
+				Return(nil).
+				Times(test.timesDel)
+			m.
+				EXPECT().
+				AddPicture(gomock.Any(), id1, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ primitive.ObjectID, pic picture.Picture) error {
+					assert.Equal(t, picture.Pending, pic.Status)
+					assert.Equal(t, "http://localhost/media/picture.png", pic.URL)
+					return test.err
+				}).
+				Times(test.times)
+			qm.
+				EXPECT().
+				Enqueue(gomock.Any()).
+				Return(nil).
+				Times(test.timesQueue)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), id1).
+				Return(test.post, nil).
+				Times(test.timesID2)
+			rm.
+				EXPECT().
+				GetUserReactions(gomock.Any(), reaction.Post, id2, []primitive.ObjectID{id1}).
+				Return(nil, nil).
+				Times(test.timesID2)
+
+			s := PostService{
+				repository: m,
+				reactions:  rm,
+				storage:    sm,
+				queue:      qm,
+				log:        l,
+			}
 
+			resultPost, err := s.AddPicture(ctx, test.id, id2.Hex(), test.role, ".png", strings.NewReader("picture"))
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.post, resultPost)
 		})
 	}
 }
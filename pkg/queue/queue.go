@@ -0,0 +1,110 @@
+package queue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// Errors.
+var (
+	ErrFull   = response.NewError(response.Unavailable, "queue is full")
+	ErrClosed = response.NewError(response.Unavailable, "queue is closed")
+)
+
+// Handler process a job of a Queue.
+type Handler func(job interface{})
+
+// Queue process the jobs in background with a fixed number of workers, the
+// jobs are dropped when the queue is full.
+type Queue struct {
+	jobs    chan interface{}
+	closing chan struct{}
+	handle  Handler
+	workers int
+
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Enqueue add a job to be processed.
+func (q *Queue) Enqueue(job interface{}) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return ErrFull
+	}
+}
+
+// Start launch the workers.
+func (q *Queue) Start(ctx context.Context) error {
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.run()
+	}
+
+	return nil
+}
+
+// Close stop receiving jobs and wait for the workers to finish the jobs
+// already enqueued.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.closing)
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Closing returns a channel closed once the queue is closing, for the
+// handlers to stop waiting.
+func (q *Queue) Closing() <-chan struct{} {
+	return q.closing
+}
+
+func (q *Queue) run() {
+	defer q.wg.Done()
+
+	for job := range q.jobs {
+		q.handle(job)
+	}
+}
+
+// New create a new Queue of size jobs processed by handle on n workers.
+func New(size, n int, handle Handler) *Queue {
+	if n < 1 {
+		n = 1
+	}
+
+	return &Queue{
+		jobs:    make(chan interface{}, size),
+		closing: make(chan struct{}),
+		handle:  handle,
+		workers: n,
+	}
+}
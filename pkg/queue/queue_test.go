@@ -0,0 +1,57 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_Enqueue(t *testing.T) {
+	block := make(chan struct{})
+	processed := make(chan interface{}, 2)
+
+	q := New(1, 1, func(job interface{}) {
+		<-block
+		processed <- job
+	})
+	assert.NoError(t, q.Start(context.Background()))
+
+	// The worker takes the first job and the second fills the queue.
+	assert.NoError(t, q.Enqueue(1))
+	assert.Eventually(t, func() bool { return len(q.jobs) == 0 }, time.Second, time.Millisecond)
+	assert.NoError(t, q.Enqueue(2))
+	assert.Equal(t, ErrFull, q.Enqueue(3))
+
+	close(block)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Close(ctx))
+
+	// The jobs already enqueued are processed before the close returns.
+	assert.Equal(t, 1, <-processed)
+	assert.Equal(t, 2, <-processed)
+	assert.Equal(t, ErrClosed, q.Enqueue(4))
+
+	select {
+	case <-q.Closing():
+	default:
+		t.Error("queue not closing")
+	}
+}
+
+func TestQueue_CloseTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	q := New(1, 1, func(job interface{}) {
+		<-block
+	})
+	assert.NoError(t, q.Start(context.Background()))
+	assert.NoError(t, q.Enqueue(1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, q.Close(ctx))
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/metrics"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// defaultScope is the bucket shared by the routes without a limit of
+// their own.
+const defaultScope = "*"
+
+// Limiter limits the requests of each user, or of each IP for the
+// requests without a token.
+type Limiter struct {
+	repo   Repository
+	def    Limit
+	routes map[string]Limit
+	log    logger.Logger
+}
+
+// Middleware limits the requests by their route, it goes after the Identify
+// of an auth.Authenticator and middleware.RealIP. The requests are let
+// through while the repository fails.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope := Route(r)
+		limit, ok := l.routes[scope]
+		if !ok {
+			scope, limit = defaultScope, l.def
+		}
+
+		key := scope + "|" + client(r)
+		res, err := l.repo.Take(r.Context(), key, limit)
+		if err != nil {
+			l.log.WithContext(r.Context()).Errorf("cannot limit %s: %v", key, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("X-RateLimit-Limit", strconv.Itoa(limit.Size()))
+		h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining()))
+		h.Set("X-RateLimit-Reset", seconds(res.Reset(limit)))
+
+		if !res.Allowed {
+			h.Set("Retry-After", seconds(res.RetryAfter(limit)))
+			_ = response.Error(w, ErrLimited)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Route returns the method and route pattern of a request, like
+// "POST /api/v1/post/", or empty for the requests without route. It is
+// known before the request is served, the pattern is the one of
+// metrics.Route once it is.
+func Route(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return ""
+	}
+
+	tctx := chi.NewRouteContext()
+	if !rctx.Routes.Match(tctx, r.Method, r.URL.Path) {
+		return ""
+	}
+
+	return r.Method + " " + metrics.Route(r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, tctx)))
+}
+
+// client returns the user of the token of r, or its IP.
+func client(r *http.Request) string {
+	if id, err := auth.GetID(r); err == nil {
+		return "user:" + id
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		// middleware.RealIP leaves the address without port.
+		ip = r.RemoteAddr
+	}
+
+	return "ip:" + ip
+}
+
+// seconds formats d in whole seconds, rounded up.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// New create a limiter of def requests, the routes override it with a
+// bucket of their own. The routes are the method and the pattern, as
+// Route returns them.
+func New(repo Repository, def Limit, routes map[string]Limit, log logger.Logger) *Limiter {
+	return &Limiter{
+		repo:   repo,
+		def:    def,
+		routes: routes,
+		log:    log,
+	}
+}
@@ -0,0 +1,201 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/auth"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/metrics"
+)
+
+// repository answers Take with result and err, and keeps the key and limit.
+type repository struct {
+	result Result
+	err    error
+	key    string
+	limit  Limit
+}
+
+func (r *repository) Take(ctx context.Context, key string, l Limit) (Result, error) {
+	r.key, r.limit = key, l
+	return r.result, r.err
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	def := Limit{Requests: 60, Period: time.Minute}
+	create := Limit{Requests: 10, Period: time.Minute, Burst: 5}
+	userID := primitive.NewObjectID()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		userID primitive.ObjectID
+		key    string
+		limit  Limit
+		result Result
+		err    error
+		code   int
+		header map[string]string
+	}{
+		{
+			name:   "Allowed route",
+			method: http.MethodPost,
+			path:   "/api/v1/post/",
+			key:    "POST /api/v1/post/|ip:192.0.2.1",
+			limit:  create,
+			result: Result{Allowed: true, Tokens: 3.5},
+			code:   http.StatusCreated,
+			header: map[string]string{"X-RateLimit-Limit": "5", "X-RateLimit-Remaining": "3", "X-RateLimit-Reset": "9"},
+		},
+		{
+			name:   "Allowed user default",
+			method: http.MethodGet,
+			path:   "/api/v1/post/" + userID.Hex(),
+			userID: userID,
+			key:    "*|user:" + userID.Hex(),
+			limit:  def,
+			result: Result{Allowed: true, Tokens: 59},
+			code:   http.StatusOK,
+			header: map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "59", "X-RateLimit-Reset": "1"},
+		},
+		{
+			name:   "Limited",
+			method: http.MethodPost,
+			path:   "/api/v1/post/",
+			userID: userID,
+			key:    "POST /api/v1/post/|user:" + userID.Hex(),
+			limit:  create,
+			result: Result{Tokens: 0.5},
+			code:   http.StatusTooManyRequests,
+			header: map[string]string{"X-RateLimit-Remaining": "0", "Retry-After": "3"},
+		},
+		{
+			name:   "Repository failure",
+			method: http.MethodPost,
+			path:   "/api/v1/post/",
+			key:    "POST /api/v1/post/|ip:192.0.2.1",
+			limit:  create,
+			err:    errors.New("connection refused"),
+			code:   http.StatusCreated,
+			header: map[string]string{"X-RateLimit-Limit": ""},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := &repository{result: test.result, err: test.err}
+			l := New(m, def, map[string]Limit{"POST /api/v1/post/": create}, logger.NewMock())
+
+			posts := chi.NewRouter()
+			posts.Post("/", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusCreated) })
+			posts.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+			v1 := chi.NewRouter()
+			v1.Use(l.Middleware)
+			v1.Mount("/post/", posts)
+
+			mux := chi.NewRouter()
+			mux.Mount("/api/v1", v1)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(test.method, test.path, nil)
+			r.RemoteAddr = "192.0.2.1:1234"
+			if !test.userID.IsZero() {
+				r = r.WithContext(context.WithValue(r.Context(), auth.IDKey, test.userID))
+			}
+
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.key, m.key)
+			assert.Equal(t, test.limit, m.limit)
+			assert.Equal(t, test.code, w.Code)
+			for k, v := range test.header {
+				assert.Equal(t, v, w.Header().Get(k), k)
+			}
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   string
+	}{
+		{
+			name:   "Mounted route",
+			method: http.MethodPost,
+			path:   "/api/v1/post/",
+			want:   "POST /api/v1/post/",
+		},
+		{
+			name:   "Route with parameter",
+			method: http.MethodGet,
+			path:   "/api/v1/post/1",
+			want:   "GET /api/v1/post/{id}",
+		},
+		{
+			name:   "Without route",
+			method: http.MethodGet,
+			path:   "/api/v1/unknown",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var before, after string
+			handler := func(w http.ResponseWriter, r *http.Request) { after = metrics.Route(r) }
+
+			posts := chi.NewRouter()
+			posts.Post("/", handler)
+			posts.Get("/{id}", handler)
+
+			v1 := chi.NewRouter()
+			v1.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					before = Route(r)
+					next.ServeHTTP(w, r)
+				})
+			})
+			v1.Mount("/post/", posts)
+
+			mux := chi.NewRouter()
+			mux.Mount("/api/v1", v1)
+
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(test.method, test.path, nil))
+
+			assert.Equal(t, test.want, before)
+			if test.want != "" {
+				// The limits and the metrics name the routes alike.
+				assert.Equal(t, test.method+" "+after, before)
+			}
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	l := Limit{Requests: 10, Period: time.Second, Burst: 20}
+
+	assert.Equal(t, 20, l.Size())
+	assert.Equal(t, 2*time.Second, l.Full())
+	assert.Equal(t, 6.0, l.Refill(1, 500*time.Millisecond))
+	assert.Equal(t, 20.0, l.Refill(19, time.Hour))
+	assert.Equal(t, 1.0, l.Refill(1, -time.Second))
+
+	r := Result{Tokens: 0.5}
+	assert.Equal(t, 0, r.Remaining())
+	assert.Equal(t, 50*time.Millisecond, r.RetryAfter(l))
+	assert.Equal(t, 1950*time.Millisecond, r.Reset(l))
+	assert.Equal(t, 10, Limit{Requests: 10, Period: time.Second}.Size())
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// Stores of the buckets.
+const (
+	Memory = "memory"
+	Mongo  = "mongo"
+	Redis  = "redis"
+)
+
+// MinPeriod is the shortest period of a limit, the buckets are refilled
+// by the millisecond.
+const MinPeriod = time.Millisecond
+
+// ErrLimited is the error of the requests over their limit.
+var ErrLimited = response.NewError(response.TooManyRequests, "too many requests")
+
+// Limit is a token bucket of Burst tokens refilled with Requests tokens
+// each Period, a request takes one token. Without Burst the bucket holds
+// Requests tokens.
+type Limit struct {
+	Requests int           `json:"requests" yaml:"requests"`
+	Period   time.Duration `json:"period" yaml:"period"`
+	Burst    int           `json:"burst" yaml:"burst"`
+}
+
+// Size returns the tokens of the bucket when full.
+func (l Limit) Size() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Requests
+}
+
+// Rate returns the tokens refilled each millisecond.
+func (l Limit) Rate() float64 {
+	return float64(l.Requests) / float64(l.Period/time.Millisecond)
+}
+
+// Refill returns the tokens of a bucket with tokens after elapsed.
+func (l Limit) Refill(tokens float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	return math.Min(float64(l.Size()), tokens+float64(elapsed/time.Millisecond)*l.Rate())
+}
+
+// Full returns the time a bucket without tokens takes to be full, the
+// buckets are dropped after it.
+func (l Limit) Full() time.Duration {
+	return l.wait(float64(l.Size()))
+}
+
+// wait returns the time to refill n tokens.
+func (l Limit) wait(n float64) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+
+	return time.Duration(math.Ceil(n/l.Rate())) * time.Millisecond
+}
+
+// Result of taking a token of a bucket.
+type Result struct {
+	Allowed bool
+	// Tokens left in the bucket.
+	Tokens float64
+}
+
+// Remaining returns the requests that can be done now.
+func (r Result) Remaining() int {
+	return int(math.Floor(r.Tokens))
+}
+
+// RetryAfter returns the time until the next request can be done.
+func (r Result) RetryAfter(l Limit) time.Duration {
+	return l.wait(1 - r.Tokens)
+}
+
+// Reset returns the time until the bucket is full again.
+func (r Result) Reset(l Limit) time.Duration {
+	return l.wait(float64(l.Size()) - r.Tokens)
+}
@@ -0,0 +1,10 @@
+package ratelimit
+
+import "context"
+
+// Repository storage of the buckets, shared by the replicas unless it is
+// in memory.
+type Repository interface {
+	// Take a token of the bucket of key, created full if it doesn't exist.
+	Take(ctx context.Context, key string, l Limit) (Result, error)
+}
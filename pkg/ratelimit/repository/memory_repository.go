@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Zucke/social_prove/pkg/ratelimit"
+)
+
+// sweepInterval is how often the full buckets are dropped.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	limit     ratelimit.Limit
+}
+
+// MemoryRepository keeps the buckets of this replica only.
+type MemoryRepository struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+// Take a token of the bucket of key.
+func (m *MemoryRepository) Take(ctx context.Context, key string, l ratelimit.Limit) (ratelimit.Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	m.sweep(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.Size()), updatedAt: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens = l.Refill(b.tokens, now.Sub(b.updatedAt))
+	b.updatedAt = now
+	b.limit = l
+
+	if b.tokens < 1 {
+		return ratelimit.Result{Tokens: b.tokens}, nil
+	}
+
+	b.tokens--
+	return ratelimit.Result{Allowed: true, Tokens: b.tokens}, nil
+}
+
+// sweep drop the buckets full by now, they are the same as new ones.
+func (m *MemoryRepository) sweep(now time.Time) {
+	if now.Sub(m.swept) < sweepInterval {
+		return
+	}
+	m.swept = now
+
+	for key, b := range m.buckets {
+		if now.Sub(b.updatedAt) >= b.limit.Full() {
+			delete(m.buckets, key)
+		}
+	}
+}
+
+// Memory create a new repository of the buckets in memory.
+func Memory() ratelimit.Repository {
+	return &MemoryRepository{
+		buckets: make(map[string]*bucket),
+		swept:   time.Now(),
+		now:     time.Now,
+	}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zucke/social_prove/pkg/ratelimit"
+)
+
+func TestMemoryRepository_Take(t *testing.T) {
+	now := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := Memory().(*MemoryRepository)
+	m.now = func() time.Time { return now }
+	m.swept = now
+
+	l := ratelimit.Limit{Requests: 1, Period: time.Second, Burst: 2}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		key     string
+		after   time.Duration
+		allowed bool
+		tokens  float64
+	}{
+		{name: "new bucket is full", key: "a", allowed: true, tokens: 1},
+		{name: "burst", key: "a", allowed: true, tokens: 0},
+		{name: "empty", key: "a", allowed: false, tokens: 0},
+		{name: "other key", key: "b", allowed: true, tokens: 1},
+		{name: "refilled", key: "a", after: 1500 * time.Millisecond, allowed: true, tokens: 0.5},
+		{name: "partially refilled", key: "a", after: 250 * time.Millisecond, allowed: false, tokens: 0.75},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now = now.Add(test.after)
+
+			res, err := m.Take(ctx, test.key, l)
+			assert.NoError(t, err)
+			assert.Equal(t, test.allowed, res.Allowed)
+			assert.InDelta(t, test.tokens, res.Tokens, 1e-9)
+		})
+	}
+
+	// The full buckets are dropped.
+	now = now.Add(time.Hour)
+	_, err := m.Take(ctx, "c", l)
+	assert.NoError(t, err)
+	assert.Len(t, m.buckets, 1)
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/ratelimit"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// duplicateKeyCode is the mongo error code of a unique index violation.
+const duplicateKeyCode = 11000
+
+// MongoRepository storage of the buckets, one document per key dropped by
+// the TTL index of expires_at once the bucket is full.
+type MongoRepository struct {
+	coll *mongo.Collection
+	log  logger.Logger
+}
+
+type mongoBucket struct {
+	Tokens  float64 `bson:"tokens"`
+	Allowed bool    `bson:"allowed"`
+}
+
+// Take a token of the bucket of key. The bucket is refilled and taken in
+// one update, with the clock of the server so the replicas agree.
+func (r *MongoRepository) Take(ctx context.Context, key string, l ratelimit.Limit) (ratelimit.Result, error) {
+	size := l.Size()
+	elapsed := bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$$NOW", bson.M{"$ifNull": bson.A{"$updated_at", "$$NOW"}}}}}}
+
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"tokens": bson.M{"$min": bson.A{
+				size,
+				bson.M{"$add": bson.A{
+					bson.M{"$ifNull": bson.A{"$tokens", size}},
+					bson.M{"$multiply": bson.A{elapsed, l.Rate()}},
+				}},
+			}},
+			"updated_at": "$$NOW",
+			"expires_at": bson.M{"$add": bson.A{"$$NOW", l.Full().Milliseconds()}},
+		}},
+		bson.M{"$set": bson.M{
+			"allowed": bson.M{"$gte": bson.A{"$tokens", 1}},
+		}},
+		bson.M{"$set": bson.M{
+			"tokens": bson.M{"$cond": bson.A{"$allowed", bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+		}},
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var b mongoBucket
+	err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&b)
+	if isDuplicateKey(err) {
+		// A concurrent request created the bucket first, it's there now.
+		err = r.coll.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&b)
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return ratelimit.Result{}, response.ErrorInternalServerError
+	}
+
+	return ratelimit.Result{Allowed: b.Allowed, Tokens: b.Tokens}, nil
+}
+
+// isDuplicateKey reports whether err is a unique index violation, a
+// findAndModify reports it as a command error.
+func isDuplicateKey(err error) bool {
+	var ce mongo.CommandError
+	if errors.As(err, &ce) {
+		return ce.Code == duplicateKeyCode
+	}
+
+	var we mongo.WriteException
+	if errors.As(err, &we) {
+		for _, e := range we.WriteErrors {
+			if e.Code == duplicateKeyCode {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Mongo create a new repository of the buckets in coll.
+func Mongo(coll *mongo.Collection, log logger.Logger) ratelimit.Repository {
+	return &MongoRepository{
+		coll: coll,
+		log:  log,
+	}
+}
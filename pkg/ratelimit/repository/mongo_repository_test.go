@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/ratelimit"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+func TestMongoRepository_Take(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	l := ratelimit.Limit{Requests: 1, Period: time.Second, Burst: 2}
+	duplicate := mtest.CreateCommandErrorResponse(mtest.CommandError{
+		Code:    11000,
+		Name:    "DuplicateKey",
+		Message: "E11000 duplicate key error",
+	})
+	bucket := mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+		{Key: "tokens", Value: 1.0},
+		{Key: "allowed", Value: true},
+	}})
+
+	tests := []struct {
+		name      string
+		responses []bson.D
+		result    ratelimit.Result
+		err       error
+	}{
+		{
+			name:      "taken",
+			responses: []bson.D{bucket},
+			result:    ratelimit.Result{Allowed: true, Tokens: 1},
+		},
+		{
+			// Another request created the bucket at the same time.
+			name:      "taken after a concurrent upsert",
+			responses: []bson.D{duplicate, bucket},
+			result:    ratelimit.Result{Allowed: true, Tokens: 1},
+		},
+		{
+			name:      "duplicate key retried once",
+			responses: []bson.D{duplicate, duplicate},
+			err:       response.ErrorInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			mt.AddMockResponses(test.responses...)
+			r := Mongo(mt.Coll, logger.NewMock())
+
+			res, err := r.Take(context.Background(), "user:1", l)
+			assert.Equal(mt, test.err, err)
+			assert.Equal(mt, test.result, res)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-redis/redis/v7"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/ratelimit"
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// keyPrefix namespaces the buckets in Redis.
+const keyPrefix = "ratelimit:"
+
+// take refills and takes a token of the bucket KEYS[1] of ARGV[1] tokens
+// refilled with ARGV[2] tokens a millisecond, with the clock of Redis. It
+// returns whether the token was taken and the tokens left as a string,
+// Lua numbers are returned truncated.
+var take = redis.NewScript(`
+redis.replicate_commands()
+local size = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated_at')
+local tokens = tonumber(bucket[1]) or size
+local updated = tonumber(bucket[2]) or now
+tokens = math.min(size, tokens + math.max(0, now - updated) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'updated_at', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(size / rate))
+return {allowed, tostring(tokens)}
+`)
+
+// RedisRepository storage of the buckets, one hash per key expired once the
+// bucket is full.
+type RedisRepository struct {
+	client *redis.Client
+	log    logger.Logger
+}
+
+// Take a token of the bucket of key, atomically in a script.
+func (r *RedisRepository) Take(ctx context.Context, key string, l ratelimit.Limit) (ratelimit.Result, error) {
+	client := r.client.WithContext(ctx)
+	v, err := take.Run(client, []string{keyPrefix + key}, l.Size(), l.Rate()).Result()
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return ratelimit.Result{}, response.ErrorInternalServerError
+	}
+
+	res, ok := v.([]interface{})
+	if !ok || len(res) != 2 {
+		r.log.WithContext(ctx).Errorf("unexpected rate limit script result %v", v)
+		return ratelimit.Result{}, response.ErrorInternalServerError
+	}
+
+	allowed, _ := res[0].(int64)
+	s, _ := res[1].(string)
+	tokens, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return ratelimit.Result{}, response.ErrorInternalServerError
+	}
+
+	return ratelimit.Result{Allowed: allowed == 1, Tokens: tokens}, nil
+}
+
+// Redis create a new repository of the buckets in Redis.
+func Redis(client *redis.Client, log logger.Logger) ratelimit.Repository {
+	return &RedisRepository{
+		client: client,
+		log:    log,
+	}
+}
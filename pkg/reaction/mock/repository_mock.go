@@ -0,0 +1,158 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Zucke/social_prove/pkg/reaction (interfaces: Repository)
+
+// Package mock_reaction is a generated GoMock package.
+package mock_reaction
+
+import (
+	context "context"
+	reaction "github.com/Zucke/social_prove/pkg/reaction"
+	user "github.com/Zucke/social_prove/pkg/user"
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	reflect "reflect"
+	time "time"
+)
+
+// MockRepository is a mock of Repository interface
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method
+func (m *MockRepository) Delete(arg0 context.Context, arg1 reaction.Target, arg2, arg3 primitive.ObjectID) (reaction.Type, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(reaction.Type)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0, arg1, arg2, arg3)
+}
+
+// DeleteAllForTarget mocks base method
+func (m *MockRepository) DeleteAllForTarget(arg0 context.Context, arg1 reaction.Target, arg2 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllForTarget", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAllForTarget indicates an expected call of DeleteAllForTarget
+func (mr *MockRepositoryMockRecorder) DeleteAllForTarget(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllForTarget", reflect.TypeOf((*MockRepository)(nil).DeleteAllForTarget), arg0, arg1, arg2)
+}
+
+// GetAllForUser mocks base method
+func (m *MockRepository) GetAllForUser(arg0 context.Context, arg1 primitive.ObjectID) ([]reaction.Reaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllForUser", arg0, arg1)
+	ret0, _ := ret[0].([]reaction.Reaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllForUser indicates an expected call of GetAllForUser
+func (mr *MockRepositoryMockRecorder) GetAllForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllForUser", reflect.TypeOf((*MockRepository)(nil).GetAllForUser), arg0, arg1)
+}
+
+// GetUserReactions mocks base method
+func (m *MockRepository) GetUserReactions(arg0 context.Context, arg1 reaction.Target, arg2 primitive.ObjectID, arg3 []primitive.ObjectID) (map[primitive.ObjectID]reaction.Type, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserReactions", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(map[primitive.ObjectID]reaction.Type)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserReactions indicates an expected call of GetUserReactions
+func (mr *MockRepositoryMockRecorder) GetUserReactions(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReactions", reflect.TypeOf((*MockRepository)(nil).GetUserReactions), arg0, arg1, arg2, arg3)
+}
+
+// GetUsers mocks base method
+func (m *MockRepository) GetUsers(arg0 context.Context, arg1 reaction.Target, arg2 primitive.ObjectID, arg3 reaction.Type, arg4, arg5 int64) ([]user.User, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]user.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUsers indicates an expected call of GetUsers
+func (mr *MockRepositoryMockRecorder) GetUsers(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockRepository)(nil).GetUsers), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// HideAllForUser mocks base method
+func (m *MockRepository) HideAllForUser(arg0 context.Context, arg1 primitive.ObjectID, arg2 time.Time) ([]reaction.Reaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideAllForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]reaction.Reaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HideAllForUser indicates an expected call of HideAllForUser
+func (mr *MockRepositoryMockRecorder) HideAllForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideAllForUser", reflect.TypeOf((*MockRepository)(nil).HideAllForUser), arg0, arg1, arg2)
+}
+
+// RestoreAllForUser mocks base method
+func (m *MockRepository) RestoreAllForUser(arg0 context.Context, arg1 primitive.ObjectID, arg2 time.Time) ([]reaction.Reaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreAllForUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]reaction.Reaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreAllForUser indicates an expected call of RestoreAllForUser
+func (mr *MockRepositoryMockRecorder) RestoreAllForUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreAllForUser", reflect.TypeOf((*MockRepository)(nil).RestoreAllForUser), arg0, arg1, arg2)
+}
+
+// Set mocks base method
+func (m *MockRepository) Set(arg0 context.Context, arg1 *reaction.Reaction) (reaction.Type, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", arg0, arg1)
+	ret0, _ := ret[0].(reaction.Type)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Set indicates an expected call of Set
+func (mr *MockRepositoryMockRecorder) Set(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockRepository)(nil).Set), arg0, arg1)
+}
@@ -0,0 +1,65 @@
+package reaction
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// Type of reaction.
+type Type string
+
+// Reaction types.
+const (
+	Like  Type = "like"
+	Love  Type = "love"
+	Laugh Type = "laugh"
+	Wow   Type = "wow"
+	Sad   Type = "sad"
+	Angry Type = "angry"
+)
+
+// Types all the valid reaction types.
+var Types = []Type{Like, Love, Laugh, Wow, Sad, Angry}
+
+// Target is the kind of content that receives the reaction.
+type Target string
+
+// Reaction targets.
+const (
+	Post    Target = "post"
+	Comment Target = "comment"
+)
+
+// Errors.
+var (
+	ErrInvalidType = response.NewError(response.Invalid, "invalid reaction type")
+	ErrNoReaction  = response.NewError(response.NotFound, "reaction not found")
+)
+
+// Reaction is a user reacting to a post or a comment, a user has
+// only one reaction per target. The reactions of a deleted user are
+// hidden, out of the counters, until it's restored or purged.
+type Reaction struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Target    Target             `json:"target,omitempty" bson:"target,omitempty"`
+	TargetID  primitive.ObjectID `json:"target_id,omitempty" bson:"target_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Type      Type               `json:"type,omitempty" bson:"type,omitempty"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+	UpdatedAt time.Time          `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	DeletedAt *time.Time         `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+}
+
+// Valid reports whether t is a known reaction type.
+func (t Type) Valid() bool {
+	for _, v := range Types {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
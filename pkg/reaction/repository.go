@@ -0,0 +1,22 @@
+package reaction
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+// Repository handle the storage of the reactions.
+type Repository interface {
+	Set(ctx context.Context, r *Reaction) (previous Type, err error)
+	Delete(ctx context.Context, target Target, targetID, userID primitive.ObjectID) (Type, error)
+	GetUserReactions(ctx context.Context, target Target, userID primitive.ObjectID, targetIDs []primitive.ObjectID) (map[primitive.ObjectID]Type, error)
+	GetUsers(ctx context.Context, target Target, targetID primitive.ObjectID, t Type, skip, limit int64) ([]user.User, int64, error)
+	DeleteAllForTarget(ctx context.Context, target Target, targetID primitive.ObjectID) error
+	GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]Reaction, error)
+	HideAllForUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]Reaction, error)
+	RestoreAllForUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]Reaction, error)
+}
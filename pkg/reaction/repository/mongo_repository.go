@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+// Repository storage to the reaction model.
+type Repository struct {
+	coll *mongo.Collection
+	log  logger.Logger
+}
+
+var pipeLineColl = "users"
+
+// Set store the reaction of a user to a target, replacing the previous
+// one. It returns the type of the replaced reaction, empty if none.
+func (r *Repository) Set(ctx context.Context, re *reaction.Reaction) (reaction.Type, error) {
+	filter := bson.M{
+		"target":    re.Target,
+		"target_id": re.TargetID,
+		"user_id":   re.UserID,
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"type":       re.Type,
+			"updated_at": re.UpdatedAt,
+		},
+		"$setOnInsert": bson.M{
+			"_id":        re.ID,
+			"created_at": re.CreatedAt,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.Before)
+
+	previous := reaction.Reaction{}
+	err := r.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&previous)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", nil
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return "", response.ErrorInternalServerError
+	}
+
+	return previous.Type, nil
+}
+
+// Delete remove the reaction of a user to a target and returns its type.
+func (r *Repository) Delete(ctx context.Context, target reaction.Target, targetID, userID primitive.ObjectID) (reaction.Type, error) {
+	filter := bson.M{
+		"target":    target,
+		"target_id": targetID,
+		"user_id":   userID,
+	}
+
+	deleted := reaction.Reaction{}
+	err := r.coll.FindOneAndDelete(ctx, filter).Decode(&deleted)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", reaction.ErrNoReaction
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return "", response.ErrorInternalServerError
+	}
+
+	return deleted.Type, nil
+}
+
+// GetUserReactions returns the reactions of the user to the targets.
+func (r *Repository) GetUserReactions(ctx context.Context, target reaction.Target, userID primitive.ObjectID, targetIDs []primitive.ObjectID) (map[primitive.ObjectID]reaction.Type, error) {
+	reactions := make(map[primitive.ObjectID]reaction.Type)
+
+	filter := bson.M{
+		"target":     target,
+		"user_id":    userID,
+		"target_id":  bson.M{"$in": targetIDs},
+		"deleted_at": bson.M{"$exists": false},
+	}
+
+	cursor, err := r.coll.Find(ctx, filter)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		re := reaction.Reaction{}
+		if err := cursor.Decode(&re); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		reactions[re.TargetID] = re.Type
+	}
+
+	return reactions, nil
+}
+
+// GetUsers returns a page of the users who reacted to a target, newest
+// first. An empty type returns every reaction.
+func (r *Repository) GetUsers(ctx context.Context, target reaction.Target, targetID primitive.ObjectID, t reaction.Type, skip, limit int64) ([]user.User, int64, error) {
+	match := bson.M{
+		"target":     target,
+		"target_id":  targetID,
+		"deleted_at": bson.M{"$exists": false},
+	}
+	if t != "" {
+		match["type"] = t
+	}
+
+	// The users are joined before the facet, so the total counts only the
+	// users that can be listed.
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: match}},
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         pipeLineColl,
+			"localField":   "user_id",
+			"foreignField": "_id",
+			"as":           "user",
+		}}},
+		bson.D{{Key: "$unwind", Value: "$user"}},
+		bson.D{{Key: "$match", Value: bson.M{"user.deleted_at": bson.M{"$exists": false}}}},
+		bson.D{{Key: "$sort", Value: bson.M{"updated_at": -1}}},
+		bson.D{{Key: "$facet", Value: bson.M{
+			"total": bson.A{
+				bson.M{"$count": "count"},
+			},
+			"users": bson.A{
+				bson.M{"$skip": skip},
+				bson.M{"$limit": limit},
+				bson.M{"$replaceRoot": bson.M{"newRoot": "$user"}},
+				bson.M{"$project": bson.M{"password": 0}},
+			},
+		}}},
+	}
+
+	cursor, err := r.coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, 0, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	result := struct {
+		Total []struct {
+			Count int64 `bson:"count"`
+		} `bson:"total"`
+		Users []user.User `bson:"users"`
+	}{}
+
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&result); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			return nil, 0, response.ErrorInternalServerError
+		}
+	}
+
+	users := result.Users
+	if users == nil {
+		users = make([]user.User, 0)
+	}
+
+	var total int64
+	if len(result.Total) > 0 {
+		total = result.Total[0].Count
+	}
+
+	return users, total, nil
+}
+
+// DeleteAllForTarget remove every reaction to a target.
+func (r *Repository) DeleteAllForTarget(ctx context.Context, target reaction.Target, targetID primitive.ObjectID) error {
+	filter := bson.M{
+		"target":    target,
+		"target_id": targetID,
+	}
+
+	_, err := r.coll.DeleteMany(ctx, filter)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// GetAllForUser returns every reaction of a user.
+func (r *Repository) GetAllForUser(ctx context.Context, userID primitive.ObjectID) ([]reaction.Reaction, error) {
+	return r.find(ctx, bson.M{"user_id": userID})
+}
+
+// find returns the reactions matching filter.
+func (r *Repository) find(ctx context.Context, filter bson.M) ([]reaction.Reaction, error) {
+	reactions := make([]reaction.Reaction, 0)
+
+	cursor, err := r.coll.Find(ctx, filter)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		re := reaction.Reaction{}
+		if err := cursor.Decode(&re); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		reactions = append(reactions, re)
+	}
+
+	return reactions, nil
+}
+
+// HideAllForUser mark as deleted at the given time the visible reactions
+// of a user and returns them, so the counters of their targets can be
+// updated.
+func (r *Repository) HideAllForUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]reaction.Reaction, error) {
+	filter := bson.M{
+		"user_id":    userID,
+		"deleted_at": bson.M{"$exists": false},
+	}
+
+	reactions, err := r.find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(reactions))
+	for _, re := range reactions {
+		ids = append(ids, re.ID)
+	}
+	filter["_id"] = bson.M{"$in": ids}
+
+	_, err = r.coll.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"deleted_at": at}})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	return reactions, nil
+}
+
+// RestoreAllForUser undo the hiding of the reactions of a user deleted at
+// the given time and returns them.
+func (r *Repository) RestoreAllForUser(ctx context.Context, userID primitive.ObjectID, at time.Time) ([]reaction.Reaction, error) {
+	filter := bson.M{
+		"user_id":    userID,
+		"deleted_at": at,
+	}
+
+	reactions, err := r.find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(reactions))
+	for _, re := range reactions {
+		ids = append(ids, re.ID)
+	}
+	filter["_id"] = bson.M{"$in": ids}
+
+	_, err = r.coll.UpdateMany(ctx, filter, bson.M{"$unset": bson.M{"deleted_at": ""}})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	return reactions, nil
+}
+
+// Mongo create a new Repository.
+func Mongo(coll *mongo.Collection, log logger.Logger) reaction.Repository {
+	return &Repository{
+		coll: coll,
+		log:  log,
+	}
+}
@@ -1,19 +1,131 @@
 package response
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+// Kind is the class of an error, it decides the status code of the
+// response of the error.
+type Kind int
+
+// Kinds of errors, an error without kind is Internal.
+const (
+	Internal Kind = iota
+	Invalid
+	Validation
+	Unauthenticated
+	Forbidden
+	NotFound
+	Conflict
+	Gone
+	PreconditionFailed
+	Unsupported
+	Unavailable
+	Timeout
+	TooManyRequests
+	TooLarge
+)
+
+var kinds = map[Kind]struct {
+	code   string
+	status int
+}{
+	Internal:           {"internal", http.StatusInternalServerError},
+	Invalid:            {"invalid", http.StatusBadRequest},
+	Validation:         {CodeValidation, http.StatusBadRequest},
+	Unauthenticated:    {"unauthenticated", http.StatusUnauthorized},
+	Forbidden:          {"forbidden", http.StatusForbidden},
+	NotFound:           {"not_found", http.StatusNotFound},
+	Conflict:           {"conflict", http.StatusConflict},
+	Gone:               {"gone", http.StatusGone},
+	PreconditionFailed: {"precondition_failed", http.StatusPreconditionFailed},
+	Unsupported:        {"unsupported_media_type", http.StatusUnsupportedMediaType},
+	Unavailable:        {"unavailable", http.StatusServiceUnavailable},
+	Timeout:            {"timeout", http.StatusGatewayTimeout},
+	TooManyRequests:    {"rate_limited", http.StatusTooManyRequests},
+	TooLarge:           {"payload_too_large", http.StatusRequestEntityTooLarge},
+}
+
+// String returns the machine-readable code of the kind.
+func (k Kind) String() string {
+	return kinds[k].code
+}
+
+// Status returns the HTTP status code of the kind.
+func (k Kind) Status() int {
+	if s, ok := kinds[k]; ok {
+		return s.status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// kindError is an error with its kind.
+type kindError struct {
+	kind Kind
+	err  error
+}
+
+func (e *kindError) Error() string { return e.err.Error() }
+
+func (e *kindError) Unwrap() error { return e.err }
+
+// Kind returns the kind of the error.
+func (e *kindError) Kind() Kind { return e.kind }
+
+// NewError create an error of a kind, the domains declare their errors
+// with it.
+func NewError(kind Kind, text string) error {
+	return &kindError{kind: kind, err: errors.New(text)}
+}
+
+// WithKind give a kind to err, errors.Is still finds err.
+func WithKind(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &kindError{kind: kind, err: err}
+}
+
+// KindOf returns the kind of the first error of the chain of err with one,
+// the failures of a validation are Validation and the expired contexts
+// Timeout.
+func KindOf(err error) Kind {
+	var k interface{ Kind() Kind }
+	if errors.As(err, &k) {
+		return k.Kind()
+	}
+
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		return Validation
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Timeout
+	}
+
+	return Internal
+}
 
 // Server errors.
 var (
-	ErrTimeout               = errors.New("timeout exceeded")
-	ErrorNotFound            = errors.New("Not Found")
-	ErrorBadRequest          = errors.New("Bad resquest")
-	ErrorBadEmailOrPassword  = errors.New("Bad email or password")
-	ErrorUnauthorized        = errors.New("Unauthorized")
-	ErrorInternalServerError = errors.New("Interal server error")
-	ErrorUUIDNotFound        = errors.New("uid not found")
-	ErrorParsingUser         = errors.New("failed to parse user")
-	ErrInvalidID             = errors.New("invalid id")
-	ErrCouldNotInsert        = errors.New("Error could not insert")
-	ErrInvalidEmail          = errors.New("Error invalid email")
-	ErrCantFollowYou         = errors.New("Error you can't follow you")
+	ErrTimeout               = NewError(Timeout, "timeout exceeded")
+	ErrorNotFound            = NewError(NotFound, "Not Found")
+	ErrorBadRequest          = NewError(Invalid, "Bad resquest")
+	ErrorBadEmailOrPassword  = NewError(Unauthenticated, "Bad email or password")
+	ErrorUnauthorized        = NewError(Forbidden, "Unauthorized")
+	ErrorInternalServerError = NewError(Internal, "Interal server error")
+	ErrorUUIDNotFound        = NewError(Invalid, "uid not found")
+	ErrorParsingUser         = NewError(Invalid, "failed to parse user")
+	ErrInvalidID             = NewError(Invalid, "invalid id")
+	ErrCouldNotInsert        = NewError(Internal, "Error could not insert")
+	ErrInvalidEmail          = NewError(Invalid, "Error invalid email")
+	ErrCantFollowYou         = NewError(Invalid, "Error you can't follow you")
+	ErrPreconditionFailed    = NewError(PreconditionFailed, "precondition failed")
 )
@@ -2,25 +2,72 @@ package response
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/Zucke/social_prove/pkg/validation"
 )
 
+// ProblemType is the content type of the error responses.
+const ProblemType = "application/problem+json"
+
+// CodeValidation is the code of the requests that failed the validation.
+const CodeValidation = "validation_failed"
+
 // Map is an alias for map[string]interface{}, this makes
 // it easier to work with objects of undefined structure.
 type Map map[string]interface{}
 
-// ErrorMessage standardized error response.
-type ErrorMessage struct {
-	Message string `json:"message"`
+// Problem standardized error response, a problem details object of RFC
+// 7807. Code is a machine-readable code of the error and Errors the
+// failures of each field of a request.
+type Problem struct {
+	Type   string            `json:"type"`
+	Title  string            `json:"title"`
+	Status int               `json:"status"`
+	Detail string            `json:"detail,omitempty"`
+	Code   string            `json:"code,omitempty"`
+	Errors validation.Errors `json:"errors,omitempty"`
+}
+
+// Error standardized error response of err, its kind decides the status
+// code. The message of the internal errors isn't shown.
+func Error(w http.ResponseWriter, err error) error {
+	kind := KindOf(err)
+
+	p := newProblem(kind.Status(), err.Error())
+	p.Code = kind.String()
+
+	switch kind {
+	case Internal:
+		p.Detail = ""
+	case Validation:
+		p.Detail = "the request is not valid"
+		errors.As(err, &p.Errors)
+	}
+
+	return problem(w, p)
+}
+
+func newProblem(statusCode int, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(statusCode),
+		Status: statusCode,
+		Detail: detail,
+	}
 }
 
-// HTTPError standardized error response in JSON format.
-func HTTPError(w http.ResponseWriter, statusCode int, message string) error {
-	msg := ErrorMessage{
-		Message: message,
+func problem(w http.ResponseWriter, p Problem) error {
+	j, err := json.Marshal(p)
+	if err != nil {
+		return err
 	}
 
-	return JSON(w, statusCode, msg)
+	w.Header().Set("Content-Type", ProblemType)
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(j)
+	return nil
 }
 
 // JSON standarized JSON response.
@@ -0,0 +1,92 @@
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+func TestError(t *testing.T) {
+	errs := validation.Errors{{Field: "email", Code: validation.Required, Message: "is required"}}
+
+	tests := []struct {
+		name string
+		err  error
+		want Problem
+	}{
+		{
+			name: "not found",
+			err:  ErrorNotFound,
+			want: Problem{Type: "about:blank", Title: "Not Found", Status: http.StatusNotFound, Detail: "Not Found", Code: "not_found"},
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("get user: %w", ErrorUnauthorized),
+			want: Problem{Type: "about:blank", Title: "Forbidden", Status: http.StatusForbidden, Detail: "get user: Unauthorized", Code: "forbidden"},
+		},
+		{
+			name: "with kind",
+			err:  WithKind(Conflict, errors.New("name already in use")),
+			want: Problem{Type: "about:blank", Title: "Conflict", Status: http.StatusConflict, Detail: "name already in use", Code: "conflict"},
+		},
+		{
+			name: "validation",
+			err:  errs,
+			want: Problem{Type: "about:blank", Title: "Bad Request", Status: http.StatusBadRequest, Detail: "the request is not valid", Code: CodeValidation, Errors: errs},
+		},
+		{
+			name: "too many requests",
+			err:  NewError(TooManyRequests, "too many requests"),
+			want: Problem{Type: "about:blank", Title: "Too Many Requests", Status: http.StatusTooManyRequests, Detail: "too many requests", Code: "rate_limited"},
+		},
+		{
+			name: "too large",
+			err:  NewError(TooLarge, "the body is too large"),
+			want: Problem{Type: "about:blank", Title: "Request Entity Too Large", Status: http.StatusRequestEntityTooLarge, Detail: "the body is too large", Code: "payload_too_large"},
+		},
+		{
+			name: "deadline",
+			err:  context.DeadlineExceeded,
+			want: Problem{Type: "about:blank", Title: "Gateway Timeout", Status: http.StatusGatewayTimeout, Detail: "context deadline exceeded", Code: "timeout"},
+		},
+		{
+			name: "internal hides the message",
+			err:  errors.New("connection refused"),
+			want: Problem{Type: "about:blank", Title: "Internal Server Error", Status: http.StatusInternalServerError, Code: "internal"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			err := Error(w, test.err)
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.want.Status, w.Code)
+			assert.Equal(t, ProblemType, w.Header().Get("Content-Type"))
+
+			var p Problem
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+			assert.Equal(t, test.want, p)
+		})
+	}
+}
+
+func TestWithKind(t *testing.T) {
+	base := errors.New("queue is full")
+	err := WithKind(Unavailable, base)
+
+	assert.True(t, errors.Is(err, base))
+	assert.Equal(t, Unavailable, KindOf(err))
+	assert.Equal(t, Internal, KindOf(base))
+	assert.Nil(t, WithKind(Invalid, nil))
+}
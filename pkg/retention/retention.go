@@ -0,0 +1,91 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/Zucke/social_prove/pkg/account"
+	"github.com/Zucke/social_prove/pkg/lease"
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/post"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+const (
+	// leaseName identifies the lease shared by the purgers of every replica.
+	leaseName = "retention-purger"
+	waitTime  = 60
+)
+
+// Purger erase for good the users and posts deleted longer than the
+// retention ago, with what depends on them. Only the replica holding the
+// lease purges, the others stay on standby.
+type Purger struct {
+	*lease.Runner
+	users     user.Repository
+	posts     post.Repository
+	eraser    account.Eraser
+	retention time.Duration
+	log       logger.Logger
+}
+
+// purge purge the expired users and posts. A failed purge is retried on
+// the next run, the document goes last.
+func (p *Purger) purge(ctx context.Context) {
+	before := time.Now().Add(-p.retention)
+
+	userIDs, err := p.users.GetPurgeable(ctx, before)
+	if err != nil {
+		p.log.Errorf("cannot get the users to purge: %v", err)
+		return
+	}
+
+	for _, id := range userIDs {
+		if err := p.eraser.EraseUser(ctx, id); err != nil {
+			p.log.Errorf("cannot purge the user %s: %v", id.Hex(), err)
+		}
+	}
+
+	postIDs, err := p.posts.GetPurgeable(ctx, before)
+	if err != nil {
+		p.log.Errorf("cannot get the posts to purge: %v", err)
+		return
+	}
+
+	for _, id := range postIDs {
+		if err := p.eraser.ErasePost(ctx, id); err != nil {
+			p.log.Errorf("cannot purge the post %s: %v", id.Hex(), err)
+		}
+	}
+
+	if len(userIDs) > 0 || len(postIDs) > 0 {
+		p.log.Infof("%d users and %d posts purged", len(userIDs), len(postIDs))
+	}
+}
+
+// New create a new Purger removing every interval what was deleted longer
+// than retention ago.
+func New(
+	log logger.Logger,
+	users user.Repository,
+	posts post.Repository,
+	eraser account.Eraser,
+	locker lease.Locker,
+	retention time.Duration,
+	interval time.Duration,
+) *Purger {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	p := &Purger{
+		users:     users,
+		posts:     posts,
+		eraser:    eraser,
+		retention: retention,
+		log:       log,
+	}
+	p.Runner = lease.NewRunner(log, locker, leaseName, interval, waitTime*time.Second, p.purge)
+
+	return p
+}
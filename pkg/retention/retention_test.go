@@ -0,0 +1,85 @@
+package retention
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	amock "github.com/Zucke/social_prove/pkg/account/mock"
+	lmock "github.com/Zucke/social_prove/pkg/lease/mock"
+	"github.com/Zucke/social_prove/pkg/logger"
+	pmock "github.com/Zucke/social_prove/pkg/post/mock"
+	"github.com/Zucke/social_prove/pkg/response"
+	umock "github.com/Zucke/social_prove/pkg/user/mock"
+)
+
+func TestPurger_Purge(t *testing.T) {
+	userID := primitive.NewObjectID()
+	postID := primitive.NewObjectID()
+
+	tests := []struct {
+		name      string
+		userErr   error
+		timesGet  int
+		timesUser int
+		timesPost int
+	}{
+		{
+			name:      "purge",
+			timesGet:  1,
+			timesUser: 1,
+			timesPost: 1,
+		},
+		{
+			name:      "posts purged when a user fails",
+			userErr:   response.ErrorInternalServerError,
+			timesGet:  1,
+			timesUser: 1,
+			timesPost: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			um := umock.NewMockRepository(ctrl)
+			pm := pmock.NewMockRepository(ctrl)
+			em := amock.NewMockEraser(ctrl)
+			lm := lmock.NewMockLocker(ctrl)
+
+			um.
+				EXPECT().
+				GetPurgeable(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, before time.Time) ([]primitive.ObjectID, error) {
+					assert.WithinDuration(t, time.Now().Add(-24*time.Hour), before, time.Minute)
+					return []primitive.ObjectID{userID}, nil
+				}).
+				Times(test.timesGet)
+			em.
+				EXPECT().
+				EraseUser(gomock.Any(), userID).
+				Return(test.userErr).
+				Times(test.timesUser)
+
+			pm.
+				EXPECT().
+				GetPurgeable(gomock.Any(), gomock.Any()).
+				Return([]primitive.ObjectID{postID}, nil).
+				Times(test.timesGet)
+			em.
+				EXPECT().
+				ErasePost(gomock.Any(), postID).
+				Return(nil).
+				Times(test.timesPost)
+
+			p := New(logger.NewMock(), um, pm, em, lm, 24*time.Hour, time.Hour)
+			p.purge(context.Background())
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package security
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// ErrBodyTooLarge is the error of the bodies over their limit.
+var ErrBodyTooLarge = response.NewError(response.TooLarge, "the body is too large")
+
+// LimitBody limits the bodies of the requests to n bytes, reading more
+// fails with ErrBodyTooLarge. A LimitBody of a route replaces the one of
+// its router, for the uploads of pictures.
+func LimitBody(n int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Body != nil && r.Body != http.NoBody {
+				body := r.Body
+				if b, ok := body.(*limitedBody); ok {
+					body = b.ReadCloser
+				}
+				r.Body = &limitedBody{ReadCloser: body, left: n}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// limitedBody is a body of left bytes at most.
+type limitedBody struct {
+	io.ReadCloser
+	left int64
+	err  error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+
+	// One byte more tells whether the body is over the limit.
+	if int64(len(p)) > b.left+1 {
+		p = p[:b.left+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.left {
+		b.left -= int64(n)
+		return n, err
+	}
+
+	b.err = ErrBodyTooLarge
+	return int(b.left), b.err
+}
@@ -0,0 +1,133 @@
+package security
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+func TestLimitBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		status      int
+	}{
+		{
+			name:        "Under the limit",
+			body:        `{"text":"hello"}`,
+			contentType: "application/json",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "At the limit",
+			body:        `{"text":"012345678901234567890"}`,
+			contentType: "application/json",
+			status:      http.StatusOK,
+		},
+		{
+			name:        "Over the limit",
+			body:        `{"text":"0123456789012345678901"}`,
+			contentType: "application/json",
+			status:      http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "Over the limit without content type",
+			body:   `{"text":"0123456789012345678901"}`,
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "Multipart over the limit",
+			body:        strings.Repeat("a", 64),
+			contentType: "multipart/form-data; boundary=x",
+			status:      http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := LimitBody(32)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Type") != "application/json" && r.Header.Get("Content-Type") != "" {
+					if _, err := ioutil.ReadAll(r.Body); err != nil {
+						_ = response.Error(w, err)
+					}
+					return
+				}
+
+				var body struct {
+					Text string `json:"text"`
+				}
+				if err := validation.Decode(r.Body, &body); err != nil {
+					_ = response.Error(w, err)
+				}
+			}))
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+			if test.contentType != "" {
+				r.Header.Set("Content-Type", test.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, test.status, w.Code)
+		})
+	}
+}
+
+func TestLimitBody_Route(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{
+			name:   "Router limit",
+			path:   "/post/",
+			body:   strings.Repeat("a", 64),
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "Route limit",
+			path:   "/post/1/pictures",
+			body:   strings.Repeat("a", 64),
+			status: http.StatusOK,
+		},
+		{
+			name:   "Over the route limit",
+			path:   "/post/1/pictures",
+			body:   strings.Repeat("a", 129),
+			status: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	read := func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			_ = response.Error(w, err)
+		}
+	}
+
+	r := chi.NewRouter()
+	r.Use(LimitBody(32))
+	r.Post("/post/", read)
+	r.With(LimitBody(128)).Post("/post/{id}/pictures", read)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package security
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Frame options.
+const (
+	Deny       = "DENY"
+	SameOrigin = "SAMEORIGIN"
+)
+
+// APIPolicy is the content security policy of the JSON responses, they
+// load nothing and can't be framed.
+const APIPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// Headers are the security headers of the responses. Strict-Transport-Security
+// is sent for HSTSMaxAge over HTTPS, or behind a proxy that terminated it,
+// and not at all without HSTSMaxAge.
+type Headers struct {
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age"`
+	FrameOptions          string        `yaml:"frame_options"`
+	ContentSecurityPolicy string        `yaml:"content_security_policy"`
+}
+
+// Middleware sets the headers in the responses of next.
+func (h Headers) Middleware(next http.Handler) http.Handler {
+	hsts := "max-age=" + strconv.FormatInt(int64(h.HSTSMaxAge/time.Second), 10) + "; includeSubDomains"
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Referrer-Policy", "no-referrer")
+		if h.FrameOptions != "" {
+			header.Set("X-Frame-Options", h.FrameOptions)
+		}
+		if h.ContentSecurityPolicy != "" {
+			header.Set("Content-Security-Policy", h.ContentSecurityPolicy)
+		}
+		if h.HSTSMaxAge > 0 && secure(r) {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ContentSecurityPolicy replaces the policy of the responses of next, for
+// the routes that serve pages instead of JSON.
+func ContentSecurityPolicy(policy string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Security-Policy", policy)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// secure reports whether the client connected over HTTPS.
+func secure(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
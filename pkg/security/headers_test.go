@@ -0,0 +1,83 @@
+package security
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaders_Middleware(t *testing.T) {
+	headers := Headers{HSTSMaxAge: 24 * time.Hour, FrameOptions: Deny, ContentSecurityPolicy: APIPolicy}
+
+	tests := []struct {
+		name    string
+		headers Headers
+		request func(r *http.Request)
+		want    map[string]string
+	}{
+		{
+			name:    "HTTP",
+			headers: headers,
+			want: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"Referrer-Policy":           "no-referrer",
+				"X-Frame-Options":           "DENY",
+				"Content-Security-Policy":   APIPolicy,
+				"Strict-Transport-Security": "",
+			},
+		},
+		{
+			name:    "HTTPS",
+			headers: headers,
+			request: func(r *http.Request) { r.TLS = &tls.ConnectionState{} },
+			want:    map[string]string{"Strict-Transport-Security": "max-age=86400; includeSubDomains"},
+		},
+		{
+			name:    "HTTPS behind a proxy",
+			headers: headers,
+			request: func(r *http.Request) { r.Header.Set("X-Forwarded-Proto", "https") },
+			want:    map[string]string{"Strict-Transport-Security": "max-age=86400; includeSubDomains"},
+		},
+		{
+			name:    "Without HSTS",
+			request: func(r *http.Request) { r.TLS = &tls.ConnectionState{} },
+			want: map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "",
+				"Content-Security-Policy":   "",
+				"Strict-Transport-Security": "",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.request != nil {
+				test.request(r)
+			}
+			w := httptest.NewRecorder()
+
+			test.headers.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+
+			for name, value := range test.want {
+				assert.Equal(t, value, w.Header().Get(name), name)
+			}
+		})
+	}
+}
+
+func TestContentSecurityPolicy(t *testing.T) {
+	h := Headers{ContentSecurityPolicy: APIPolicy}.Middleware(
+		ContentSecurityPolicy("default-src 'self'")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})),
+	)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/", nil))
+
+	assert.Equal(t, "default-src 'self'", w.Header().Get("Content-Security-Policy"))
+}
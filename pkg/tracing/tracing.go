@@ -0,0 +1,148 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/go-chi/chi/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Zucke/social_prove/pkg/metrics"
+)
+
+// Exporters of the spans.
+const (
+	None   = "none"
+	Stdout = "stdout"
+	OTLP   = "otlp"
+)
+
+// instrumentation names the tracer of the API.
+const instrumentation = "github.com/Zucke/social_prove"
+
+// Options configure the provider, Endpoint is the OTLP/HTTP endpoint of the
+// collector and SampleRatio the ratio of the new traces kept.
+type Options struct {
+	Exporter    string
+	Endpoint    string
+	ServiceName string
+	SampleRatio float64
+}
+
+// Provider creates and exports the spans of the API. It's installed as
+// the global provider, with the W3C trace context propagator.
+type Provider struct {
+	tp *sdktrace.TracerProvider
+}
+
+// New install the provider. Without exporter the spans are still created,
+// so the logs get the trace IDs.
+func New(o Options) (*Provider, error) {
+	exporter, err := newExporter(o, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProvider(o, exporter), nil
+}
+
+// newExporter returns the exporter of the options, nil for none. The OTLP
+// spans are sent in protobuf, which every OTLP/HTTP receiver accepts.
+func newExporter(o Options, stdout io.Writer) (sdktrace.SpanExporter, error) {
+	switch o.Exporter {
+	case Stdout:
+		return stdouttrace.New(stdouttrace.WithWriter(stdout))
+	case OTLP:
+		u, err := url.Parse(o.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+		if u.Path != "" {
+			opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+		}
+		if u.Scheme == "http" {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		return otlptracehttp.New(context.Background(), opts...)
+	}
+
+	return nil, nil
+}
+
+func newProvider(o Options, exporter sdktrace.SpanExporter) *Provider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(o.ServiceName),
+		)),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return &Provider{tp: tp}
+}
+
+// Close export the pending spans and stop the provider.
+func (p *Provider) Close(ctx context.Context) error {
+	return p.tp.Shutdown(ctx)
+}
+
+// Start a span as child of the span of ctx.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, opts...)
+}
+
+// Middleware starts the span of each request, child of the span of the
+// traceparent header if any. It must wrap the router so the span is named
+// by the route pattern.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := Start(
+			ctx,
+			"HTTP "+r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.NetAttributesFromHTTPRequest("tcp", r)...),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", "", r)...),
+		)
+		defer span.End()
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		route := metrics.Route(r)
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(semconv.HTTPRouteKey.String(route), semconv.HTTPStatusCodeKey.Int(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
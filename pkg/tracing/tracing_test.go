@@ -0,0 +1,152 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentID = "00f067aa0ba902b7"
+)
+
+// recorder keeps the exported spans after the provider is closed, which
+// flushes them.
+type recorder struct {
+	*tracetest.InMemoryExporter
+}
+
+func (r recorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		status      int
+		traceID     string
+		parentID    string
+		spanStatus  sdktrace.Status
+	}{
+		{
+			name:        "child of the traceparent",
+			traceparent: "00-" + traceID + "-" + parentID + "-01",
+			status:      http.StatusOK,
+			traceID:     traceID,
+			parentID:    parentID,
+		},
+		{
+			name:   "new trace",
+			status: http.StatusOK,
+		},
+		{
+			name:       "server error",
+			status:     http.StatusInternalServerError,
+			spanStatus: sdktrace.Status{Code: codes.Error, Description: "Internal Server Error"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter := recorder{tracetest.NewInMemoryExporter()}
+			p := newProvider(Options{Exporter: Stdout, ServiceName: "social", SampleRatio: 1}, exporter)
+
+			r := chi.NewRouter()
+			r.Use(Middleware)
+			r.Get("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/user/1", nil)
+			if test.traceparent != "" {
+				req.Header.Set("traceparent", test.traceparent)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.NoError(t, p.Close(context.Background()))
+
+			spans := exporter.GetSpans()
+			assert.Len(t, spans, 1)
+
+			s := spans[0]
+			if test.traceID != "" {
+				assert.Equal(t, test.traceID, s.SpanContext.TraceID().String())
+			}
+			if test.parentID != "" {
+				assert.Equal(t, test.parentID, s.Parent.SpanID().String())
+			} else {
+				assert.False(t, s.Parent.IsValid())
+			}
+			assert.Equal(t, "GET /user/{id}", s.Name)
+			assert.Equal(t, trace.SpanKindServer, s.SpanKind)
+			assert.Equal(t, test.spanStatus, s.Status)
+		})
+	}
+}
+
+func TestStdoutExporter(t *testing.T) {
+	var buf bytes.Buffer
+	o := Options{Exporter: Stdout, ServiceName: "social", SampleRatio: 1}
+	exporter, err := newExporter(o, &buf)
+	assert.NoError(t, err)
+	p := newProvider(o, exporter)
+
+	_, span := Start(context.Background(), "UserService.GetByID")
+	span.End()
+
+	assert.NoError(t, p.Close(context.Background()))
+	assert.Contains(t, buf.String(), `"Name":"UserService.GetByID"`)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	var got coltracepb.ExportTraceServiceRequest
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.NoError(t, proto.Unmarshal(body, &got))
+	}))
+	defer collector.Close()
+
+	o := Options{Exporter: OTLP, Endpoint: collector.URL + "/v1/traces", ServiceName: "social", SampleRatio: 1}
+	exporter, err := newExporter(o, nil)
+	assert.NoError(t, err)
+	p := newProvider(o, exporter)
+
+	ctx, parent := Start(context.Background(), "UserService.GetByID")
+	_, child := Start(ctx, "mongo.find")
+	child.End()
+	parent.End()
+
+	assert.NoError(t, p.Close(context.Background()))
+
+	assert.Len(t, got.ResourceSpans, 1)
+	attrs := got.ResourceSpans[0].Resource.Attributes
+	found := false
+	for _, kv := range attrs {
+		if kv.Key == "service.name" && kv.Value.GetStringValue() == "social" {
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	spans := got.ResourceSpans[0].InstrumentationLibrarySpans[0].Spans
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "mongo.find", spans[0].Name)
+	assert.Equal(t, spans[1].SpanId, spans[0].ParentSpanId)
+	assert.Equal(t, spans[1].TraceId, spans[0].TraceId)
+}
@@ -2,22 +2,23 @@ package handler
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
-
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/Zucke/social_prove/pkg/audit"
 	"github.com/Zucke/social_prove/pkg/auth"
 	"github.com/Zucke/social_prove/pkg/logger"
 	"github.com/Zucke/social_prove/pkg/pagination"
+	"github.com/Zucke/social_prove/pkg/patch"
 	"github.com/Zucke/social_prove/pkg/response"
 	"github.com/Zucke/social_prove/pkg/user"
 	"github.com/Zucke/social_prove/pkg/user/service"
+	"github.com/Zucke/social_prove/pkg/validation"
 )
 
 // Handler is the router of the users.
@@ -31,10 +32,18 @@ func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	var u, storedUser *user.User
 	var tokenString string
 
-	err := json.NewDecoder(r.Body).Decode(&u)
+	err := validation.Decode(r.Body, &u)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	if u == nil {
+		u = &user.User{}
+	}
+	if err := u.ValidateCredentials(); err != nil {
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -43,26 +52,16 @@ func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		storedUser, tokenString, err = h.service.LoginUser(ctx, u)
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		if errors.Is(err, response.ErrorBadEmailOrPassword) {
-			_ = response.HTTPError(w, http.StatusBadRequest, err.Error())
-			return
-		} else if errors.Is(err, response.ErrorNotFound) {
-			_ = response.HTTPError(w, http.StatusNotFound, err.Error())
-			return
-
-		} else {
-			_ = response.HTTPError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
 	}
 
 	_ = response.JSON(w, http.StatusOK, response.Map{
@@ -77,7 +76,7 @@ func (h *Handler) FirebaseAuthHandler(w http.ResponseWriter, r *http.Request) {
 	iUID := ctx.Value(auth.UIDKey)
 	uid, ok := iUID.(string)
 	if !ok {
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorUUIDNotFound.Error())
+		_ = response.Error(w, response.ErrorUUIDNotFound)
 		return
 	}
 
@@ -92,21 +91,16 @@ func (h *Handler) FirebaseAuthHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		u, tokenString, err = h.service.FirebaseAuth(ctx, uid)
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		if errors.Is(err, response.ErrorNotFound) {
-			_ = response.HTTPError(w, http.StatusNotFound, err.Error())
-			return
-		} else {
-			_ = response.HTTPError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
 	}
 
 	_ = response.JSON(w, http.StatusOK, response.Map{
@@ -133,7 +127,7 @@ func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		if all {
@@ -144,8 +138,8 @@ func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, response.ErrorNotFound.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -177,7 +171,7 @@ func (h *Handler) GetAdminsHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		users, err = h.service.GetByRole(ctx, user.Admin)
@@ -185,8 +179,8 @@ func (h *Handler) GetAdminsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, response.ErrorNotFound.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -209,18 +203,19 @@ func (h *Handler) GetOneHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		u, err = h.service.GetByID(ctx, id)
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, response.ErrorNotFound.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
+	w.Header().Set("ETag", patch.ETag(u.Version))
 	_ = response.JSON(w, http.StatusOK, response.Map{"user": u})
 }
 
@@ -228,10 +223,10 @@ func (h *Handler) GetOneHandler(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	var u user.User
 
-	err := json.NewDecoder(r.Body).Decode(&u)
+	err := validation.Decode(r.Body, &u)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorParsingUser.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -242,15 +237,15 @@ func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		err = h.service.Create(ctx, &u)
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, err.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -258,33 +253,31 @@ func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	_ = response.JSON(w, http.StatusCreated, response.Map{"user": u})
 }
 
-// CreateAdminHandler Start a new user.
+// CreateAdminHandler Start a new admin.
 func (h *Handler) CreateAdminHandler(w http.ResponseWriter, r *http.Request) {
 	var u user.User
 
-	err := json.NewDecoder(r.Body).Decode(&u)
+	err := validation.Decode(r.Body, &u)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorParsingUser.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
-	u.Role = user.Admin
-
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
-		err = h.service.Create(ctx, &u)
+		err = h.service.CreateAdmin(ctx, &u)
 	}
 
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, err.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -292,13 +285,14 @@ func (h *Handler) CreateAdminHandler(w http.ResponseWriter, r *http.Request) {
 	_ = response.JSON(w, http.StatusCreated, response.Map{"user": u})
 }
 
-// UpdateHandler update a stored user by id.
+// UpdateHandler replace the profile of a user, If-Match makes it
+// conditional to the ETag of the user.
 func (h *Handler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 	var u, updatedUser user.User
-	err := json.NewDecoder(r.Body).Decode(&u)
+	err := validation.Decode(r.Body, &u)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorParsingUser.Error())
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -306,12 +300,18 @@ func (h *Handler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 
 	cu, err := auth.GetID(r)
 	if err != nil {
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		_ = response.Error(w, response.ErrorBadRequest)
 		return
 	}
 	role, err := auth.GetRole(r)
 	if err != nil {
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	version, err := patch.IfMatch(r)
+	if err != nil {
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -320,19 +320,70 @@ func (h *Handler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
-		updatedUser, err = h.service.Update(ctx, id, cu, role, &u)
+		updatedUser, err = h.service.Update(ctx, id, cu, role, &u, version)
 	}
 
+	h.updateResponse(w, r, updatedUser, err)
+}
+
+// PatchHandler apply a JSON merge patch to the profile of a user, If-Match
+// makes it conditional to the ETag of the user.
+func (h *Handler) PatchHandler(w http.ResponseWriter, r *http.Request) {
+	var updatedUser user.User
+
+	p, err := patch.Read(r)
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, err.Error())
+		_ = response.Error(w, err)
 		return
 	}
 
-	render.JSON(w, r, render.M{"user": updatedUser})
+	id := chi.URLParam(r, "id")
+
+	cu, err := auth.GetID(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+	role, err := auth.GetRole(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	version, err := patch.IfMatch(r)
+	if err != nil {
+		_ = response.Error(w, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		updatedUser, err = h.service.Patch(ctx, id, cu, role, p, version)
+	}
+
+	h.updateResponse(w, r, updatedUser, err)
+}
+
+// updateResponse response the updated user with its ETag, or the error
+// of the update.
+func (h *Handler) updateResponse(w http.ResponseWriter, r *http.Request, u user.User, err error) {
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", patch.ETag(u.Version))
+	render.JSON(w, r, render.M{"user": u})
 }
 
 //FollowToHandler follow to somebody
@@ -342,7 +393,7 @@ func (h *Handler) FollowToHandler(w http.ResponseWriter, r *http.Request) {
 	followerID, err := auth.GetID(r)
 
 	if err != nil {
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		_ = response.Error(w, response.ErrorBadRequest)
 		return
 	}
 
@@ -351,14 +402,15 @@ func (h *Handler) FollowToHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		followerUser, err = h.service.FollowTo(ctx, followingID, followerID)
 	}
 
 	if err != nil {
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorInternalServerError.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
@@ -372,7 +424,7 @@ func (h *Handler) UnfollowToHandler(w http.ResponseWriter, r *http.Request) {
 	followingID := chi.URLParam(r, "id")
 	followerID, err := auth.GetID(r)
 	if err != nil {
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		_ = response.Error(w, response.ErrorBadRequest)
 		return
 	}
 
@@ -381,14 +433,16 @@ func (h *Handler) UnfollowToHandler(w http.ResponseWriter, r *http.Request) {
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		followedUser, err = h.service.UnfollowTo(ctx, followingID, followerID)
 	}
 
 	if err != nil {
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorInternalServerError.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
 	}
 
 	render.Status(r, http.StatusOK)
@@ -406,82 +460,323 @@ func (h *Handler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
 
 	role, err := auth.GetRole(r)
 	if err != nil {
-		_ = response.HTTPError(w, http.StatusBadRequest, response.ErrorBadRequest.Error())
+		_ = response.Error(w, response.ErrorBadRequest)
 		return
 	}
 
 	select {
 	case <-ctx.Done():
-		_ = response.HTTPError(w, http.StatusBadGateway, response.ErrTimeout.Error())
+		_ = response.Error(w, response.ErrTimeout)
 		return
 	default:
 		err = h.service.Delete(ctx, role, id)
 	}
 	if err != nil {
-		h.log.Error(err)
-		_ = response.HTTPError(w, http.StatusNotFound, err.Error())
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
 		return
 	}
 
 	render.JSON(w, r, render.M{})
 }
 
+// RestoreHandler undo the delete of a user by ID.
+func (h *Handler) RestoreHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var (
+		u   user.User
+		err error
+	)
+
+	role, err := auth.GetRole(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		u, err = h.service.Restore(ctx, role, id)
+	}
+
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, response.Map{"user": u})
+}
+
+// DeactivateHandler deactivate a user, until it logs in again when it
+// deactivated itself.
+func (h *Handler) DeactivateHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	cu, err := auth.GetID(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+	role, err := auth.GetRole(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		err = h.service.Deactivate(ctx, id, cu, role)
+	}
+
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	render.JSON(w, r, render.M{})
+}
+
+// ActivateHandler undo the deactivation of a user by ID.
+func (h *Handler) ActivateHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var (
+		u   user.User
+		err error
+	)
+
+	role, err := auth.GetRole(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		u, err = h.service.Activate(ctx, role, id)
+	}
+
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, response.Map{"user": u})
+}
+
+// SuspendHandler suspend a user with a reason and an optional end.
+func (h *Handler) SuspendHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		s user.Suspension
+		u user.User
+	)
+	err := validation.Decode(r.Body, &s)
+	if err != nil {
+		h.log.WithContext(r.Context()).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+
+	cu, err := auth.GetID(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+	role, err := auth.GetRole(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		u, err = h.service.Suspend(ctx, id, cu, role, s)
+	}
+
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, response.Map{"user": u})
+}
+
+// UnsuspendHandler lift the suspension of a user.
+func (h *Handler) UnsuspendHandler(w http.ResponseWriter, r *http.Request) {
+	var u user.User
+	id := chi.URLParam(r, "id")
+
+	cu, err := auth.GetID(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+	role, err := auth.GetRole(r)
+	if err != nil {
+		_ = response.Error(w, response.ErrorBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		u, err = h.service.Unsuspend(ctx, id, cu, role)
+	}
+
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, response.Map{"user": u})
+}
+
+// GetSuspensionsHandler response the suspension records of a user.
+func (h *Handler) GetSuspensionsHandler(w http.ResponseWriter, r *http.Request) {
+	var records []user.SuspensionRecord
+	id := chi.URLParam(r, "id")
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		_ = response.Error(w, response.ErrTimeout)
+		return
+	default:
+		records, err = h.service.GetSuspensions(ctx, id)
+	}
+
+	if err != nil {
+		h.log.WithContext(ctx).Error(err)
+		_ = response.Error(w, err)
+		return
+	}
+
+	_ = response.JSON(w, http.StatusOK, response.Map{"suspensions": records})
+}
+
 //Routes configure and return routes for users
-func (h *Handler) Routes() http.Handler {
+func (h *Handler) Routes(a *auth.Authenticator) http.Handler {
 	r := chi.NewRouter()
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client, user.Admin, user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
 		Get("/", h.GetAllHandler)
 
 	r.Post("/", h.CreateHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client, user.Admin, user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
 		Get("/{id}", h.GetOneHandler)
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client, user.Admin, user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
 		Put("/{id}", h.UpdateHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Patch("/{id}", h.PatchHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
 		Post("/{id}/follow", h.FollowToHandler)
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Client)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Client)).
 		Delete("/{id}/follow", h.UnfollowToHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Super)).
-		Get("/admin", h.CreateHandler)
+		With(a.Authenticate).
+		With(a.WithRole(user.Super)).
+		Post("/admin", h.CreateAdminHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Super)).
 		Get("/admin", h.GetAdminsHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Admin, user.Super)).
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
 		Delete("/{id}", h.DeleteHandler)
 
 	r.
-		With(auth.Authenticator).
-		With(auth.WithRole(user.Admin, user.Super)).
-		Put("/{id}", h.UpdateHandler)
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Post("/{id}/restore", h.RestoreHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Client, user.Admin, user.Super)).
+		Post("/{id}/deactivate", h.DeactivateHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Post("/{id}/activate", h.ActivateHandler)
+
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Post("/{id}/suspension", h.SuspendHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Delete("/{id}/suspension", h.UnsuspendHandler)
+	r.
+		With(a.Authenticate).
+		With(a.WithRole(user.Admin, user.Super)).
+		Get("/{id}/suspensions", h.GetSuspensionsHandler)
 	return r
 
 }
 
 // NewUserHandler create and configure a new Handler.
-func New(coll *mongo.Collection, log logger.Logger, firebaseRepo auth.Repository) *Handler {
+func New(coll, postColl, commentColl, reactionColl, suspensionColl *mongo.Collection, log logger.Logger, timeout time.Duration, signingString string, firebaseRepo auth.Repository, auditor audit.Recorder) *Handler {
 	return &Handler{
 		log:     log,
-		service: service.New(coll, log, firebaseRepo),
+		service: service.New(coll, postColl, commentColl, reactionColl, suspensionColl, log, timeout, signingString, firebaseRepo, auditor),
 	}
 }
@@ -17,9 +17,11 @@ import (
 
 	"github.com/Zucke/social_prove/pkg/auth"
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/patch"
 	"github.com/Zucke/social_prove/pkg/response"
 	"github.com/Zucke/social_prove/pkg/user"
 	mock "github.com/Zucke/social_prove/pkg/user/mock"
+	"github.com/Zucke/social_prove/pkg/validation"
 )
 
 func TestHandler_FirebaseAuthHandler(t *testing.T) {
@@ -170,7 +172,7 @@ func TestHandler_LoginHandler(t *testing.T) {
 			user:  &badPasswordUserLogin,
 			rUser: &user.User{},
 			body:  strings.NewReader(string(jsonbadPasswordUserLogin)),
-			code:  http.StatusBadRequest,
+			code:  http.StatusUnauthorized,
 			err:   response.ErrorBadEmailOrPassword,
 			times: 1,
 		},
@@ -360,6 +362,7 @@ func TestHandler_CreateHandler(t *testing.T) {
 		code  int
 		err   error
 		times int
+		want  string
 	}{
 		{
 			name:  "Success",
@@ -378,10 +381,27 @@ func TestHandler_CreateHandler(t *testing.T) {
 			times: 0,
 		},
 		{
-			name:  "User not inserted",
+			name:  "Wrong type",
+			user:  u,
+			body:  strings.NewReader(`{"email":1}`),
+			code:  http.StatusBadRequest,
+			times: 0,
+			want:  `"errors":[{"field":"email","code":"invalid_type","message":"cannot be a number"}]`,
+		},
+		{
+			name:  "Not valid",
 			user:  u,
 			body:  bytes.NewReader(j),
 			code:  http.StatusBadRequest,
+			err:   validation.Errors{{Field: "email", Code: validation.InvalidFormat, Message: "must be an email address"}},
+			times: 1,
+			want:  `"code":"validation_failed","errors":[{"field":"email","code":"invalid_format"`,
+		},
+		{
+			name:  "User not inserted",
+			user:  u,
+			body:  bytes.NewReader(j),
+			code:  http.StatusInternalServerError,
 			err:   response.ErrCouldNotInsert,
 			times: 1,
 		},
@@ -409,6 +429,7 @@ func TestHandler_CreateHandler(t *testing.T) {
 			mux.ServeHTTP(w, r)
 
 			assert.Equal(t, test.code, w.Code)
+			assert.Contains(t, w.Body.String(), test.want)
 		})
 	}
 }
@@ -647,6 +668,63 @@ func TestHandler_DeleteHandler(t *testing.T) {
 		})
 	}
 }
+func TestHandler_ActivateHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	role := user.Admin
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name  string
+		code  int
+		err   error
+		times int
+	}{
+		{
+			name:  "Success",
+			code:  http.StatusOK,
+			times: 1,
+		},
+		{
+			name:  "Failure not a client",
+			code:  http.StatusNotFound,
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Activate(gomock.Any(), role, id.Hex()).
+				Return(user.User{ID: id, Active: true}, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/users/"+id.Hex()+"/activate", nil)
+			ctx := context.WithValue(r.Context(), auth.RoleKey, role)
+			r = r.WithContext(ctx)
+
+			mux := chi.NewRouter()
+			mux.Post("/users/{id}/activate", h.ActivateHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
+
 func TestHandler_UpdateHandler(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -706,7 +784,7 @@ func TestHandler_UpdateHandler(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				Update(gomock.Any(), id.Hex(), id.Hex(), role, &test.user).
+				Update(gomock.Any(), id.Hex(), id.Hex(), role, &test.user, patch.AnyVersion).
 				Return(test.user, test.err).
 				Times(test.times)
 
@@ -730,3 +808,176 @@ func TestHandler_UpdateHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_PatchHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	role := user.Client
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id := primitive.NewObjectID()
+	body := `{"bio":"after"}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		ifMatch     string
+		version     int64
+		err         error
+		code        int
+		times       int
+	}{
+		{
+			name:        "Success",
+			contentType: patch.ContentType,
+			ifMatch:     `"4"`,
+			version:     4,
+			code:        http.StatusOK,
+			times:       1,
+		},
+		{
+			name:        "Success without If-Match",
+			contentType: "application/json",
+			version:     patch.AnyVersion,
+			code:        http.StatusOK,
+			times:       1,
+		},
+		{
+			name:        "Failure stale version",
+			contentType: patch.ContentType,
+			ifMatch:     `"3"`,
+			version:     3,
+			err:         response.ErrPreconditionFailed,
+			code:        http.StatusPreconditionFailed,
+			times:       1,
+		},
+		{
+			name:        "Failure field not editable",
+			contentType: patch.ContentType,
+			version:     patch.AnyVersion,
+			err:         &patch.FieldError{Field: "email", Reason: "cannot be changed"},
+			code:        http.StatusBadRequest,
+			times:       1,
+		},
+		{
+			name:        "Failure bad If-Match",
+			contentType: patch.ContentType,
+			ifMatch:     `W/"4"`,
+			code:        http.StatusPreconditionFailed,
+		},
+		{
+			name:        "Failure media type",
+			contentType: "text/plain",
+			code:        http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Patch(gomock.Any(), id.Hex(), id.Hex(), role, []byte(body), test.version).
+				Return(user.User{ID: id, Version: 5}, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPatch, "/users/"+id.Hex(), strings.NewReader(body))
+			r.Header.Set("Content-Type", test.contentType)
+			if test.ifMatch != "" {
+				r.Header.Set("If-Match", test.ifMatch)
+			}
+			ctx := context.WithValue(r.Context(), auth.RoleKey, role)
+			ctx = context.WithValue(ctx, auth.IDKey, id)
+			r = r.WithContext(ctx)
+
+			mux := chi.NewRouter()
+			mux.Patch("/users/{id}", h.PatchHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+			if test.code == http.StatusOK {
+				assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestHandler_SuspendHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	role := user.Admin
+
+	m := mock.NewMockService(ctrl)
+	l := logger.NewMock()
+	id := primitive.NewObjectID()
+	actorID := primitive.NewObjectID()
+
+	tests := []struct {
+		name  string
+		body  string
+		code  int
+		err   error
+		times int
+	}{
+		{
+			name:  "Success",
+			body:  `{"reason":"spam"}`,
+			code:  http.StatusOK,
+			times: 1,
+		},
+		{
+			name:  "Failure invalid suspension",
+			body:  `{"reason":""}`,
+			code:  http.StatusBadRequest,
+			err:   user.ErrInvalidSuspension,
+			times: 1,
+		},
+		{
+			name:  "Failure not a client",
+			body:  `{"reason":"spam"}`,
+			code:  http.StatusNotFound,
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+		{
+			name: "Failure bad body",
+			body: `{"reason":`,
+			code: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Suspend(gomock.Any(), id.Hex(), actorID.Hex(), role, gomock.Any()).
+				Return(user.User{ID: id}, test.err).
+				Times(test.times)
+
+			h := Handler{
+				service: m,
+				log:     l,
+			}
+
+			w := httptest.NewRecorder()
+
+			r := httptest.NewRequest(http.MethodPost, "/users/"+id.Hex()+"/suspension", strings.NewReader(test.body))
+			ctx := context.WithValue(r.Context(), auth.RoleKey, role)
+			r = r.WithContext(context.WithValue(ctx, auth.IDKey, actorID))
+
+			mux := chi.NewRouter()
+			mux.Post("/users/{id}/suspension", h.SuspendHandler)
+			mux.ServeHTTP(w, r)
+
+			assert.Equal(t, test.code, w.Code)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 	reflect "reflect"
+	time "time"
 )
 
 // MockRepository is a mock of Repository interface
@@ -35,6 +36,20 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Activate mocks base method
+func (m *MockRepository) Activate(arg0 context.Context, arg1 user.Role, arg2 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Activate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Activate indicates an expected call of Activate
+func (mr *MockRepositoryMockRecorder) Activate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Activate", reflect.TypeOf((*MockRepository)(nil).Activate), arg0, arg1, arg2)
+}
+
 // Create mocks base method
 func (m *MockRepository) Create(arg0 context.Context, arg1 *user.User) error {
 	m.ctrl.T.Helper()
@@ -49,18 +64,32 @@ func (mr *MockRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), arg0, arg1)
 }
 
+// Deactivate mocks base method
+func (m *MockRepository) Deactivate(arg0 context.Context, arg1 user.Role, arg2, arg3 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deactivate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deactivate indicates an expected call of Deactivate
+func (mr *MockRepositoryMockRecorder) Deactivate(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deactivate", reflect.TypeOf((*MockRepository)(nil).Deactivate), arg0, arg1, arg2, arg3)
+}
+
 // Delete mocks base method
-func (m *MockRepository) Delete(arg0 context.Context, arg1 user.Role, arg2 primitive.ObjectID) error {
+func (m *MockRepository) Delete(arg0 context.Context, arg1 user.Role, arg2 primitive.ObjectID, arg3 time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete
-func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), arg0, arg1, arg2, arg3)
 }
 
 // FollowTo mocks base method
@@ -167,6 +196,121 @@ func (mr *MockRepositoryMockRecorder) GetByUID(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUID", reflect.TypeOf((*MockRepository)(nil).GetByUID), arg0, arg1)
 }
 
+// GetFollowers mocks base method
+func (m *MockRepository) GetFollowers(arg0 context.Context, arg1 primitive.ObjectID) ([]user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowers", arg0, arg1)
+	ret0, _ := ret[0].([]user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowers indicates an expected call of GetFollowers
+func (mr *MockRepositoryMockRecorder) GetFollowers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowers", reflect.TypeOf((*MockRepository)(nil).GetFollowers), arg0, arg1)
+}
+
+// GetPurgeable mocks base method
+func (m *MockRepository) GetPurgeable(arg0 context.Context, arg1 time.Time) ([]primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurgeable", arg0, arg1)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurgeable indicates an expected call of GetPurgeable
+func (mr *MockRepositoryMockRecorder) GetPurgeable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurgeable", reflect.TypeOf((*MockRepository)(nil).GetPurgeable), arg0, arg1)
+}
+
+// HideReferences mocks base method
+func (m *MockRepository) HideReferences(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HideReferences", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HideReferences indicates an expected call of HideReferences
+func (mr *MockRepositoryMockRecorder) HideReferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HideReferences", reflect.TypeOf((*MockRepository)(nil).HideReferences), arg0, arg1)
+}
+
+// Purge mocks base method
+func (m *MockRepository) Purge(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge
+func (mr *MockRepositoryMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRepository)(nil).Purge), arg0, arg1)
+}
+
+// RemoveReferences mocks base method
+func (m *MockRepository) RemoveReferences(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReferences", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveReferences indicates an expected call of RemoveReferences
+func (mr *MockRepositoryMockRecorder) RemoveReferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReferences", reflect.TypeOf((*MockRepository)(nil).RemoveReferences), arg0, arg1)
+}
+
+// Restore mocks base method
+func (m *MockRepository) Restore(arg0 context.Context, arg1 user.Role, arg2 primitive.ObjectID) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1, arg2)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockRepositoryMockRecorder) Restore(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), arg0, arg1, arg2)
+}
+
+// RestoreReferences mocks base method
+func (m *MockRepository) RestoreReferences(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreReferences", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreReferences indicates an expected call of RestoreReferences
+func (mr *MockRepositoryMockRecorder) RestoreReferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreReferences", reflect.TypeOf((*MockRepository)(nil).RestoreReferences), arg0, arg1)
+}
+
+// Suspend mocks base method
+func (m *MockRepository) Suspend(arg0 context.Context, arg1 user.Role, arg2 primitive.ObjectID, arg3 user.Suspension) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suspend", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Suspend indicates an expected call of Suspend
+func (mr *MockRepositoryMockRecorder) Suspend(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suspend", reflect.TypeOf((*MockRepository)(nil).Suspend), arg0, arg1, arg2, arg3)
+}
+
 // UnfollowTo mocks base method
 func (m *MockRepository) UnfollowTo(arg0 context.Context, arg1, arg2 primitive.ObjectID) error {
 	m.ctrl.T.Helper()
@@ -181,16 +325,30 @@ func (mr *MockRepositoryMockRecorder) UnfollowTo(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowTo", reflect.TypeOf((*MockRepository)(nil).UnfollowTo), arg0, arg1, arg2)
 }
 
+// Unsuspend mocks base method
+func (m *MockRepository) Unsuspend(arg0 context.Context, arg1 user.Role, arg2 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsuspend", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsuspend indicates an expected call of Unsuspend
+func (mr *MockRepositoryMockRecorder) Unsuspend(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsuspend", reflect.TypeOf((*MockRepository)(nil).Unsuspend), arg0, arg1, arg2)
+}
+
 // Update mocks base method
-func (m *MockRepository) Update(arg0 context.Context, arg1 user.Role, arg2 primitive.ObjectID, arg3 *user.User) error {
+func (m *MockRepository) Update(arg0 context.Context, arg1 user.Role, arg2 primitive.ObjectID, arg3 *user.User, arg4 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update
-func (mr *MockRepositoryMockRecorder) Update(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), arg0, arg1, arg2, arg3, arg4)
 }
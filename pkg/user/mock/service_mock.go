@@ -34,6 +34,21 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// Activate mocks base method
+func (m *MockService) Activate(arg0 context.Context, arg1 user.Role, arg2 string) (user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Activate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Activate indicates an expected call of Activate
+func (mr *MockServiceMockRecorder) Activate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Activate", reflect.TypeOf((*MockService)(nil).Activate), arg0, arg1, arg2)
+}
+
 // Create mocks base method
 func (m *MockService) Create(arg0 context.Context, arg1 *user.User) error {
 	m.ctrl.T.Helper()
@@ -48,6 +63,34 @@ func (mr *MockServiceMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), arg0, arg1)
 }
 
+// CreateAdmin mocks base method
+func (m *MockService) CreateAdmin(arg0 context.Context, arg1 *user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdmin", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAdmin indicates an expected call of CreateAdmin
+func (mr *MockServiceMockRecorder) CreateAdmin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdmin", reflect.TypeOf((*MockService)(nil).CreateAdmin), arg0, arg1)
+}
+
+// Deactivate mocks base method
+func (m *MockService) Deactivate(arg0 context.Context, arg1, arg2 string, arg3 user.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deactivate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deactivate indicates an expected call of Deactivate
+func (mr *MockServiceMockRecorder) Deactivate(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deactivate", reflect.TypeOf((*MockService)(nil).Deactivate), arg0, arg1, arg2, arg3)
+}
+
 // Delete mocks base method
 func (m *MockService) Delete(arg0 context.Context, arg1 user.Role, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -183,6 +226,21 @@ func (mr *MockServiceMockRecorder) GetByUID(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUID", reflect.TypeOf((*MockService)(nil).GetByUID), arg0, arg1)
 }
 
+// GetSuspensions mocks base method
+func (m *MockService) GetSuspensions(arg0 context.Context, arg1 string) ([]user.SuspensionRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuspensions", arg0, arg1)
+	ret0, _ := ret[0].([]user.SuspensionRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuspensions indicates an expected call of GetSuspensions
+func (mr *MockServiceMockRecorder) GetSuspensions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuspensions", reflect.TypeOf((*MockService)(nil).GetSuspensions), arg0, arg1)
+}
+
 // LoginUser mocks base method
 func (m *MockService) LoginUser(arg0 context.Context, arg1 *user.User) (*user.User, string, error) {
 	m.ctrl.T.Helper()
@@ -199,6 +257,51 @@ func (mr *MockServiceMockRecorder) LoginUser(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockService)(nil).LoginUser), arg0, arg1)
 }
 
+// Patch mocks base method
+func (m *MockService) Patch(arg0 context.Context, arg1, arg2 string, arg3 user.Role, arg4 []byte, arg5 int64) (user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Patch", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Patch indicates an expected call of Patch
+func (mr *MockServiceMockRecorder) Patch(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Patch", reflect.TypeOf((*MockService)(nil).Patch), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// Restore mocks base method
+func (m *MockService) Restore(arg0 context.Context, arg1 user.Role, arg2 string) (user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", arg0, arg1, arg2)
+	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockServiceMockRecorder) Restore(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), arg0, arg1, arg2)
+}
+
+// Suspend mocks base method
+func (m *MockService) Suspend(arg0 context.Context, arg1, arg2 string, arg3 user.Role, arg4 user.Suspension) (user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suspend", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suspend indicates an expected call of Suspend
+func (mr *MockServiceMockRecorder) Suspend(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suspend", reflect.TypeOf((*MockService)(nil).Suspend), arg0, arg1, arg2, arg3, arg4)
+}
+
 // UnfollowTo mocks base method
 func (m *MockService) UnfollowTo(arg0 context.Context, arg1, arg2 string) (user.User, error) {
 	m.ctrl.T.Helper()
@@ -214,19 +317,34 @@ func (mr *MockServiceMockRecorder) UnfollowTo(arg0, arg1, arg2 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowTo", reflect.TypeOf((*MockService)(nil).UnfollowTo), arg0, arg1, arg2)
 }
 
+// Unsuspend mocks base method
+func (m *MockService) Unsuspend(arg0 context.Context, arg1, arg2 string, arg3 user.Role) (user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsuspend", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unsuspend indicates an expected call of Unsuspend
+func (mr *MockServiceMockRecorder) Unsuspend(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsuspend", reflect.TypeOf((*MockService)(nil).Unsuspend), arg0, arg1, arg2, arg3)
+}
+
 // Update mocks base method
-func (m *MockService) Update(arg0 context.Context, arg1, arg2 string, arg3 user.Role, arg4 *user.User) (user.User, error) {
+func (m *MockService) Update(arg0 context.Context, arg1, arg2 string, arg3 user.Role, arg4 *user.User, arg5 int64) (user.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "Update", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(user.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Update indicates an expected call of Update
-func (mr *MockServiceMockRecorder) Update(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // WithPagination mocks base method
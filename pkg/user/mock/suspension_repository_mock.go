@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/Zucke/social_prove/pkg/user (interfaces: SuspensionRepository)
+
+// Package mock_user is a generated GoMock package.
+package mock_user
+
+import (
+	context "context"
+	user "github.com/Zucke/social_prove/pkg/user"
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+	reflect "reflect"
+)
+
+// MockSuspensionRepository is a mock of SuspensionRepository interface
+type MockSuspensionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSuspensionRepositoryMockRecorder
+}
+
+// MockSuspensionRepositoryMockRecorder is the mock recorder for MockSuspensionRepository
+type MockSuspensionRepositoryMockRecorder struct {
+	mock *MockSuspensionRepository
+}
+
+// NewMockSuspensionRepository creates a new mock instance
+func NewMockSuspensionRepository(ctrl *gomock.Controller) *MockSuspensionRepository {
+	mock := &MockSuspensionRepository{ctrl: ctrl}
+	mock.recorder = &MockSuspensionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSuspensionRepository) EXPECT() *MockSuspensionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockSuspensionRepository) Create(arg0 context.Context, arg1 *user.SuspensionRecord) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockSuspensionRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSuspensionRepository)(nil).Create), arg0, arg1)
+}
+
+// Erase mocks base method
+func (m *MockSuspensionRepository) Erase(arg0 context.Context, arg1 primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erase", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Erase indicates an expected call of Erase
+func (mr *MockSuspensionRepositoryMockRecorder) Erase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erase", reflect.TypeOf((*MockSuspensionRepository)(nil).Erase), arg0, arg1)
+}
+
+// GetAll mocks base method
+func (m *MockSuspensionRepository) GetAll(arg0 context.Context, arg1 primitive.ObjectID) ([]user.SuspensionRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", arg0, arg1)
+	ret0, _ := ret[0].([]user.SuspensionRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll
+func (mr *MockSuspensionRepositoryMockRecorder) GetAll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockSuspensionRepository)(nil).GetAll), arg0, arg1)
+}
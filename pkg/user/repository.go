@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -9,7 +10,7 @@ import (
 // Repository handle the CRUD operations with Users.
 type Repository interface {
 	Create(ctx context.Context, u *User) error
-	Update(ctx context.Context, role Role, id primitive.ObjectID, user *User) error
+	Update(ctx context.Context, role Role, id primitive.ObjectID, user *User, version int64) error
 	GetAll(ctx context.Context) ([]User, error)
 	GetAllActive(ctx context.Context) ([]User, error)
 	GetByUID(ctx context.Context, uid string) (User, error)
@@ -17,6 +18,24 @@ type Repository interface {
 	GetByEmail(ctx context.Context, email string) (User, error)
 	FollowTo(ctx context.Context, followingID primitive.ObjectID, followerID primitive.ObjectID) error
 	UnfollowTo(ctx context.Context, followingID primitive.ObjectID, followerID primitive.ObjectID) error
-	Delete(ctx context.Context, role Role, id primitive.ObjectID) error
+	Delete(ctx context.Context, role Role, id primitive.ObjectID, at time.Time) error
+	Restore(ctx context.Context, role Role, id primitive.ObjectID) (time.Time, error)
 	GetByRole(ctx context.Context, role Role) ([]User, error)
+	GetPurgeable(ctx context.Context, before time.Time) ([]primitive.ObjectID, error)
+	GetFollowers(ctx context.Context, id primitive.ObjectID) ([]User, error)
+	HideReferences(ctx context.Context, id primitive.ObjectID) error
+	RestoreReferences(ctx context.Context, id primitive.ObjectID) error
+	RemoveReferences(ctx context.Context, id primitive.ObjectID) error
+	Purge(ctx context.Context, id primitive.ObjectID) error
+	Activate(ctx context.Context, role Role, id primitive.ObjectID) error
+	Deactivate(ctx context.Context, role Role, id primitive.ObjectID, by primitive.ObjectID) error
+	Suspend(ctx context.Context, role Role, id primitive.ObjectID, s Suspension) error
+	Unsuspend(ctx context.Context, role Role, id primitive.ObjectID) error
+}
+
+// SuspensionRepository handle the audit of the suspensions.
+type SuspensionRepository interface {
+	Create(ctx context.Context, r *SuspensionRecord) error
+	GetAll(ctx context.Context, userID primitive.ObjectID) ([]SuspensionRecord, error)
+	Erase(ctx context.Context, userID primitive.ObjectID) error
 }
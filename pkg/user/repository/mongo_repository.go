@@ -11,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/patch"
 	"github.com/Zucke/social_prove/pkg/response"
 	"github.com/Zucke/social_prove/pkg/user"
 )
@@ -25,7 +26,7 @@ type Repository struct {
 func (r *Repository) Create(ctx context.Context, u *user.User) error {
 	_, err := r.coll.InsertOne(ctx, u)
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return err
 	}
 
@@ -35,16 +36,16 @@ func (r *Repository) Create(ctx context.Context, u *user.User) error {
 // GetByEmail returns a user by email address.
 func (r *Repository) GetByEmail(ctx context.Context, email string) (user.User, error) {
 	u := user.User{}
-	result := r.coll.FindOne(ctx, bson.M{"email": email})
+	result := r.coll.FindOne(ctx, notDeleted(bson.M{"email": email}))
 
 	if result.Err() != nil {
-		r.log.Error(result.Err().Error())
+		r.log.WithContext(ctx).Error(result.Err().Error())
 		return user.User{}, response.ErrorNotFound
 
 	}
 	err := result.Decode(&u)
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrorInternalServerError
 	}
 
@@ -54,15 +55,15 @@ func (r *Repository) GetByEmail(ctx context.Context, email string) (user.User, e
 // GetByUID returns a user by UID.
 func (r *Repository) GetByUID(ctx context.Context, uid string) (user.User, error) {
 	u := user.User{}
-	result := r.coll.FindOne(ctx, bson.M{"uid": uid})
+	result := r.coll.FindOne(ctx, notDeleted(bson.M{"uid": uid}))
 	if result.Err() != nil {
-		r.log.Error(result.Err().Error())
+		r.log.WithContext(ctx).Error(result.Err().Error())
 		return user.User{}, response.ErrorNotFound
 
 	}
 	err := result.Decode(&u)
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrorInternalServerError
 	}
 	return u, nil
@@ -71,15 +72,15 @@ func (r *Repository) GetByUID(ctx context.Context, uid string) (user.User, error
 // GetByID returns a user by ID.
 func (r *Repository) GetByID(ctx context.Context, objectID primitive.ObjectID) (user.User, error) {
 	u := user.User{}
-	result := r.coll.FindOne(ctx, bson.M{"_id": objectID})
+	result := r.coll.FindOne(ctx, notDeleted(bson.M{"_id": objectID}))
 	if result.Err() != nil {
-		r.log.Error(result.Err().Error())
+		r.log.WithContext(ctx).Error(result.Err().Error())
 		return user.User{}, response.ErrorNotFound
 
 	}
 	err := result.Decode(&u)
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrorInternalServerError
 	}
 	return u, nil
@@ -90,13 +91,13 @@ func (r *Repository) GetAll(ctx context.Context) ([]user.User, error) {
 	opt := options.Find().SetProjection(bson.M{"password": 0})
 	users := make([]user.User, 0)
 
-	cursor, err := r.coll.Find(ctx, bson.M{"role": user.Client}, opt)
+	cursor, err := r.coll.Find(ctx, notDeleted(bson.M{"role": user.Client}), opt)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return users, response.ErrorNotFound
 	}
 
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return nil, response.ErrorInternalServerError
 	}
 
@@ -105,7 +106,7 @@ func (r *Repository) GetAll(ctx context.Context) ([]user.User, error) {
 	for cursor.Next(ctx) {
 		u := user.User{}
 		if err := cursor.Decode(&u); err != nil {
-			r.log.Error(err)
+			r.log.WithContext(ctx).Error(err)
 			continue
 		}
 		users = append(users, u)
@@ -114,18 +115,27 @@ func (r *Repository) GetAll(ctx context.Context) ([]user.User, error) {
 	return users, nil
 }
 
-// GetAllActive returns all active stored users.
+// GetAllActive returns all active and not suspended stored users.
 func (r *Repository) GetAllActive(ctx context.Context) ([]user.User, error) {
 	opt := options.Find().SetProjection(bson.M{"password": 0})
 	users := make([]user.User, 0)
 
-	cursor, err := r.coll.Find(ctx, bson.M{"role": user.Client, "active": true}, opt)
+	filter := notDeleted(bson.M{
+		"role":   user.Client,
+		"active": true,
+		"$or": bson.A{
+			bson.M{"suspension": bson.M{"$exists": false}},
+			bson.M{"suspension.until": bson.M{"$lte": time.Now()}},
+		},
+	})
+
+	cursor, err := r.coll.Find(ctx, filter, opt)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return users, response.ErrorNotFound
 	}
 
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return nil, response.ErrorInternalServerError
 	}
 
@@ -134,7 +144,7 @@ func (r *Repository) GetAllActive(ctx context.Context) ([]user.User, error) {
 	for cursor.Next(ctx) {
 		u := user.User{}
 		if err := cursor.Decode(&u); err != nil {
-			r.log.Error(err)
+			r.log.WithContext(ctx).Error(err)
 			continue
 		}
 		users = append(users, u)
@@ -148,12 +158,12 @@ func (r *Repository) GetByRole(ctx context.Context, role user.Role) ([]user.User
 	opt := options.Find().SetProjection(bson.M{"password": 0})
 
 	users := make([]user.User, 0)
-	cursor, err := r.coll.Find(ctx, bson.M{"role": role}, opt)
+	cursor, err := r.coll.Find(ctx, notDeleted(bson.M{"role": role}), opt)
 	if errors.Is(err, mongo.ErrNoDocuments) {
 		return users, response.ErrorNotFound
 	}
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return nil, response.ErrorInternalServerError
 	}
 
@@ -162,7 +172,7 @@ func (r *Repository) GetByRole(ctx context.Context, role user.Role) ([]user.User
 	for cursor.Next(ctx) {
 		u := user.User{}
 		if err := cursor.Decode(&u); err != nil {
-			r.log.Error(err)
+			r.log.WithContext(ctx).Error(err)
 			continue
 		}
 		users = append(users, u)
@@ -171,47 +181,56 @@ func (r *Repository) GetByRole(ctx context.Context, role user.Role) ([]user.User
 	return users, nil
 }
 
-// Update user by ID.
-func (r *Repository) Update(ctx context.Context, role user.Role, id primitive.ObjectID, u *user.User) error {
-	var filter bson.M
+// Update update the profile of a user the role can manage. Without
+// patch.AnyVersion only the given version is updated, the version is
+// increased on every update.
+func (r *Repository) Update(ctx context.Context, role user.Role, id primitive.ObjectID, u *user.User, version int64) error {
+	update := bson.M{
+		"$set": bson.M{
+			"first_name": u.FirstName,
+			"last_name":  u.LastName,
+			"country":    u.Country,
+			"state":      u.State,
+			"city":       u.City,
+			"bio":        u.Bio,
+			"picture":    u.Picture,
+			"updated_at": time.Now(),
+		},
+		"$inc": bson.M{"version": 1},
+	}
 
-	switch role {
-	case user.Client:
-		filter = bson.M{
-			"_id":  id,
-			"role": user.Client,
-		}
+	filter := withVersion(notDeleted(roleFilter(role, id)), version)
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
 
-	case user.Admin:
-		filter = bson.M{
-			"_id":  id,
-			"role": user.Client,
-		}
+	if result.MatchedCount == 0 {
+		return r.missed(ctx, notDeleted(roleFilter(role, id)), version)
+	}
 
-	case user.Super:
-		filter = bson.M{
-			"_id": id,
-		}
+	return nil
+}
+
+// missed tells why a conditional update matched nothing, the user is gone
+// or it's in another version.
+func (r *Repository) missed(ctx context.Context, filter bson.M, version int64) error {
+	if version == patch.AnyVersion {
+		return response.ErrorNotFound
 	}
 
-	update := bson.M{
-		"first_name": u.FirstName,
-		"last_name":  u.LastName,
-		"country":    u.Country,
-		"state":      u.State,
-		"city":       u.City,
-		"bio":        u.Bio,
-		"picture":    u.Picture,
-		"updated_at": time.Now(),
-	}
-
-	sr := r.coll.FindOneAndUpdate(ctx, filter, bson.M{"$set": update})
-	if err := sr.Err(); err != nil {
-		r.log.Error(err)
-		return err
+	n, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
 	}
 
-	return nil
+	if n == 0 {
+		return response.ErrorNotFound
+	}
+
+	return response.ErrPreconditionFailed
 }
 
 // FollowTo add a user id to the following array if not exist.
@@ -225,7 +244,7 @@ func (r *Repository) FollowTo(ctx context.Context, followingID primitive.ObjectI
 	}
 	result := r.coll.FindOneAndUpdate(ctx, filter, update)
 	if err := result.Err(); err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return response.ErrorInternalServerError
 	}
 
@@ -243,34 +262,302 @@ func (r *Repository) UnfollowTo(ctx context.Context, followingID primitive.Objec
 	}
 	result := r.coll.FindOneAndUpdate(ctx, filter, update)
 	if err := result.Err(); err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return response.ErrorInternalServerError
 	}
 
 	return nil
 }
 
-// Delete remove a user by ID.
-func (r *Repository) Delete(ctx context.Context, role user.Role, id primitive.ObjectID) error {
-	var filter bson.M
-	switch role {
-	case user.Admin:
-		filter = bson.M{"_id": id, "role": user.Client}
+// notDeleted add to the filter the condition to skip the deleted users.
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$exists": false}
+
+	return filter
+}
+
+// withVersion add the version required by an update to filter, the users
+// never updated have no version.
+func withVersion(filter bson.M, version int64) bson.M {
+	switch version {
+	case patch.AnyVersion:
+	case 0:
+		filter["version"] = bson.M{"$exists": false}
+	default:
+		filter["version"] = version
+	}
+
+	return filter
+}
 
+// roleFilter returns the filter of the users that the role can manage.
+func roleFilter(role user.Role, id primitive.ObjectID) bson.M {
+	switch role {
 	case user.Super:
-		filter = bson.M{"_id": id}
+		return bson.M{"_id": id}
+	default:
+		return bson.M{"_id": id, "role": user.Client}
+	}
+}
+
+// Delete mark a user as deleted at the given time, the user is kept until
+// it's purged.
+func (r *Repository) Delete(ctx context.Context, role user.Role, id primitive.ObjectID, at time.Time) error {
+	update := bson.M{
+		"$set": bson.M{"deleted_at": at},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, notDeleted(roleFilter(role, id)), update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if result.MatchedCount == 0 {
+		return response.ErrorNotFound
+	}
+
+	return nil
+}
+
+// Restore undo the delete of a user and returns when it was deleted.
+func (r *Repository) Restore(ctx context.Context, role user.Role, id primitive.ObjectID) (time.Time, error) {
+	filter := roleFilter(role, id)
+	filter["deleted_at"] = bson.M{"$exists": true}
+
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	u := user.User{}
+	err := r.coll.FindOneAndUpdate(ctx, filter, update).Decode(&u)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, response.ErrorNotFound
+	}
+
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return time.Time{}, response.ErrorInternalServerError
+	}
+
+	return *u.DeletedAt, nil
+}
+
+// GetPurgeable returns the IDs of the users deleted before the given time.
+func (r *Repository) GetPurgeable(ctx context.Context, before time.Time) ([]primitive.ObjectID, error) {
+	opt := options.Find().SetProjection(bson.M{"_id": 1})
+	ids := make([]primitive.ObjectID, 0)
+
+	cursor, err := r.coll.Find(ctx, bson.M{"deleted_at": bson.M{"$lt": before}}, opt)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		u := user.User{}
+		if err := cursor.Decode(&u); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		ids = append(ids, u.ID)
+	}
+
+	return ids, nil
+}
+
+// GetFollowers returns the users following a user.
+func (r *Repository) GetFollowers(ctx context.Context, id primitive.ObjectID) ([]user.User, error) {
+	opt := options.Find().SetProjection(bson.M{
+		"first_name": 1,
+		"last_name":  1,
+		"picture":    1,
+	})
+	users := make([]user.User, 0)
+
+	cursor, err := r.coll.Find(ctx, notDeleted(bson.M{"following": id}), opt)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
 
+	for cursor.Next(ctx) {
+		u := user.User{}
+		if err := cursor.Decode(&u); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		users = append(users, u)
+	}
+
+	return users, nil
+}
+
+// HideReferences move a deleted user id from the following array of every
+// user to their hidden one, until it's restored or purged.
+func (r *Repository) HideReferences(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.coll.UpdateMany(ctx, bson.M{"following": id}, bson.M{
+		"$pull":     bson.M{"following": id},
+		"$addToSet": bson.M{"hidden_following": id},
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// RestoreReferences move back a restored user id to the following array of
+// the users it was hidden from.
+func (r *Repository) RestoreReferences(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.coll.UpdateMany(ctx, bson.M{"hidden_following": id}, bson.M{
+		"$pull":     bson.M{"hidden_following": id},
+		"$addToSet": bson.M{"following": id},
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// RemoveReferences remove a user id from the following arrays of every
+// user and from the suspensions it issued.
+func (r *Repository) RemoveReferences(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"following": id},
+		bson.M{"hidden_following": id},
+	}}
+
+	_, err := r.coll.UpdateMany(ctx, filter, bson.M{
+		"$pull": bson.M{"following": id, "hidden_following": id},
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	_, err = r.coll.UpdateMany(ctx, bson.M{"suspension.by": id}, bson.M{
+		"$unset": bson.M{"suspension.by": ""},
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// Purge remove for good a deleted user by ID.
+func (r *Repository) Purge(ctx context.Context, id primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":        id,
+		"deleted_at": bson.M{"$exists": true},
 	}
 
 	_, err := r.coll.DeleteOne(ctx, filter)
 	if err != nil {
-		r.log.Error(err)
+		r.log.WithContext(ctx).Error(err)
 		return response.ErrorInternalServerError
 	}
 
 	return nil
 }
 
+// Activate activate a user by ID, if the role can manage it.
+func (r *Repository) Activate(ctx context.Context, role user.Role, id primitive.ObjectID) error {
+	update := bson.M{
+		"$set": bson.M{
+			"active":     true,
+			"updated_at": time.Now(),
+		},
+		"$unset": bson.M{"deactivated_by": ""},
+	}
+
+	return r.setActive(ctx, role, id, update)
+}
+
+// Deactivate deactivate a user by ID, if the role can manage it, and keeps
+// who did it.
+func (r *Repository) Deactivate(ctx context.Context, role user.Role, id primitive.ObjectID, by primitive.ObjectID) error {
+	update := bson.M{
+		"$set": bson.M{
+			"active":         false,
+			"deactivated_by": by,
+			"updated_at":     time.Now(),
+		},
+	}
+
+	return r.setActive(ctx, role, id, update)
+}
+
+// setActive apply the update of the active state to a user.
+func (r *Repository) setActive(ctx context.Context, role user.Role, id primitive.ObjectID, update bson.M) error {
+	result, err := r.coll.UpdateOne(ctx, notDeleted(roleFilter(role, id)), update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if result.MatchedCount == 0 {
+		return response.ErrorNotFound
+	}
+
+	return nil
+}
+
+// Suspend set the suspension of a user, replacing the previous one.
+func (r *Repository) Suspend(ctx context.Context, role user.Role, id primitive.ObjectID, s user.Suspension) error {
+	update := bson.M{
+		"$set": bson.M{
+			"suspension": s,
+			"updated_at": time.Now(),
+		},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, notDeleted(roleFilter(role, id)), update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if result.MatchedCount == 0 {
+		return response.ErrorNotFound
+	}
+
+	return nil
+}
+
+// Unsuspend lift the suspension of a user.
+func (r *Repository) Unsuspend(ctx context.Context, role user.Role, id primitive.ObjectID) error {
+	filter := notDeleted(roleFilter(role, id))
+	filter["suspension"] = bson.M{"$exists": true}
+
+	update := bson.M{
+		"$unset": bson.M{"suspension": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	if result.MatchedCount == 0 {
+		return response.ErrorNotFound
+	}
+
+	return nil
+}
+
 // Mongo create a new Repository.
 func Mongo(coll *mongo.Collection, log logger.Logger) user.Repository {
 	return &Repository{
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+func TestRepository_Deactivate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	id := primitive.NewObjectID()
+	by := primitive.NewObjectID()
+
+	tests := []struct {
+		name    string
+		role    user.Role
+		matched int
+		filter  bool
+		err     error
+	}{
+		{
+			name:    "Super",
+			role:    user.Super,
+			matched: 1,
+		},
+		{
+			name:    "Admin client",
+			role:    user.Admin,
+			matched: 1,
+			filter:  true,
+		},
+		{
+			// An admin doesn't match the admins and supers.
+			name:   "Admin super",
+			role:   user.Admin,
+			filter: true,
+			err:    response.ErrorNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		mt.Run(test.name, func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(
+				bson.E{Key: "n", Value: test.matched},
+				bson.E{Key: "nModified", Value: test.matched},
+			))
+			r := Mongo(mt.Coll, logger.NewMock())
+
+			assert.Equal(mt, test.err, r.Deactivate(context.Background(), test.role, id, by))
+
+			update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+			assert.Equal(mt, by, update.Lookup("u", "$set", "deactivated_by").ObjectID())
+
+			q := update.Lookup("q").Document()
+			assert.Equal(mt, id, q.Lookup("_id").ObjectID())
+			role, ok := q.Lookup("role").Int64OK()
+			assert.Equal(mt, test.filter, ok)
+			if test.filter {
+				assert.Equal(mt, int64(user.Client), role)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/user"
+)
+
+// SuspensionRepository storage to the suspension records, it's append only.
+type SuspensionRepository struct {
+	coll *mongo.Collection
+	log  logger.Logger
+}
+
+// Create store a new suspension record.
+func (r *SuspensionRepository) Create(ctx context.Context, rec *user.SuspensionRecord) error {
+	_, err := r.coll.InsertOne(ctx, rec)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrCouldNotInsert
+	}
+
+	return nil
+}
+
+// GetAll returns the suspension records of a user, the newest first.
+func (r *SuspensionRepository) GetAll(ctx context.Context, userID primitive.ObjectID) ([]user.SuspensionRecord, error) {
+	opt := options.Find().SetSort(bson.M{"created_at": -1})
+	records := make([]user.SuspensionRecord, 0)
+
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID}, opt)
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return nil, response.ErrorInternalServerError
+	}
+
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		rec := user.SuspensionRecord{}
+		if err := cursor.Decode(&rec); err != nil {
+			r.log.WithContext(ctx).Error(err)
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// Erase remove the records of a user and unset it as the actor of the
+// others, which are kept for the audit.
+func (r *SuspensionRepository) Erase(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.coll.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	_, err = r.coll.UpdateMany(ctx, bson.M{"actor_id": userID}, bson.M{
+		"$unset": bson.M{"actor_id": ""},
+	})
+	if err != nil {
+		r.log.WithContext(ctx).Error(err)
+		return response.ErrorInternalServerError
+	}
+
+	return nil
+}
+
+// Suspensions create a new SuspensionRepository.
+func Suspensions(coll *mongo.Collection, log logger.Logger) user.SuspensionRepository {
+	return &SuspensionRepository{
+		coll: coll,
+		log:  log,
+	}
+}
@@ -5,8 +5,10 @@ import "context"
 // Service the user service.
 type Service interface {
 	Create(ctx context.Context, u *User) error
+	CreateAdmin(ctx context.Context, u *User) error
 	LoginUser(ctx context.Context, u *User) (*User, string, error)
-	Update(ctx context.Context, toUpdateid string, currendUserID string, role Role, u *User) (User, error)
+	Update(ctx context.Context, toUpdateid string, currendUserID string, role Role, u *User, version int64) (User, error)
+	Patch(ctx context.Context, toUpdateid string, currendUserID string, role Role, p []byte, version int64) (User, error)
 	GetByEmail(ctx context.Context, email string) (User, error)
 	GetByUID(ctx context.Context, uid string) (User, error)
 	GetByID(ctx context.Context, id string) (User, error)
@@ -15,6 +17,12 @@ type Service interface {
 	FollowTo(ctx context.Context, followingID string, followerID string) (User, error)
 	UnfollowTo(ctx context.Context, followingID string, followerID string) (User, error)
 	Delete(ctx context.Context, role Role, id string) error
+	Restore(ctx context.Context, role Role, id string) (User, error)
+	Deactivate(ctx context.Context, id string, currendUserID string, role Role) error
+	Activate(ctx context.Context, role Role, id string) (User, error)
+	Suspend(ctx context.Context, id string, actorID string, role Role, s Suspension) (User, error)
+	Unsuspend(ctx context.Context, id string, actorID string, role Role) (User, error)
+	GetSuspensions(ctx context.Context, id string) ([]SuspensionRecord, error)
 	GetByRole(ctx context.Context, role Role) ([]User, error)
 	FirebaseAuth(ctx context.Context, uid string) (*User, string, error)
 	WithPagination(users []User, page int, limit int) ([]User, int)
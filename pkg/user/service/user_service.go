@@ -2,42 +2,70 @@ package service
 
 import (
 	"context"
-	"os"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 
+	"github.com/Zucke/social_prove/pkg/audit"
 	"github.com/Zucke/social_prove/pkg/auth"
 	"github.com/Zucke/social_prove/pkg/claim"
+	"github.com/Zucke/social_prove/pkg/comment"
+	commentrepository "github.com/Zucke/social_prove/pkg/comment/repository"
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/metrics"
+	"github.com/Zucke/social_prove/pkg/patch"
+	"github.com/Zucke/social_prove/pkg/post"
+	postrepository "github.com/Zucke/social_prove/pkg/post/repository"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	reactionrepository "github.com/Zucke/social_prove/pkg/reaction/repository"
 	"github.com/Zucke/social_prove/pkg/response"
+	"github.com/Zucke/social_prove/pkg/tracing"
 	"github.com/Zucke/social_prove/pkg/user"
 	"github.com/Zucke/social_prove/pkg/user/repository"
 )
 
-const waitTime = 10
-
 // UserService the user service.
 type UserService struct {
-	repository   user.Repository
-	firebaseRepo auth.Repository
-	log          logger.Logger
+	repository    user.Repository
+	posts         post.Repository
+	comments      comment.Repository
+	reactions     reaction.Repository
+	suspensions   user.SuspensionRepository
+	firebaseRepo  auth.Repository
+	auditor       audit.Recorder
+	signingString string
+	timeout       time.Duration
+	log           logger.Logger
 }
 
-// Create create a new user.
+// Create sign up a new user.
 func (us *UserService) Create(ctx context.Context, u *user.User) error {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.Create")
+	defer span.End()
+
+	if err := us.create(ctx, u); err != nil {
+		return err
+	}
+
+	metrics.Signups.WithLabelValues(metrics.Password).Inc()
+	return nil
+}
+
+// create create a new user.
+func (us *UserService) create(ctx context.Context, u *user.User) error {
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
-	if !u.ValidateEmail() {
-		return response.ErrInvalidEmail
+	if err := u.Validate(); err != nil {
+		return err
 	}
 
 	if u.Password != "" {
 		err := u.EncryptPassword()
 		if err != nil {
-			us.log.Error(err)
+			us.log.WithContext(ctx).Error(err)
 			return response.ErrCouldNotInsert
 		}
 	}
@@ -51,39 +79,63 @@ func (us *UserService) Create(ctx context.Context, u *user.User) error {
 	u.UpdatedAt = time.Now()
 
 	if err := us.repository.Create(ctx, u); err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return response.ErrCouldNotInsert
 	}
 	u.Password = ""
 	return nil
 }
 
+// CreateAdmin create a new admin.
+func (us *UserService) CreateAdmin(ctx context.Context, u *user.User) error {
+	ctx, span := tracing.Start(ctx, "UserService.CreateAdmin")
+	defer span.End()
+
+	u.Role = user.Admin
+	if err := us.create(ctx, u); err != nil {
+		return err
+	}
+
+	us.auditAction(ctx, audit.CreateAdmin, u.ID, nil, u)
+
+	return nil
+}
+
 //FirebaseAuth service for firebase auth
 func (us *UserService) FirebaseAuth(ctx context.Context, uid string) (*user.User, string, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.FirebaseAuth")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 	u, err := us.GetByUID(ctx, uid)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		u, err = us.firebaseRepo.GetFirebaseUser(ctx, uid)
 		if err != nil {
-			us.log.Error(err)
+			us.log.WithContext(ctx).Error(err)
 			return &user.User{}, "", response.ErrorNotFound
 		}
-		err = us.Create(ctx, &u)
+		err = us.create(ctx, &u)
 
 		if err != nil {
-			us.log.Error(err)
+			us.log.WithContext(ctx).Error(err)
 			return &user.User{}, "", err
 		}
+		metrics.Signups.WithLabelValues(metrics.Google).Inc()
 
 	}
 
-	tokenString, err := claim.GenerateToken(os.Getenv("SIGNING_STRING"), u.ID.Hex(), uint(u.Role))
+	if err := us.admit(ctx, &u); err != nil {
+		return &user.User{}, "", err
+	}
+
+	tokenString, err := claim.GenerateToken(us.signingString, u.ID.Hex(), uint(u.Role))
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return &user.User{}, "", response.ErrorInternalServerError
 	}
+	metrics.Logins.WithLabelValues(metrics.Google).Inc()
 
 	return &u, tokenString, nil
 
@@ -91,6 +143,9 @@ func (us *UserService) FirebaseAuth(ctx context.Context, uid string) (*user.User
 
 //LoginUser evaluate a user and return if it a valid login and it token
 func (us *UserService) LoginUser(ctx context.Context, u *user.User) (*user.User, string, error) {
+	ctx, span := tracing.Start(ctx, "UserService.LoginUser")
+	defer span.End()
+
 	var tokenString string
 
 	if !u.ValidateEmail() {
@@ -99,32 +154,59 @@ func (us *UserService) LoginUser(ctx context.Context, u *user.User) (*user.User,
 	matchUser, err := us.GetByEmail(ctx, u.Email)
 
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return &user.User{}, "", err
 	}
 
-	tokenString, err = claim.GenerateToken(os.Getenv("SIGNING_STRING"), matchUser.ID.Hex(), uint(matchUser.Role))
-	if err != nil {
-		us.log.Error(err)
-		return &user.User{}, "", response.ErrorInternalServerError
-	}
-
 	if !matchUser.ComparePassword(u.Password) {
 		return &user.User{}, "", response.ErrorBadEmailOrPassword
 	}
 
+	if err := us.admit(ctx, &matchUser); err != nil {
+		return &user.User{}, "", err
+	}
+
+	tokenString, err = claim.GenerateToken(us.signingString, matchUser.ID.Hex(), uint(matchUser.Role))
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return &user.User{}, "", response.ErrorInternalServerError
+	}
+	metrics.Logins.WithLabelValues(metrics.Password).Inc()
+
 	return &matchUser, tokenString, nil
 
 }
 
+// admit check a user can get a token, a suspended user can't and a user
+// that deactivated itself is reactivated by logging in again. A user
+// deactivated by an admin stays so until an admin activates it.
+func (us *UserService) admit(ctx context.Context, u *user.User) error {
+	err := u.CheckStatus(time.Now())
+	if errors.Is(err, user.ErrDeactivated) && u.DeactivatedItself() {
+		// The user reactivates itself, whatever its role.
+		if err := us.repository.Activate(ctx, user.Super, u.ID); err != nil {
+			us.log.WithContext(ctx).Error(err)
+			return err
+		}
+		u.Active = true
+		u.DeactivatedBy = primitive.NilObjectID
+		return nil
+	}
+
+	return err
+}
+
 // GetByEmail returns a user by email address.
 func (us *UserService) GetByEmail(ctx context.Context, email string) (user.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.GetByEmail")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	u, err := us.repository.GetByEmail(ctx, email)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, err
 	}
 
@@ -133,18 +215,21 @@ func (us *UserService) GetByEmail(ctx context.Context, email string) (user.User,
 
 // GetByID returns a user by ID.
 func (us *UserService) GetByID(ctx context.Context, id string) (user.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.GetByID")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrInvalidID
 	}
 
 	u, err := us.repository.GetByID(ctx, objectID)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, err
 	}
 
@@ -153,12 +238,15 @@ func (us *UserService) GetByID(ctx context.Context, id string) (user.User, error
 
 // GetAll returns all stored users.
 func (us *UserService) GetAll(ctx context.Context) ([]user.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.GetAll")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	users, err := us.repository.GetAll(ctx)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return nil, err
 	}
 
@@ -167,12 +255,15 @@ func (us *UserService) GetAll(ctx context.Context) ([]user.User, error) {
 
 // GetByRole return a list of users by role.
 func (us *UserService) GetByRole(ctx context.Context, role user.Role) ([]user.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.GetByRole")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	users, err := us.repository.GetByRole(ctx, role)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return nil, err
 	}
 
@@ -181,12 +272,15 @@ func (us *UserService) GetByRole(ctx context.Context, role user.Role) ([]user.Us
 
 // GetAllActive returns all active stored users.
 func (us *UserService) GetAllActive(ctx context.Context) ([]user.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.GetAllActive")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	users, err := us.repository.GetAllActive(ctx)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return nil, err
 	}
 
@@ -195,21 +289,28 @@ func (us *UserService) GetAllActive(ctx context.Context) ([]user.User, error) {
 
 // GetByUID returns a user by UID.
 func (us *UserService) GetByUID(ctx context.Context, uid string) (user.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.GetByUID")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	u, err := us.repository.GetByUID(ctx, uid)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, err
 	}
 
 	return u, nil
 }
 
-// Update user by ID.
-func (us *UserService) Update(ctx context.Context, toUpdateid string, currendUserID string, role user.Role, u *user.User) (user.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+// Update replace the profile of a user by ID, the fields left out are
+// emptied. Without patch.AnyVersion the user must be in the given version.
+func (us *UserService) Update(ctx context.Context, toUpdateid string, currendUserID string, role user.Role, u *user.User, version int64) (user.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Update")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	if role == user.Client {
@@ -220,28 +321,90 @@ func (us *UserService) Update(ctx context.Context, toUpdateid string, currendUse
 
 	objectID, err := primitive.ObjectIDFromHex(toUpdateid)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrInvalidID
 	}
 
-	err = us.repository.Update(ctx, role, objectID, u)
+	if err := u.ValidateProfile(); err != nil {
+		return user.User{}, err
+	}
+
+	// The updates of the admins are audited with the user as it was.
+	var before *user.User
+	if role != user.Client {
+		old, err := us.repository.GetByID(ctx, objectID)
+		if err != nil {
+			us.log.WithContext(ctx).Error(err)
+			return user.User{}, err
+		}
+		before = &old
+	}
+
+	err = us.repository.Update(ctx, role, objectID, u, version)
+	if errors.Is(err, response.ErrorNotFound) || errors.Is(err, response.ErrPreconditionFailed) {
+		return user.User{}, err
+	}
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrorInternalServerError
 	}
 	updatedUser, err := us.GetByID(ctx, toUpdateid)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, err
 	}
 
+	if before != nil {
+		us.auditAction(ctx, audit.UpdateUser, objectID, before, &updatedUser)
+	}
+
 	return updatedUser, nil
 
 }
 
+// Patch apply a JSON merge patch to the profile of a user by ID, only the
+// fields in the patch are changed. Without patch.AnyVersion the user must
+// be in the given version.
+func (us *UserService) Patch(ctx context.Context, toUpdateid string, currendUserID string, role user.Role, p []byte, version int64) (user.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Patch")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
+	defer cancel()
+
+	if role == user.Client && currendUserID != toUpdateid {
+		return user.User{}, response.ErrorUnauthorized
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(toUpdateid)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, response.ErrInvalidID
+	}
+
+	u, err := us.repository.GetByID(ctx, objectID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	if version != patch.AnyVersion && version != u.Version {
+		return user.User{}, response.ErrPreconditionFailed
+	}
+
+	if err := patch.Apply(&u, p, user.Editable...); err != nil {
+		return user.User{}, err
+	}
+
+	return us.Update(ctx, toUpdateid, currendUserID, role, &u, version)
+}
+
 // FollowTo add user to the following list
 func (us *UserService) FollowTo(ctx context.Context, followingID string, followerID string) (user.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.FollowTo")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	var u user.User
@@ -251,25 +414,26 @@ func (us *UserService) FollowTo(ctx context.Context, followingID string, followe
 
 	followingObjectID, err := primitive.ObjectIDFromHex(followingID)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrInvalidID
 	}
 	followerObjectID, err := primitive.ObjectIDFromHex(followerID)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrInvalidID
 	}
 
 	err = us.repository.FollowTo(ctx, followingObjectID, followerObjectID)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, err
 	}
+	metrics.Follows.Inc()
 
 	u, err = us.GetByID(ctx, followerID)
 
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, err
 	}
 
@@ -278,7 +442,10 @@ func (us *UserService) FollowTo(ctx context.Context, followingID string, followe
 
 // UnfollowTo delete user of the following list
 func (us *UserService) UnfollowTo(ctx context.Context, followingID string, followerID string) (user.User, error) {
-	ctx, cancel := context.WithTimeout(ctx, waitTime*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.UnfollowTo")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	var u user.User
@@ -288,49 +455,430 @@ func (us *UserService) UnfollowTo(ctx context.Context, followingID string, follo
 
 	followingObjectID, err := primitive.ObjectIDFromHex(followingID)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrInvalidID
 	}
 	followerObjectID, err := primitive.ObjectIDFromHex(followerID)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, response.ErrInvalidID
 	}
 
 	err = us.repository.UnfollowTo(ctx, followingObjectID, followerObjectID)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return user.User{}, err
 	}
 
 	u, err = us.GetByID(ctx, followerID)
 
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return u, err
 	}
 
 	return u, nil
 }
 
-// Delete remove a user by ID.
+// Delete mark a user and its posts as deleted, they can be restored until
+// they're purged. Its reposts, its reactions and the follows to it leave the
+// counters and lists meanwhile.
 func (us *UserService) Delete(ctx context.Context, role user.Role, id string) error {
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	ctx, span := tracing.Start(ctx, "UserService.Delete")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return response.ErrInvalidID
 	}
-	err = us.repository.Delete(ctx, role, objectID)
+
+	before, err := us.repository.GetByID(ctx, objectID)
 	if err != nil {
-		us.log.Error(err)
+		us.log.WithContext(ctx).Error(err)
 		return err
 	}
+
+	now := time.Now()
+	err = us.repository.Delete(ctx, role, objectID, now)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	originals, err := us.posts.DeleteAllForUser(ctx, objectID, now)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	err = us.countReposts(ctx, originals, -1)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	err = us.repository.HideReferences(ctx, objectID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	reactions, err := us.reactions.HideAllForUser(ctx, objectID, now)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	err = us.countReactions(ctx, reactions, -1)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	us.auditAction(ctx, audit.DeleteUser, objectID, &before, nil)
+
 	return nil
 }
 
+// Restore undo the delete of a user and of the posts deleted with it, its
+// reposts, its reactions and the follows to it count again.
+func (us *UserService) Restore(ctx context.Context, role user.Role, id string) (user.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Restore")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, response.ErrInvalidID
+	}
+
+	deletedAt, err := us.repository.Restore(ctx, role, objectID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	originals, err := us.posts.RestoreAllForUser(ctx, objectID, deletedAt)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	err = us.countReposts(ctx, originals, 1)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	err = us.repository.RestoreReferences(ctx, objectID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	reactions, err := us.reactions.RestoreAllForUser(ctx, objectID, deletedAt)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	err = us.countReactions(ctx, reactions, 1)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	u, err := us.GetByID(ctx, id)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	us.auditAction(ctx, audit.RestoreUser, objectID, nil, &u)
+
+	return u, nil
+}
+
+// countReposts add n to the reposts counters of the originals, the
+// originals already purged are skipped.
+func (us *UserService) countReposts(ctx context.Context, originals []primitive.ObjectID, n int) error {
+	for _, id := range originals {
+		err := us.posts.IncReposts(ctx, id, n)
+		if err != nil && !errors.Is(err, response.ErrorNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countReactions add n to the counters of the targets of the reactions,
+// the targets already purged are skipped.
+func (us *UserService) countReactions(ctx context.Context, reactions []reaction.Reaction, n int) error {
+	for _, re := range reactions {
+		counts := map[reaction.Type]int{re.Type: n}
+
+		var err error
+		switch re.Target {
+		case reaction.Post:
+			err = us.posts.IncReactions(ctx, re.TargetID, counts)
+		case reaction.Comment:
+			err = us.comments.IncReactions(ctx, re.TargetID, counts)
+		}
+		if err != nil && !errors.Is(err, response.ErrorNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deactivate deactivate a user, a client can only deactivate itself and an
+// admin the clients. A user that deactivated itself is reactivated by
+// logging in again, one deactivated by an admin can't log in until an admin
+// activates it.
+func (us *UserService) Deactivate(ctx context.Context, id string, currendUserID string, role user.Role) error {
+	ctx, span := tracing.Start(ctx, "UserService.Deactivate")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
+	defer cancel()
+
+	if role == user.Client && currendUserID != id {
+		return response.ErrorUnauthorized
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return response.ErrInvalidID
+	}
+
+	// Any user can deactivate itself.
+	scope := role
+	if currendUserID == id {
+		scope = user.Super
+	}
+
+	actorID, err := primitive.ObjectIDFromHex(currendUserID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return response.ErrInvalidID
+	}
+
+	err = us.repository.Deactivate(ctx, scope, objectID, actorID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	if role != user.Client {
+		us.auditAction(ctx, audit.DeactivateUser, objectID, nil, nil)
+	}
+
+	return nil
+}
+
+// Activate undo the deactivation of a user, the only way back for the
+// users deactivated by an admin.
+func (us *UserService) Activate(ctx context.Context, role user.Role, id string) (user.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Activate")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, response.ErrInvalidID
+	}
+
+	err = us.repository.Activate(ctx, role, objectID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	u, err := us.GetByID(ctx, id)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	us.auditAction(ctx, audit.ActivateUser, objectID, nil, &u)
+
+	return u, nil
+}
+
+// Suspend ban a user until s.Until, or for good without it, and keeps a
+// record of who did it.
+func (us *UserService) Suspend(ctx context.Context, id string, actorID string, role user.Role, s user.Suspension) (user.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Suspend")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
+	defer cancel()
+
+	if id == actorID {
+		return user.User{}, response.ErrorUnauthorized
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, response.ErrInvalidID
+	}
+	objectActorID, err := primitive.ObjectIDFromHex(actorID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, response.ErrInvalidID
+	}
+
+	now := time.Now()
+	if err := s.Validate(now); err != nil {
+		return user.User{}, err
+	}
+
+	s.By = objectActorID
+	s.CreatedAt = now
+	err = us.repository.Suspend(ctx, role, objectID, s)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	err = us.record(ctx, user.SuspensionRecord{
+		UserID:  objectID,
+		ActorID: objectActorID,
+		Action:  user.Suspend,
+		Reason:  s.Reason,
+		Until:   s.Until,
+	})
+	if err != nil {
+		return user.User{}, err
+	}
+
+	u, err := us.GetByID(ctx, id)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	us.auditAction(ctx, audit.SuspendUser, objectID, nil, &u)
+
+	return u, nil
+}
+
+// Unsuspend lift the suspension of a user and keeps a record of who did it.
+func (us *UserService) Unsuspend(ctx context.Context, id string, actorID string, role user.Role) (user.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.Unsuspend")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, response.ErrInvalidID
+	}
+	objectActorID, err := primitive.ObjectIDFromHex(actorID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, response.ErrInvalidID
+	}
+
+	err = us.repository.Unsuspend(ctx, role, objectID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return user.User{}, err
+	}
+
+	err = us.record(ctx, user.SuspensionRecord{
+		UserID:  objectID,
+		ActorID: objectActorID,
+		Action:  user.Unsuspend,
+	})
+	if err != nil {
+		return user.User{}, err
+	}
+
+	u, err := us.GetByID(ctx, id)
+	if err != nil {
+		return user.User{}, err
+	}
+
+	us.auditAction(ctx, audit.UnsuspendUser, objectID, nil, &u)
+
+	return u, nil
+}
+
+// GetSuspensions returns the suspension records of a user.
+func (us *UserService) GetSuspensions(ctx context.Context, id string) ([]user.SuspensionRecord, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetSuspensions")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, us.timeout)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return nil, response.ErrInvalidID
+	}
+
+	records, err := us.suspensions.GetAll(ctx, objectID)
+	if err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// record store the audit of a suspension action.
+func (us *UserService) record(ctx context.Context, rec user.SuspensionRecord) error {
+	rec.ID = primitive.NewObjectID()
+	rec.CreatedAt = time.Now()
+
+	if err := us.suspensions.Create(ctx, &rec); err != nil {
+		us.log.WithContext(ctx).Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// auditAction record an administrative action on a user, a failure is
+// only logged as the action is already done.
+func (us *UserService) auditAction(ctx context.Context, action audit.Action, id primitive.ObjectID, before, after *user.User) {
+	e := audit.Entry{
+		Action:     action,
+		TargetType: audit.UserTarget,
+		TargetID:   id,
+	}
+	// The raw password of a request is rendered, it never goes to the log.
+	if before != nil {
+		u := *before
+		u.Password = ""
+		e.Before = audit.Snapshot(u)
+	}
+	if after != nil {
+		u := *after
+		u.Password = ""
+		e.After = audit.Snapshot(u)
+	}
+
+	if err := us.auditor.Record(ctx, e); err != nil {
+		us.log.WithContext(ctx).Errorf("cannot audit %s of %s: %v", action, id.Hex(), err)
+	}
+}
+
 // WithPagination returns users with a pagination limit.
 func (us *UserService) WithPagination(users []user.User, page int, limit int) ([]user.User, int) {
 	if limit < 0 {
@@ -361,11 +909,18 @@ func (us *UserService) WithPagination(users []user.User, page int, limit int) ([
 	return newUsers, total
 }
 
-// New create and configure user services.
-func New(coll *mongo.Collection, log logger.Logger, firebaseRepo auth.Repository) user.Service {
+// New create and configure user services, signingString signs the tokens.
+func New(coll, postColl, commentColl, reactionColl, suspensionColl *mongo.Collection, log logger.Logger, timeout time.Duration, signingString string, firebaseRepo auth.Repository, auditor audit.Recorder) user.Service {
 	return &UserService{
-		repository:   repository.Mongo(coll, log),
-		log:          log,
-		firebaseRepo: firebaseRepo,
+		repository:    repository.Mongo(coll, log),
+		posts:         postrepository.Mongo(postColl, log),
+		comments:      commentrepository.Mongo(commentColl, log),
+		reactions:     reactionrepository.Mongo(reactionColl, log),
+		suspensions:   repository.Suspensions(suspensionColl, log),
+		log:           log,
+		timeout:       timeout,
+		firebaseRepo:  firebaseRepo,
+		auditor:       auditor,
+		signingString: signingString,
 	}
 }
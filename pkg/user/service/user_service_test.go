@@ -2,17 +2,27 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/Zucke/social_prove/pkg/audit"
+	amock "github.com/Zucke/social_prove/pkg/audit/mock"
 	fmock "github.com/Zucke/social_prove/pkg/auth/mock"
+	cmock "github.com/Zucke/social_prove/pkg/comment/mock"
 	"github.com/Zucke/social_prove/pkg/logger"
+	"github.com/Zucke/social_prove/pkg/patch"
+	pmock "github.com/Zucke/social_prove/pkg/post/mock"
+	"github.com/Zucke/social_prove/pkg/reaction"
+	rmock "github.com/Zucke/social_prove/pkg/reaction/mock"
 	"github.com/Zucke/social_prove/pkg/response"
 	"github.com/Zucke/social_prove/pkg/user"
 	mock "github.com/Zucke/social_prove/pkg/user/mock"
+	"github.com/Zucke/social_prove/pkg/validation"
 )
 
 func TestUserService_Create(t *testing.T) {
@@ -88,9 +98,19 @@ func TestUserService_Create(t *testing.T) {
 			times:  1,
 		},
 		{
-			name:  "With invalid email",
-			user:  userWithIncorrectEmail,
-			err:   response.ErrInvalidEmail,
+			name: "With invalid email",
+			user: userWithIncorrectEmail,
+			err: validation.Errors{
+				{Field: "email", Code: validation.InvalidFormat, Message: "must be an email address"},
+			},
+			times: 0,
+		},
+		{
+			name: "Without password nor firebase",
+			user: user.User{Email: "user@example.com", FirstName: "user"},
+			err: validation.Errors{
+				{Field: "password", Code: validation.Required, Message: "is required"},
+			},
 			times: 0,
 		},
 	}
@@ -264,6 +284,352 @@ func TestUserService_LoginUser(t *testing.T) {
 	}
 }
 
+func TestUserService_LoginUserStatus(t *testing.T) {
+	stored := user.User{
+		ID:       primitive.NewObjectID(),
+		Email:    "user@example.com",
+		Password: "123456",
+		Role:     user.Client,
+		Active:   true,
+	}
+	stored.EncryptPassword()
+	deactivated := stored
+	deactivated.Active = false
+	deactivated.DeactivatedBy = stored.ID
+	legacy := stored
+	legacy.Active = false
+	banned := deactivated
+	banned.DeactivatedBy = primitive.NewObjectID()
+	suspended := stored
+	suspended.Suspension = &user.Suspension{Reason: "spam"}
+
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name           string
+		stored         user.User
+		err            error
+		timesActivate int
+	}{
+		{
+			name:          "deactivated user is reactivated",
+			stored:        deactivated,
+			timesActivate: 1,
+		},
+		{
+			name:          "user deactivated without record is reactivated",
+			stored:        legacy,
+			timesActivate: 1,
+		},
+		{
+			name:   "user deactivated by an admin can't log in",
+			stored: banned,
+			err:    user.ErrDeactivated,
+		},
+		{
+			name:   "suspended user can't log in",
+			stored: suspended,
+			err:    user.ErrSuspended,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+
+			m.
+				EXPECT().
+				GetByEmail(gomock.Any(), stored.Email).
+				Return(test.stored, nil)
+			m.
+				EXPECT().
+				Activate(gomock.Any(), user.Super, stored.ID).
+				Return(nil).
+				Times(test.timesActivate)
+
+			s := UserService{
+				repository: m,
+				log:        l,
+			}
+
+			u, tokenString, err := s.LoginUser(ctx, &user.User{Email: stored.Email, Password: "123456"})
+			assert.True(t, errors.Is(err, test.err), "got %v", err)
+			if test.err == nil {
+				assert.NoError(t, err)
+				assert.True(t, u.Active)
+				assert.NotEmpty(t, tokenString)
+			}
+		})
+	}
+}
+
+func TestUserService_Suspend(t *testing.T) {
+	id := primitive.NewObjectID()
+	actorID := primitive.NewObjectID()
+	past := time.Now().Add(-time.Hour)
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name       string
+		id         string
+		suspension user.Suspension
+		suspendErr error
+		err        error
+		times      int
+		timesRec   int
+	}{
+		{
+			name:       "Success",
+			id:         id.Hex(),
+			suspension: user.Suspension{Reason: "spam"},
+			times:      1,
+			timesRec:   1,
+		},
+		{
+			name:       "Failure admin suspending an admin",
+			id:         id.Hex(),
+			suspension: user.Suspension{Reason: "spam"},
+			suspendErr: response.ErrorNotFound,
+			err:        response.ErrorNotFound,
+			times:      1,
+		},
+		{
+			name:       "Failure without reason",
+			id:         id.Hex(),
+			suspension: user.Suspension{},
+			err:        user.ErrInvalidSuspension,
+		},
+		{
+			name:       "Failure ended",
+			id:         id.Hex(),
+			suspension: user.Suspension{Reason: "spam", Until: &past},
+			err:        user.ErrInvalidSuspension,
+		},
+		{
+			name:       "Failure suspending itself",
+			id:         actorID.Hex(),
+			suspension: user.Suspension{Reason: "spam"},
+			err:        response.ErrorUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+			sm := mock.NewMockSuspensionRepository(ctrl)
+			am := amock.NewMockRecorder(ctrl)
+
+			m.
+				EXPECT().
+				Suspend(gomock.Any(), user.Admin, id, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ user.Role, _ primitive.ObjectID, s user.Suspension) error {
+					assert.Equal(t, actorID, s.By)
+					return test.suspendErr
+				}).
+				Times(test.times)
+			sm.
+				EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, rec *user.SuspensionRecord) error {
+					assert.Equal(t, id, rec.UserID)
+					assert.Equal(t, actorID, rec.ActorID)
+					assert.Equal(t, user.Suspend, rec.Action)
+					assert.Equal(t, "spam", rec.Reason)
+					return nil
+				}).
+				Times(test.timesRec)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), id).
+				Return(user.User{ID: id}, nil).
+				Times(test.timesRec)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					assert.Equal(t, audit.SuspendUser, e.Action)
+					assert.Equal(t, id, e.TargetID)
+					return nil
+				}).
+				Times(test.timesRec)
+
+			s := UserService{
+				repository:  m,
+				suspensions: sm,
+				auditor:     am,
+				log:         l,
+			}
+
+			_, err := s.Suspend(ctx, test.id, actorID.Hex(), user.Admin, test.suspension)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestUserService_Deactivate(t *testing.T) {
+	id := primitive.NewObjectID()
+	actorID := primitive.NewObjectID()
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name       string
+		actorID    string
+		role       user.Role
+		scope      user.Role
+		setErr     error
+		err        error
+		times      int
+		timesAudit int
+	}{
+		{
+			name:    "Client itself",
+			actorID: id.Hex(),
+			role:    user.Client,
+			scope:   user.Super,
+			times:   1,
+		},
+		{
+			name:    "Client other user",
+			actorID: actorID.Hex(),
+			role:    user.Client,
+			err:     response.ErrorUnauthorized,
+		},
+		{
+			name:       "Admin client",
+			actorID:    actorID.Hex(),
+			role:       user.Admin,
+			scope:      user.Admin,
+			times:      1,
+			timesAudit: 1,
+		},
+		{
+			// The repository only finds the clients for an admin.
+			name:    "Admin super",
+			actorID: actorID.Hex(),
+			role:    user.Admin,
+			scope:   user.Admin,
+			setErr:  response.ErrorNotFound,
+			err:     response.ErrorNotFound,
+			times:   1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+			// Who deactivated the user is kept.
+			by, _ := primitive.ObjectIDFromHex(test.actorID)
+			am := amock.NewMockRecorder(ctrl)
+
+			m.
+				EXPECT().
+				Deactivate(gomock.Any(), test.scope, id, by).
+				Return(test.setErr).
+				Times(test.times)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				Return(nil).
+				Times(test.timesAudit)
+
+			s := UserService{
+				repository: m,
+				auditor:    am,
+				log:        l,
+			}
+
+			err := s.Deactivate(ctx, id.Hex(), test.actorID, test.role)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
+func TestUserService_Activate(t *testing.T) {
+	id := primitive.NewObjectID()
+	us := user.User{ID: id, Email: "user@example.com", Role: user.Client, Active: true}
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name       string
+		id         string
+		role       user.Role
+		err        error
+		times      int
+		timesAudit int
+	}{
+		{
+			name:       "Admin client",
+			id:         id.Hex(),
+			role:       user.Admin,
+			times:      1,
+			timesAudit: 1,
+		},
+		{
+			// The repository only finds the clients for an admin.
+			name:  "Admin super",
+			id:    id.Hex(),
+			role:  user.Admin,
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+		{
+			name: "With invalid id",
+			id:   "123",
+			role: user.Super,
+			err:  response.ErrInvalidID,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+			am := amock.NewMockRecorder(ctrl)
+
+			m.
+				EXPECT().
+				Activate(gomock.Any(), test.role, id).
+				Return(test.err).
+				Times(test.times)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), id).
+				Return(us, nil).
+				Times(test.timesAudit)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					assert.Equal(t, audit.ActivateUser, e.Action)
+					assert.Equal(t, id, e.TargetID)
+					assert.Equal(t, true, e.After["active"])
+					return nil
+				}).
+				Times(test.timesAudit)
+
+			s := UserService{
+				repository: m,
+				auditor:    am,
+				log:        l,
+			}
+
+			u, err := s.Activate(ctx, test.role, test.id)
+			assert.Equal(t, test.err, err)
+			if test.err == nil {
+				assert.Equal(t, us, u)
+			}
+		})
+	}
+}
+
 func TestUserService_GetByID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -668,8 +1034,21 @@ func TestUserService_Delete(t *testing.T) {
 	defer ctrl.Finish()
 
 	m := mock.NewMockRepository(ctrl)
+	pm := pmock.NewMockRepository(ctrl)
+	cm := cmock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	am := amock.NewMockRecorder(ctrl)
 	id := primitive.NewObjectID()
+	originalID := primitive.NewObjectID()
+	purgedID := primitive.NewObjectID()
+	postID := primitive.NewObjectID()
+	commentID := primitive.NewObjectID()
+	reactions := []reaction.Reaction{
+		{Target: reaction.Post, TargetID: postID, UserID: id, Type: reaction.Like},
+		{Target: reaction.Comment, TargetID: commentID, UserID: id, Type: reaction.Sad},
+	}
 	us := user.User{
+		ID:        id,
 		Email:     "user@example.com",
 		Password:  "123456",
 		FirstName: "user",
@@ -681,18 +1060,20 @@ func TestUserService_Delete(t *testing.T) {
 	l := logger.NewMock()
 
 	tests := []struct {
-		name  string
-		id    string
-		user  user.User
-		err   error
-		times int
+		name       string
+		id         string
+		user       user.User
+		err        error
+		times      int
+		timesPosts int
 	}{
 		{
-			name:  "Success",
-			id:    id.Hex(),
-			user:  us,
-			err:   nil,
-			times: 1,
+			name:       "Success",
+			id:         id.Hex(),
+			user:       us,
+			err:        nil,
+			times:      1,
+			timesPosts: 1,
 		},
 		{
 			name:  "Failure",
@@ -712,14 +1093,82 @@ func TestUserService_Delete(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
+			var deletedAt time.Time
 			m.
 				EXPECT().
-				Delete(gomock.Any(), test.user.Role, id).
-				Return(test.err).
+				GetByID(gomock.Any(), id).
+				Return(us, nil).
+				Times(test.times)
+			m.
+				EXPECT().
+				Delete(gomock.Any(), test.user.Role, id, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ user.Role, _ primitive.ObjectID, at time.Time) error {
+					deletedAt = at
+					return test.err
+				}).
 				Times(test.times)
+			pm.
+				EXPECT().
+				DeleteAllForUser(gomock.Any(), id, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ primitive.ObjectID, at time.Time) ([]primitive.ObjectID, error) {
+					// The posts are deleted with the same time, to be restored with the user.
+					assert.Equal(t, deletedAt, at)
+					return []primitive.ObjectID{originalID, purgedID}, nil
+				}).
+				Times(test.timesPosts)
+			pm.
+				EXPECT().
+				IncReposts(gomock.Any(), originalID, -1).
+				Return(nil).
+				Times(test.timesPosts)
+			pm.
+				EXPECT().
+				IncReposts(gomock.Any(), purgedID, -1).
+				Return(response.ErrorNotFound).
+				Times(test.timesPosts)
+			m.
+				EXPECT().
+				HideReferences(gomock.Any(), id).
+				Return(nil).
+				Times(test.timesPosts)
+			rm.
+				EXPECT().
+				HideAllForUser(gomock.Any(), id, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ primitive.ObjectID, at time.Time) ([]reaction.Reaction, error) {
+					// The reactions are hidden with the same time, to be restored with the user.
+					assert.Equal(t, deletedAt, at)
+					return reactions, nil
+				}).
+				Times(test.timesPosts)
+			pm.
+				EXPECT().
+				IncReactions(gomock.Any(), postID, map[reaction.Type]int{reaction.Like: -1}).
+				Return(nil).
+				Times(test.timesPosts)
+			cm.
+				EXPECT().
+				IncReactions(gomock.Any(), commentID, map[reaction.Type]int{reaction.Sad: -1}).
+				Return(response.ErrorNotFound).
+				Times(test.timesPosts)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					// The deleted user is kept in the entry, without its password.
+					assert.Equal(t, audit.DeleteUser, e.Action)
+					assert.Equal(t, "user@example.com", e.Before["email"])
+					assert.NotContains(t, e.Before, "password")
+					assert.Nil(t, e.After)
+					return nil
+				}).
+				Times(test.timesPosts)
 
 			s := UserService{
 				repository: m,
+				posts:      pm,
+				comments:   cm,
+				reactions:  rm,
+				auditor:    am,
 				log:        l,
 			}
 
@@ -729,12 +1178,133 @@ func TestUserService_Delete(t *testing.T) {
 	}
 }
 
+func TestUserService_Restore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	m := mock.NewMockRepository(ctrl)
+	pm := pmock.NewMockRepository(ctrl)
+	cm := cmock.NewMockRepository(ctrl)
+	rm := rmock.NewMockRepository(ctrl)
+	am := amock.NewMockRecorder(ctrl)
+	id := primitive.NewObjectID()
+	originalID := primitive.NewObjectID()
+	postID := primitive.NewObjectID()
+	commentID := primitive.NewObjectID()
+	reactions := []reaction.Reaction{
+		{Target: reaction.Post, TargetID: postID, UserID: id, Type: reaction.Like},
+		{Target: reaction.Comment, TargetID: commentID, UserID: id, Type: reaction.Sad},
+	}
+	us := user.User{
+		ID:        id,
+		Email:     "user@example.com",
+		FirstName: "user",
+		Role:      user.Client,
+	}
+	deletedAt := time.Now()
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name       string
+		id         string
+		err        error
+		times      int
+		timesPosts int
+	}{
+		{
+			name:       "Success",
+			id:         id.Hex(),
+			times:      1,
+			timesPosts: 1,
+		},
+		{
+			name:  "Failure not deleted",
+			id:    id.Hex(),
+			err:   response.ErrorNotFound,
+			times: 1,
+		},
+		{
+			name: "With invalid id",
+			id:   "123",
+			err:  response.ErrInvalidID,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.
+				EXPECT().
+				Restore(gomock.Any(), user.Admin, id).
+				Return(deletedAt, test.err).
+				Times(test.times)
+			pm.
+				EXPECT().
+				RestoreAllForUser(gomock.Any(), id, deletedAt).
+				Return([]primitive.ObjectID{originalID}, nil).
+				Times(test.timesPosts)
+			pm.
+				EXPECT().
+				IncReposts(gomock.Any(), originalID, 1).
+				Return(nil).
+				Times(test.timesPosts)
+			m.
+				EXPECT().
+				RestoreReferences(gomock.Any(), id).
+				Return(nil).
+				Times(test.timesPosts)
+			rm.
+				EXPECT().
+				RestoreAllForUser(gomock.Any(), id, deletedAt).
+				Return(reactions, nil).
+				Times(test.timesPosts)
+			pm.
+				EXPECT().
+				IncReactions(gomock.Any(), postID, map[reaction.Type]int{reaction.Like: 1}).
+				Return(nil).
+				Times(test.timesPosts)
+			cm.
+				EXPECT().
+				IncReactions(gomock.Any(), commentID, map[reaction.Type]int{reaction.Sad: 1}).
+				Return(nil).
+				Times(test.timesPosts)
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), id).
+				Return(us, nil).
+				Times(test.timesPosts)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				Return(nil).
+				Times(test.timesPosts)
+
+			s := UserService{
+				repository: m,
+				posts:      pm,
+				comments:   cm,
+				reactions:  rm,
+				auditor:    am,
+				log:        l,
+			}
+
+			u, err := s.Restore(ctx, user.Admin, test.id)
+			assert.Equal(t, test.err, err)
+			if test.err == nil {
+				assert.Equal(t, us, u)
+			}
+		})
+	}
+}
+
 func TestUserService_Update(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	defer ctrl.Finish()
 
 	m := mock.NewMockRepository(ctrl)
+	am := amock.NewMockRecorder(ctrl)
 	id1 := primitive.NewObjectID()
 	id2 := primitive.NewObjectID()
 	cliendUser := user.User{
@@ -759,13 +1329,14 @@ func TestUserService_Update(t *testing.T) {
 	l := logger.NewMock()
 
 	tests := []struct {
-		name    string
-		id      string
-		user    user.User
-		rUser   user.User
-		err     error
-		times   int
-		IDtimes int
+		name     string
+		id       string
+		user     user.User
+		rUser    user.User
+		err      error
+		times    int
+		IDtimes  int
+		timesRec int
 	}{
 		{
 			name:    "Success cliend",
@@ -777,13 +1348,14 @@ func TestUserService_Update(t *testing.T) {
 			IDtimes: 1,
 		},
 		{
-			name:    "Success admin",
-			id:      id1.Hex(),
-			user:    adminUser,
-			rUser:   adminUser,
-			err:     nil,
-			times:   1,
-			IDtimes: 1,
+			name:     "Success admin",
+			id:       id1.Hex(),
+			user:     adminUser,
+			rUser:    adminUser,
+			err:      nil,
+			times:    1,
+			IDtimes:  2,
+			timesRec: 1,
 		},
 		{
 			name:    "Failure cliend id unauthorized",
@@ -819,7 +1391,7 @@ func TestUserService_Update(t *testing.T) {
 			rUser:   user.User{},
 			err:     response.ErrorInternalServerError,
 			times:   1,
-			IDtimes: 0,
+			IDtimes: 1,
 		},
 	}
 
@@ -827,7 +1399,7 @@ func TestUserService_Update(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			m.
 				EXPECT().
-				Update(gomock.Any(), test.user.Role, gomock.Any(), &test.user).
+				Update(gomock.Any(), test.user.Role, gomock.Any(), &test.user, patch.AnyVersion).
 				Return(test.err).
 				Times(test.times)
 			m.
@@ -835,18 +1407,122 @@ func TestUserService_Update(t *testing.T) {
 				GetByID(gomock.Any(), gomock.Any()).
 				Return(test.user, nil).
 				Times(test.IDtimes)
+			am.
+				EXPECT().
+				Record(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, e audit.Entry) error {
+					assert.Equal(t, audit.UpdateUser, e.Action)
+					assert.NotNil(t, e.Before)
+					assert.NotNil(t, e.After)
+					return nil
+				}).
+				Times(test.timesRec)
 
 			s := UserService{
 				repository: m,
+				auditor:    am,
 				log:        l,
 			}
 
-			u, err := s.Update(ctx, test.id, test.user.ID.Hex(), test.user.Role, &test.user)
+			u, err := s.Update(ctx, test.id, test.user.ID.Hex(), test.user.Role, &test.user, patch.AnyVersion)
 			assert.Equal(t, err, test.err)
 			assert.Equal(t, u, test.rUser)
 		})
 	}
 }
+func TestUserService_Patch(t *testing.T) {
+	id := primitive.NewObjectID()
+	stored := user.User{
+		ID:           id,
+		Email:        "user@example.com",
+		HashPassword: []byte("hash"),
+		FirstName:    "user",
+		LastName:     "test",
+		City:         "Caracas",
+		Bio:          "before",
+		Role:         user.Client,
+		Version:      2,
+	}
+	ctx := context.Background()
+	l := logger.NewMock()
+
+	tests := []struct {
+		name        string
+		id          string
+		patch       string
+		version     int64
+		err         error
+		timesGet    int
+		timesUpdate int
+	}{
+		{
+			name:        "Success",
+			id:          id.Hex(),
+			patch:       `{"bio":"after","city":null}`,
+			version:     2,
+			timesGet:    2,
+			timesUpdate: 1,
+		},
+		{
+			name:     "Failure stale version",
+			id:       id.Hex(),
+			patch:    `{"bio":"after"}`,
+			version:  1,
+			err:      response.ErrPreconditionFailed,
+			timesGet: 1,
+		},
+		{
+			name:     "Failure field not editable",
+			id:       id.Hex(),
+			patch:    `{"role":2}`,
+			version:  patch.AnyVersion,
+			err:      &patch.FieldError{Field: "role", Reason: "cannot be changed"},
+			timesGet: 1,
+		},
+		{
+			name:    "Failure other user",
+			id:      primitive.NewObjectID().Hex(),
+			patch:   `{"bio":"after"}`,
+			version: patch.AnyVersion,
+			err:     response.ErrorUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			m := mock.NewMockRepository(ctrl)
+
+			m.
+				EXPECT().
+				GetByID(gomock.Any(), id).
+				Return(stored, nil).
+				Times(test.timesGet)
+			m.
+				EXPECT().
+				Update(gomock.Any(), user.Client, id, gomock.Any(), test.version).
+				DoAndReturn(func(_ context.Context, _ user.Role, _ primitive.ObjectID, u *user.User, _ int64) error {
+					// Only the fields in the patch change.
+					assert.Equal(t, "after", u.Bio)
+					assert.Equal(t, "", u.City)
+					assert.Equal(t, "user", u.FirstName)
+					assert.Equal(t, "test", u.LastName)
+					return nil
+				}).
+				Times(test.timesUpdate)
+
+			s := UserService{
+				repository: m,
+				log:        l,
+			}
+
+			_, err := s.Patch(ctx, test.id, id.Hex(), user.Client, []byte(test.patch), test.version)
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
+
 func TestUserService_FirebaseAuth(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
@@ -857,6 +1533,7 @@ func TestUserService_FirebaseAuth(t *testing.T) {
 
 	uid := "2134gh"
 	us := user.User{
+		UID:       uid,
 		Email:     "user@example.com",
 		FirstName: "user",
 		LastName:  "test",
@@ -0,0 +1,92 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/response"
+)
+
+// Errors.
+var (
+	ErrDeactivated       = response.NewError(response.Forbidden, "account deactivated")
+	ErrSuspended         = response.NewError(response.Forbidden, "account suspended")
+	ErrInvalidSuspension = response.NewError(response.Invalid, "invalid suspension")
+)
+
+// Suspension actions of the records.
+const (
+	Suspend   SuspensionAction = "suspend"
+	Unsuspend SuspensionAction = "unsuspend"
+)
+
+// SuspensionAction is what an admin did to the suspension of a user.
+type SuspensionAction string
+
+// SuspensionRecord is the audit of an admin suspending a user or lifting
+// its suspension.
+type SuspensionRecord struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	ActorID   primitive.ObjectID `json:"actor_id,omitempty" bson:"actor_id,omitempty"`
+	Action    SuspensionAction   `json:"action,omitempty" bson:"action,omitempty"`
+	Reason    string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	Until     *time.Time         `json:"until,omitempty" bson:"until,omitempty"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// Suspension is a ban of a user by an admin, until a time or for good
+// when Until is nil.
+type Suspension struct {
+	Reason    string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	Until     *time.Time         `json:"until,omitempty" bson:"until,omitempty"`
+	By        primitive.ObjectID `json:"by,omitempty" bson:"by,omitempty"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
+
+// Validate check the suspension has a reason and ends in the future.
+func (s Suspension) Validate(now time.Time) error {
+	if s.Reason == "" {
+		return ErrInvalidSuspension
+	}
+
+	if s.Until != nil && !s.Until.After(now) {
+		return ErrInvalidSuspension
+	}
+
+	return nil
+}
+
+// IsSuspended reports whether the user is suspended at now.
+func (u User) IsSuspended(now time.Time) bool {
+	if u.Suspension == nil {
+		return false
+	}
+
+	return u.Suspension.Until == nil || u.Suspension.Until.After(now)
+}
+
+// DeactivatedItself reports whether the user deactivated itself, a
+// deactivation without a record of who did it counts as its own.
+func (u User) DeactivatedItself() bool {
+	return u.DeactivatedBy.IsZero() || u.DeactivatedBy == u.ID
+}
+
+// CheckStatus returns an error wrapping ErrSuspended or ErrDeactivated
+// when the user can't use the API at now.
+func (u User) CheckStatus(now time.Time) error {
+	if u.IsSuspended(now) {
+		if u.Suspension.Until == nil {
+			return fmt.Errorf("%w: %s", ErrSuspended, u.Suspension.Reason)
+		}
+		return fmt.Errorf("%w until %s: %s", ErrSuspended, u.Suspension.Until.Format(time.RFC3339), u.Suspension.Reason)
+	}
+
+	if !u.Active {
+		return ErrDeactivated
+	}
+
+	return nil
+}
@@ -6,6 +6,18 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Zucke/social_prove/pkg/validation"
+)
+
+// Limits of the user fields, bcrypt ignores the passwords after 72 bytes.
+const (
+	MinPasswordLength = 6
+	MaxPasswordLength = 72
+	MaxNameLength     = 50
+	MaxPlaceLength    = 100
+	MaxBioLength      = 500
+	MaxURLLength      = 2048
 )
 
 // Role to the user on the system.
@@ -33,13 +45,22 @@ type User struct {
 	Bio            string               `json:"bio,omitempty" bson:"bio,omitempty"`
 	Picture        string               `json:"picture,omitempty" bson:"picture,omitempty"`
 	Following      []primitive.ObjectID `json:"following,omitempty" bson:"following,omitempty"`
+	HiddenFollows  []primitive.ObjectID `json:"-" bson:"hidden_following,omitempty"`
 	Role           Role                 `json:"role,omitempty" bson:"role,omitempty"`
 	Active         bool                 `json:"active" bson:"active"`
+	DeactivatedBy  primitive.ObjectID   `json:"-" bson:"deactivated_by,omitempty"`
+	Suspension     *Suspension          `json:"suspension,omitempty" bson:"suspension,omitempty"`
 	NotificationID string               `json:"notification_id,omitempty" bson:"notification_id,omitempty"`
+	DeletedAt      *time.Time           `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 	CreatedAt      time.Time            `json:"created_at,omitempty" bson:"created_at,omitempty"`
 	UpdatedAt      time.Time            `json:"updated_at,omitempty" bson:"updated_at,omitempty"`
+	Version        int64                `json:"version,omitempty" bson:"version,omitempty"`
 }
 
+// Editable are the JSON names of the fields changed by an update, the only
+// ones a patch can change.
+var Editable = []string{"first_name", "last_name", "country", "state", "city", "bio", "picture"}
+
 // ComparePassword compare the HashPassword with a raw password and return true if they are the same
 func (u User) ComparePassword(password string) bool {
 	saltedPassword := getSatlForPassword(password)
@@ -59,6 +80,62 @@ func (u *User) EncryptPassword() (err error) {
 	return nil
 }
 
+// Validate check the fields of a new user. The users signed up by
+// Firebase have no password, the others need one.
+func (u User) Validate() error {
+	var v validation.Validator
+
+	if v.Required("email", u.Email) {
+		v.Email("email", u.Email)
+	}
+
+	if u.UID == "" || u.Password != "" {
+		if v.Required("password", u.Password) {
+			v.Length("password", u.Password, MinPasswordLength, MaxPasswordLength)
+		}
+	}
+
+	u.validateProfile(&v)
+
+	return v.Err()
+}
+
+// ValidateProfile check the fields changed by an update.
+func (u User) ValidateProfile() error {
+	var v validation.Validator
+	u.validateProfile(&v)
+
+	return v.Err()
+}
+
+// ValidateCredentials check the fields of a login.
+func (u User) ValidateCredentials() error {
+	var v validation.Validator
+
+	if v.Required("email", u.Email) {
+		v.Email("email", u.Email)
+	}
+	v.Required("password", u.Password)
+
+	return v.Err()
+}
+
+func (u User) validateProfile(v *validation.Validator) {
+	v.Length("first_name", u.FirstName, 0, MaxNameLength)
+	v.Length("last_name", u.LastName, 0, MaxNameLength)
+	v.Length("state", u.State, 0, MaxPlaceLength)
+	v.Length("city", u.City, 0, MaxPlaceLength)
+	v.Length("bio", u.Bio, 0, MaxBioLength)
+
+	if u.Country != "" {
+		v.Country("country", u.Country)
+	}
+
+	if u.Picture != "" && v.Length("picture", u.Picture, 0, MaxURLLength) {
+		v.URL("picture", u.Picture)
+	}
+}
+
 // ValidateEmail confirm valid email format.
 func (u User) ValidateEmail() bool {
 	const pattern = "^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$"
@@ -1,9 +1,15 @@
 package user
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/Zucke/social_prove/pkg/validation"
 )
 
 func TestEncryptPassword(t *testing.T) {
@@ -74,3 +80,125 @@ func TestValidateEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   User
+		fields []string
+	}{
+		{
+			name: "valid",
+			user: User{Email: "user@example.com", Password: "123456", Country: "VE", Picture: "https://example.com/a.png"},
+		},
+		{
+			name: "firebase user without password",
+			user: User{Email: "user@example.com", UID: "abc"},
+		},
+		{
+			name:   "without email nor password",
+			user:   User{},
+			fields: []string{"email", "password"},
+		},
+		{
+			name:   "short password",
+			user:   User{Email: "user@example.com", Password: "123"},
+			fields: []string{"password"},
+		},
+		{
+			name: "bad profile",
+			user: User{
+				Email:     "user@example.com",
+				Password:  "123456",
+				FirstName: strings.Repeat("a", MaxNameLength+1),
+				Country:   "Venezuela",
+				Picture:   "ftp://example.com/a.png",
+			},
+			fields: []string{"first_name", "country", "picture"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.user.Validate()
+			if len(test.fields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var errs validation.Errors
+			assert.True(t, errors.As(err, &errs))
+
+			fields := make([]string, 0, len(errs))
+			for _, fe := range errs {
+				fields = append(fields, fe.Field)
+			}
+			assert.Equal(t, test.fields, fields)
+		})
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		user User
+		err  error
+	}{
+		{
+			name: "active",
+			user: User{Active: true},
+		},
+		{
+			name: "deactivated",
+			user: User{Active: false},
+			err:  ErrDeactivated,
+		},
+		{
+			name: "suspended for good",
+			user: User{Active: true, Suspension: &Suspension{Reason: "spam"}},
+			err:  ErrSuspended,
+		},
+		{
+			name: "suspended until later",
+			user: User{Active: true, Suspension: &Suspension{Reason: "spam", Until: &future}},
+			err:  ErrSuspended,
+		},
+		{
+			name: "suspension expired",
+			user: User{Active: true, Suspension: &Suspension{Reason: "spam", Until: &past}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.user.CheckStatus(now)
+			assert.True(t, errors.Is(err, test.err), "got %v", err)
+			if test.err == nil {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDeactivatedItself(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	assert.True(t, User{ID: id, DeactivatedBy: id}.DeactivatedItself())
+	assert.True(t, User{ID: id}.DeactivatedItself())
+	assert.False(t, User{ID: id, DeactivatedBy: primitive.NewObjectID()}.DeactivatedItself())
+}
+
+func TestSuspensionValidate(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.NoError(t, Suspension{Reason: "spam"}.Validate(now))
+	assert.NoError(t, Suspension{Reason: "spam", Until: &future}.Validate(now))
+	assert.Equal(t, ErrInvalidSuspension, Suspension{}.Validate(now))
+	assert.Equal(t, ErrInvalidSuspension, Suspension{Reason: "spam", Until: &past}.Validate(now))
+}
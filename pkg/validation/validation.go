@@ -0,0 +1,198 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Code is the machine-readable reason of a failure.
+type Code string
+
+// Failure codes.
+const (
+	Malformed     Code = "malformed"
+	InvalidType   Code = "invalid_type"
+	Required      Code = "required"
+	TooShort      Code = "too_short"
+	TooLong       Code = "too_long"
+	InvalidFormat Code = "invalid_format"
+	NotAllowed    Code = "not_allowed"
+)
+
+var emailPattern = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+
+// FieldError is the failure of a field of a request, Field is its JSON
+// name, empty when the failure is about the whole body.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors are the failures of a request.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		if fe.Field == "" {
+			msgs = append(msgs, fe.Message)
+			continue
+		}
+		msgs = append(msgs, fe.Field+": "+fe.Message)
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Validator collects the failures of the fields of a request.
+type Validator struct {
+	errs Errors
+}
+
+// Add add a failure of a field.
+func (v *Validator) Add(field string, code Code, message string) {
+	v.errs = append(v.errs, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Required check the value isn't blank.
+func (v *Validator) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, Required, "is required")
+		return false
+	}
+
+	return true
+}
+
+// Length check the value has between min and max characters, a max of 0
+// is no limit.
+func (v *Validator) Length(field, value string, min, max int) bool {
+	n := utf8.RuneCountInString(value)
+	if n < min {
+		v.Add(field, TooShort, fmt.Sprintf("must have at least %d characters", min))
+		return false
+	}
+
+	if max > 0 && n > max {
+		v.Add(field, TooLong, fmt.Sprintf("must have at most %d characters", max))
+		return false
+	}
+
+	return true
+}
+
+// Email check the value is an email address.
+func (v *Validator) Email(field, value string) bool {
+	if !emailPattern.MatchString(value) {
+		v.Add(field, InvalidFormat, "must be an email address")
+		return false
+	}
+
+	return true
+}
+
+// URL check the value is an absolute http or https URL.
+func (v *Validator) URL(field, value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.Add(field, InvalidFormat, "must be an http or https URL")
+		return false
+	}
+
+	return true
+}
+
+// Country check the value is an allowed ISO 3166-1 alpha-2 country code.
+func (v *Validator) Country(field, value string) bool {
+	if !countries[value] {
+		v.Add(field, NotAllowed, "must be an ISO 3166-1 alpha-2 country code")
+		return false
+	}
+
+	return true
+}
+
+// OneOf check the value is one of the allowed.
+func (v *Validator) OneOf(field, value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+
+	v.Add(field, NotAllowed, "must be one of "+strings.Join(allowed, ", "))
+	return false
+}
+
+// Err returns the failures as Errors, nil without failures.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+
+	return v.errs
+}
+
+// Decode decode the JSON body of a request into v, a body that can't be
+// decoded is reported as Errors and one that can't be read with the error
+// of the reader, like the limit of its size.
+func Decode(r io.Reader, v interface{}) error {
+	br := &bodyReader{Reader: r}
+	err := json.NewDecoder(br).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return Errors{{Code: Required, Message: "the body is required"}}
+	}
+
+	return br.decodeError(err)
+}
+
+// DecodeOptional is Decode for the requests whose body can be empty.
+func DecodeOptional(r io.Reader, v interface{}) error {
+	br := &bodyReader{Reader: r}
+	err := json.NewDecoder(br).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	return br.decodeError(err)
+}
+
+// bodyReader keeps the error of the reader to tell it from the errors of
+// the JSON.
+type bodyReader struct {
+	io.Reader
+	err error
+}
+
+func (r *bodyReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+
+	return n, err
+}
+
+func (r *bodyReader) decodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return Errors{{Field: typeErr.Field, Code: InvalidType, Message: "cannot be a " + typeErr.Value}}
+	}
+
+	return Errors{{Code: Malformed, Message: "the body is not valid JSON"}}
+}
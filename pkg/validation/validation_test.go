@@ -0,0 +1,157 @@
+package validation
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(v *Validator) bool
+		want  Errors
+	}{
+		{
+			name:  "required",
+			check: func(v *Validator) bool { return v.Required("email", " ") },
+			want:  Errors{{Field: "email", Code: Required, Message: "is required"}},
+		},
+		{
+			name:  "too short",
+			check: func(v *Validator) bool { return v.Length("password", "123", 6, 72) },
+			want:  Errors{{Field: "password", Code: TooShort, Message: "must have at least 6 characters"}},
+		},
+		{
+			name:  "too long",
+			check: func(v *Validator) bool { return v.Length("bio", "ñññ", 0, 2) },
+			want:  Errors{{Field: "bio", Code: TooLong, Message: "must have at most 2 characters"}},
+		},
+		{
+			name:  "length counts characters",
+			check: func(v *Validator) bool { return v.Length("bio", "ñññ", 0, 3) },
+		},
+		{
+			name:  "no limit",
+			check: func(v *Validator) bool { return v.Length("bio", strings.Repeat("a", 5000), 0, 0) },
+		},
+		{
+			name:  "email",
+			check: func(v *Validator) bool { return v.Email("email", "user.example.com") },
+			want:  Errors{{Field: "email", Code: InvalidFormat, Message: "must be an email address"}},
+		},
+		{
+			name:  "url",
+			check: func(v *Validator) bool { return v.URL("picture", "https://example.com/a.png") },
+		},
+		{
+			name:  "url without scheme",
+			check: func(v *Validator) bool { return v.URL("picture", "example.com/a.png") },
+			want:  Errors{{Field: "picture", Code: InvalidFormat, Message: "must be an http or https URL"}},
+		},
+		{
+			name:  "url not http",
+			check: func(v *Validator) bool { return v.URL("picture", "javascript:alert(1)") },
+			want:  Errors{{Field: "picture", Code: InvalidFormat, Message: "must be an http or https URL"}},
+		},
+		{
+			name:  "country",
+			check: func(v *Validator) bool { return v.Country("country", "VE") },
+		},
+		{
+			name:  "country not allowed",
+			check: func(v *Validator) bool { return v.Country("country", "Venezuela") },
+			want:  Errors{{Field: "country", Code: NotAllowed, Message: "must be an ISO 3166-1 alpha-2 country code"}},
+		},
+		{
+			name:  "one of",
+			check: func(v *Validator) bool { return v.OneOf("status", "hidden", "draft", "published") },
+			want:  Errors{{Field: "status", Code: NotAllowed, Message: "must be one of draft, published"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var v Validator
+
+			ok := test.check(&v)
+			assert.Equal(t, test.want == nil, ok)
+
+			if test.want == nil {
+				assert.NoError(t, v.Err())
+				return
+			}
+			assert.Equal(t, test.want, v.Err())
+		})
+	}
+}
+
+var errRead = errors.New("read failed")
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestDecode(t *testing.T) {
+	var body struct {
+		Email string `json:"email"`
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		reader   io.Reader
+		optional bool
+		want     error
+	}{
+		{
+			name: "valid",
+			body: `{"email":"user@example.com"}`,
+		},
+		{
+			name: "empty",
+			want: Errors{{Code: Required, Message: "the body is required"}},
+		},
+		{
+			name:     "empty optional",
+			optional: true,
+		},
+		{
+			name: "malformed",
+			body: `{"email":`,
+			want: Errors{{Code: Malformed, Message: "the body is not valid JSON"}},
+		},
+		{
+			name:     "wrong type",
+			body:     `{"email":true}`,
+			optional: true,
+			want:     Errors{{Field: "email", Code: InvalidType, Message: "cannot be a bool"}},
+		},
+		{
+			name:   "read error",
+			reader: io.MultiReader(strings.NewReader(`{"email":`), errReader{errRead}),
+			want:   errRead,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := test.reader
+			if r == nil {
+				r = strings.NewReader(test.body)
+			}
+
+			var err error
+			if test.optional {
+				err = DecodeOptional(r, &body)
+			} else {
+				err = Decode(r, &body)
+			}
+
+			assert.Equal(t, test.want, err)
+		})
+	}
+}